@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	trashUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/trash_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/migrations"
 	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres"
+	postgresRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres_repository"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/cli"
 	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/job"
 	"log"
 	"net/http"
+	"os"
 
 	_ "github.com/OddEer0/vk-filmoteka/docs"
 	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
@@ -17,11 +25,30 @@ import (
 // @description This is a sample HTTP package with Swagger annotations.
 func main() {
 	cfg := config.MustLoad()
+	if len(os.Args) > 1 {
+		if err := cli.Run(context.Background(), cfg, os.Args[1:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	db, err := postgres.ConnectPg(cfg)
 	if err != nil {
 		log.Fatal("Error connect postgres", err.Error())
 	}
-	appHandler := httpv1.NewAppHandler(db)
+	if cfg.AutoMigrate {
+		migrator, err := migrations.New(db)
+		if err != nil {
+			log.Fatal("Error load migrations", err.Error())
+		}
+		if _, err = migrator.Up(context.Background()); err != nil {
+			log.Fatal("Error apply migrations", err.Error())
+		}
+	}
+	if err = postgres.SeedAdmin(context.Background(), db, cfg); err != nil {
+		log.Fatal("Error seed admin", err.Error())
+	}
+	appHandler := httpv1.NewAppHandler(db, cfg)
 	logger := slogger.SetupLogger(cfg.Env)
 	logger.Info("Logger setup")
 	router := appRouter.NewAppRouter(logger, appHandler)
@@ -29,6 +56,10 @@ func main() {
 	initSwagger(router)
 	logger.Info("swagger setup")
 
+	audit := auditService.New(postgresRepository.NewAuditRepository(db), postgresRepository.NewTxManager(db))
+	trashUsecase := trashUseCase.New(postgresRepository.NewFilmRepository(db, cfg.HonorManualRate), postgresRepository.NewActorRepository(db, cfg.HonorManualRate), audit)
+	go job.TrashRetention(context.Background(), logger, trashUsecase, cfg.Trash)
+
 	server := http.Server{Addr: cfg.Server.Address, Handler: router}
 	if err := server.ListenAndServe(); err != nil {
 		log.Fatal("Error starting server")
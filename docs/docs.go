@@ -41,11 +41,71 @@ const docTemplate = `{
                         "name": "page-count",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "токен next/prev из прошлого ответа, вместо page. Сортировка берется из токена, фильтры нужно передать снова",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Вернуть вместе со связями (film)",
                         "name": "connection",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поиск по имени, допускает опечатки",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "пол (male, female)",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "год рождения от",
+                        "name": "birth-year-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "год рождения до",
+                        "name": "birth-year-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "возраст от",
+                        "name": "age-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "возраст до",
+                        "name": "age-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id фильма, в котором участвовал актер",
+                        "name": "film",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поле сортировки (name, birthday), по умолчанию id",
+                        "name": "order-field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "направление сортировки (asc, desc)",
+                        "name": "order-by",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -55,6 +115,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/appDto.ActorGetByQueryResult"
                         }
                     },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
                     "404": {
                         "description": "Ошибка 404",
                         "schema": {
@@ -84,6 +150,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.Actor"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -98,6 +170,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/appErrors.ResponseError"
                         }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
                     }
                 }
             },
@@ -136,11 +214,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/appErrors.ResponseError"
                         }
+                    },
+                    "409": {
+                        "description": "Внешний идентификатор уже у другой записи",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
                     }
                 }
             },
             "delete": {
-                "description": "Доступно только админам, ничего ответом не возвращает",
+                "description": "Доступно только админам, ничего ответом не возвращает. Актер переносится в корзину, его можно восстановить",
                 "consumes": [
                     "application/json"
                 ],
@@ -168,11 +252,92 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "patch": {
+                "description": "Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, для обязательных полей это ошибка 422. Проверяется только итоговый актер",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Частичное обновление актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля актера",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Actor"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные обновленного актера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Actor"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Ошибка 409",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "Ошибка 415",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
             }
         },
         "/http/v1/actor/add-film": {
             "post": {
-                "description": "Доступно только админам",
+                "description": "Доступно только админам. Роль (actor, director, writer, producer, composer), персонаж и порядок в титрах передаются в credits, filmIds создает роль actor",
                 "consumes": [
                     "application/json"
                 ],
@@ -182,7 +347,7 @@ const docTemplate = `{
                 "tags": [
                     "actor"
                 ],
-                "summary": "Обновление актера [Админы]",
+                "summary": "Добавление актеру участия в фильмах [Админы]",
                 "parameters": [
                     {
                         "description": "Данные id для связывания актера и фильма",
@@ -204,6 +369,76 @@ const docTemplate = `{
                 }
             }
         },
+        "/http/v1/audit": {
+            "get": {
+                "description": "Доступно только админам, от новых записей к старым. diff - изменившиеся поля {\"путь\": {\"before\", \"after\"}}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Журнал изменений [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id пользователя",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "тип сущности (film, actor, genre, review, trash)",
+                        "name": "entity-type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id сущности",
+                        "name": "entity-id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "начиная с (RFC3339 или 2000-12-31)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "заканчивая (RFC3339 или 2000-12-31 включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во записей на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Записи журнала",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.AuditGetByQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
         "/http/v1/auth/login": {
             "post": {
                 "description": "Ответом при успешном Логине получаем свои данные",
@@ -364,10 +599,76 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Вернуть вместе со связями (film)",
+                        "description": "токен next/prev из прошлого ответа, вместо page. Сортировка берется из токена, фильтры нужно передать снова",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Вернуть вместе со связями (actor, genre)",
                         "name": "connection",
                         "in": "query"
                     },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "id жанров для фильтра",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any - хотя бы один жанр, all - все жанры (по умолчанию any)",
+                        "name": "genre-match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "id актеров для фильтра",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any - хотя бы один актер, all - все актеры (по умолчанию any)",
+                        "name": "actor-match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "дата выхода от, год (1990) либо дата (1990-05-20)",
+                        "name": "release-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "дата выхода до включительно, год (2000) либо дата (2000-12-31)",
+                        "name": "release-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "рейтинг от (0-10)",
+                        "name": "rate-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "рейтинг до (0-10)",
+                        "name": "rate-to",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "asc либо desc",
@@ -388,6 +689,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/appDto.FilmGetByQueryResult"
                         }
                     },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
                     "404": {
                         "description": "Ошибка 404",
                         "schema": {
@@ -397,7 +704,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Доступно только админам",
+                "description": "Доступно только админам. manualRate без поля не меняется, null сбрасывает ручную оценку",
                 "consumes": [
                     "application/json"
                 ],
@@ -415,8 +722,14 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Film"
+                            "$ref": "#/definitions/dto.UpdateFilmBody"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -431,11 +744,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/appErrors.ResponseError"
                         }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Доступно только админам",
+                "description": "Доступно только админам. actorIds и actors сразу добавляют состав, фильм, новые актеры и связи сохраняются одной транзакцией",
                 "consumes": [
                     "application/json"
                 ],
@@ -473,7 +792,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Доступно только админам, ничего не возвоащает",
+                "description": "Доступно только админам, ничего не возвоащает. Фильм переносится в корзину, его можно восстановить",
                 "consumes": [
                     "application/json"
                 ],
@@ -501,13 +820,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/http/v1/film/search": {
-            "get": {
-                "description": "поиск по имени и имени актеров",
+            },
+            "patch": {
+                "description": "Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, поэтому {\"description\": null} очищает описание. Проверяется только итоговый фильм",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
@@ -515,20 +832,42 @@ const docTemplate = `{
                 "tags": [
                     "film"
                 ],
-                "summary": "Поиск фильма",
+                "summary": "Частичное обновление фильма [Админы]",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "параметр поиска",
-                        "name": "search",
-                        "in": "query"
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля фильма",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Film"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "получаемые фильмы",
+                        "description": "Данные обновленного фильма",
                         "schema": {
-                            "$ref": "#/definitions/appDto.FilmGetByQueryResult"
+                            "$ref": "#/definitions/model.Film"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
                         }
                     },
                     "404": {
@@ -536,86 +875,3852 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/appErrors.ResponseError"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "aggregate.ActorAggregate": {
-            "type": "object",
-            "properties": {
-                "actor": {
-                    "$ref": "#/definitions/model.Actor"
+                    },
+                    "409": {
+                        "description": "Ошибка 409",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "Ошибка 415",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/film/search": {
+            "get": {
+                "description": "полнотекстовый поиск по названию, описанию и именам актеров. Сначала фильмы с совпадением в названии, совпадения выделены тегом \u003cb\u003e",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Поиск фильма",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "параметр поиска",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "кол-во фильмов на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "получаемые фильмы",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.FilmGetByQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/film/{id}/reviews": {
+            "get": {
+                "description": "Только одобренные модератором рецензии, от новых к старым",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Рецензии фильма",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во рецензий на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рецензии",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.ReviewGetByQueryResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильм не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/genre": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genre"
+                ],
+                "summary": "Получение всех жанров",
+                "responses": {
+                    "200": {
+                        "description": "Жанры отсортированные по названию",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Genre"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Доступно только админам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genre"
+                ],
+                "summary": "Обновление жанра [Админы]",
+                "parameters": [
+                    {
+                        "description": "Данные жанра",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Genre"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные жанра",
+                        "schema": {
+                            "$ref": "#/definitions/model.Genre"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Доступно только админам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genre"
+                ],
+                "summary": "Создание жанра [Админы]",
+                "parameters": [
+                    {
+                        "description": "Данные жанра",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.CreateGenreUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные созданного жанра",
+                        "schema": {
+                            "$ref": "#/definitions/model.Genre"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Доступно только админам, ничего ответом не возвращает. Связи с фильмами удаляются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genre"
+                ],
+                "summary": "Удаление жанра [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id удаляемого жанра",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/genre/add-film": {
+            "post": {
+                "description": "Доступно только админам, повторная привязка игнорируется",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genre"
+                ],
+                "summary": "Привязка фильмов к жанру [Админы]",
+                "parameters": [
+                    {
+                        "description": "id жанра и фильмов",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FilmsToGenreDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/genre/remove-film": {
+            "post": {
+                "description": "Доступно только админам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genre"
+                ],
+                "summary": "Отвязка фильмов от жанра [Админы]",
+                "parameters": [
+                    {
+                        "description": "id жанра и фильмов",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FilmsToGenreDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/list": {
+            "get": {
+                "description": "Хочу посмотреть, просмотрено, избранное и собственные списки. Системные списки создаются автоматически",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Свои списки фильмов [Пользователи]",
+                "responses": {
+                    "200": {
+                        "description": "Списки пользователя",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.UserList"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Переименование и управление ссылкой: shared=true выдает shareSlug, false закрывает доступ",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Изменение списка [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "Новые данные списка",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.UpdateUserListUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Измененный список",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserList"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Чужой список",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Список не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Создание своего списка [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "Название списка и открыт ли он по ссылке",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.CreateUserListUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданный список",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserList"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Системные списки удалить нельзя, ничего ответом не возвращает",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Удаление своего списка [Пользователи]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id списка",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "403": {
+                        "description": "Чужой список",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Список не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Системный список",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/list/add-film": {
+            "post": {
+                "description": "Фильм добавляется в конец списка, повторное добавление ничего не меняет",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Добавление фильма в список [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "id списка и фильма",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.UserListFilmUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Список или фильм не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/list/films": {
+            "get": {
+                "description": "Фильмы в порядке, заданном пользователем",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Фильмы своего списка [Пользователи]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id списка",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во фильмов на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список и его фильмы",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.UserListFilmsResult"
+                        }
+                    },
+                    "403": {
+                        "description": "Чужой список",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Список не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/list/move-film": {
+            "post": {
+                "description": "Ставит фильм на позицию position (с нуля), позиция за концом списка означает последнее место",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Перемещение фильма в списке [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "id списка, фильма и новая позиция",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.MoveUserListFilmUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма нет в списке",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/list/remove-film": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Удаление фильма из списка [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "id списка и фильма",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.UserListFilmUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма нет в списке",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/list/shared/{slug}": {
+            "get": {
+                "description": "Доступно без авторизации, если владелец открыл список",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Открытый список по ссылке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "shareSlug списка",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во фильмов на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список и его фильмы",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.UserListFilmsResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Список не найден или закрыт",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/rating": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating"
+                ],
+                "summary": "Своя оценка фильма [Пользователи]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "filmId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Оценка пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rating"
+                        }
+                    },
+                    "404": {
+                        "description": "Оценка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Доступно авторизованным пользователям, меняет ранее поставленную оценку",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating"
+                ],
+                "summary": "Изменение оценки фильма [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "id фильма и новая оценка от 1 до 10",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RateFilmUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Оценка и новый рейтинг фильма",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RatingUseCaseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Оценка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Доступно авторизованным пользователям, rate и voteCount фильма пересчитываются сразу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating"
+                ],
+                "summary": "Оценка фильма [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "id фильма и оценка от 1 до 10",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RateFilmUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Оценка и новый рейтинг фильма",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RatingUseCaseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильм не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Фильм уже оценен",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Доступно авторизованным пользователям, удаляет свою оценку",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rating"
+                ],
+                "summary": "Отзыв оценки фильма [Пользователи]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "filmId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новый рейтинг фильма",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RatingUseCaseResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Оценка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/review": {
+            "put": {
+                "description": "Доступно автору рецензии, после изменения рецензия снова проходит модерацию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Изменение своей рецензии [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "Новые данные рецензии",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.UpdateReviewUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Измененная рецензия",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.ReviewAggregate"
+                        }
+                    },
+                    "403": {
+                        "description": "Чужая рецензия",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Рецензия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Доступно авторизованным пользователям, рецензия попадает в очередь модерации. Одна рецензия на фильм от пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Создание рецензии [Пользователи]",
+                "parameters": [
+                    {
+                        "description": "Данные рецензии",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.CreateReviewUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданная рецензия",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.ReviewAggregate"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильм не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Рецензия уже написана",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Доступно автору рецензии, ничего ответом не возвращает",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Удаление своей рецензии [Пользователи]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id рецензии",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "403": {
+                        "description": "Чужая рецензия",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Рецензия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/review/moderate": {
+            "post": {
+                "description": "Доступно только админам. approve - опубликовать, reject - отклонить, hide - скрыть опубликованную",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Модерация рецензии [Админы]",
+                "parameters": [
+                    {
+                        "description": "id рецензии, действие и причина",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.ModerateReviewUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рецензия с новым статусом",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.ReviewAggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Рецензия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Неизвестное действие",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/review/queue": {
+            "get": {
+                "description": "Доступно только админам, по умолчанию рецензии в статусе pending, от новых к старым",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Очередь модерации рецензий [Админы]",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "статусы (pending, approved, rejected, hidden)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "film",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во рецензий на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Рецензии",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.ReviewGetByQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v1/suggest": {
+            "get": {
+                "description": "фильмы и актеры вперемешку, устойчиво к опечаткам. Сортировка по схожести и популярности",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suggest"
+                ],
+                "summary": "Подсказки при вводе",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "введенное значение, минимум 2 символа",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "кол-во подсказок, от 1 до 20 (по умолчанию 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "подсказки",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.SuggestUseCaseResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/actors/{id}": {
+            "get": {
+                "description": "Актер вместе с фильмами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Получение актера по id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при совпадении 304 без тела",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные актера",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.ActorAggregate"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "версия записи"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Не изменился"
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Доступно только админам, актер передается целиком, id берется из пути",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Замена актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные актера",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Actor"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные обновленного актера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Actor"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Доступно только админам, ничего не возвращает. Актер переносится в корзину, его можно восстановить",
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Удаление актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, для обязательных полей это ошибка 422. Проверяется только итоговый актер",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Частичное обновление актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля актера",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Actor"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные обновленного актера",
+                        "schema": {
+                            "$ref": "#/definitions/model.Actor"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Ошибка 409",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "Ошибка 415",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/actors/{id}/films": {
+            "get": {
+                "description": "Принимает те же query что и список фильмов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Фильмы актера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во фильмов на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "токен next/prev из прошлого ответа, вместо page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc либо desc",
+                        "name": "order-by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поле по которому сортируют (rate, name, release_date)",
+                        "name": "order-field",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "фильмы актера",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.FilmGetByQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Доступно только админам. Все текущие участия актера заменяются переданными, повторы пропускаются. Пустой объект {} убирает актера из всех фильмов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Замена фильмов актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые участия актера",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetActorFilmsDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Актер с новыми фильмами",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.ActorAggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/actors/{id}/films/{filmId}": {
+            "delete": {
+                "description": "Доступно только админам, убирает все роли актера в фильме. Отсутствующая связь не ошибка",
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Удаление фильма у актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "filmId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/actors/{id}/revisions": {
+            "get": {
+                "description": "Доступно только админам, от новых к старым. snapshot - актер с титрами после правки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Ревизии актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во ревизий на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ревизии",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RevisionGetByQueryResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Актер не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/actors/{id}/revisions/diff": {
+            "get": {
+                "description": "Доступно только админам, поля которые поменялись от ревизии from к ревизии to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Разница между ревизиями актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Изменившиеся поля",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RevisionDiffResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Нет номеров ревизий",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ревизия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/actors/{id}/revisions/{number}": {
+            "get": {
+                "description": "Доступно только админам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Ревизия актера [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ревизия",
+                        "schema": {
+                            "$ref": "#/definitions/model.Revision"
+                        }
+                    },
+                    "404": {
+                        "description": "Ревизия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/actors/{id}/revisions/{number}/revert": {
+            "post": {
+                "description": "Доступно только админам. Поля и участия в фильмах возвращаются к ревизии, откат проходит валидацию и создает новую ревизию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Откат актера к ревизии [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Актер после отката",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.ActorAggregate"
+                        }
+                    },
+                    "404": {
+                        "description": "Актер или ревизия не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Фильмы ревизии удалены",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ревизия не проходит валидацию",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/export/actors": {
+            "get": {
+                "description": "Доступно только админам. Все актеры под фильтр без страниц, ответ отдается по мере чтения из базы.\nКолонки: id, name, gender, birthday - файл можно снова загрузить импортом",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Выгрузка актеров [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json (по умолчанию, один массив), ndjson или csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поиск по имени, допускает опечатки",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "пол (male, female)",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "год рождения от",
+                        "name": "birth-year-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "год рождения до",
+                        "name": "birth-year-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "возраст от",
+                        "name": "age-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "возраст до",
+                        "name": "age-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id фильма, в котором участвовал актер",
+                        "name": "film",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поле сортировки (name, birthday), по умолчанию id",
+                        "name": "order-field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "направление сортировки (asc, desc)",
+                        "name": "order-by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/export/credits": {
+            "get": {
+                "description": "Доступно только админам. Титры фильмов под фильтр, фильтры как у выгрузки фильмов.\nКолонки: film, filmName, filmRelease, actor, actorName, actorBirthday, role, character, billingOrder",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Выгрузка титров [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json (по умолчанию, один массив), ndjson или csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "id жанров для фильтра",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "id актеров для фильтра",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "дата выхода от, год (1990) либо дата (1990-05-20)",
+                        "name": "release-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "дата выхода до включительно, год (2000) либо дата (2000-12-31)",
+                        "name": "release-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "сортировка фильмов (rate, name, release_date)",
+                        "name": "order-field",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/export/films": {
+            "get": {
+                "description": "Доступно только админам. Все фильмы под фильтр без страниц, ответ отдается по мере чтения из базы.\nКолонки: id, name, description, release, rate - файл можно снова загрузить импортом",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Выгрузка фильмов [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json (по умолчанию, один массив), ndjson или csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "id жанров для фильтра",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any - хотя бы один жанр, all - все жанры (по умолчанию any)",
+                        "name": "genre-match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "id актеров для фильтра",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "any - хотя бы один актер, all - все актеры (по умолчанию any)",
+                        "name": "actor-match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "дата выхода от, год (1990) либо дата (1990-05-20)",
+                        "name": "release-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "дата выхода до включительно, год (2000) либо дата (2000-12-31)",
+                        "name": "release-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "рейтинг от (0-10)",
+                        "name": "rate-from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "рейтинг до (0-10)",
+                        "name": "rate-to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc либо desc",
+                        "name": "order-by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поле по которому сортируют (rate, name, release_date)",
+                        "name": "order-field",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Выгрузка",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/external/{source}/{externalId}/actor": {
+            "get": {
+                "description": "Поиск по идентификатору IMDb, TMDB или Кинопоиска, например /external/imdb/nm0000209/actor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Получение актера по внешнему идентификатору",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "источник: imdb, tmdb или kinopoisk",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "идентификатор в источнике",
+                        "name": "externalId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные актера",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.ActorAggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный источник",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/external/{source}/{externalId}/film": {
+            "get": {
+                "description": "Поиск по идентификатору IMDb, TMDB или Кинопоиска, например /external/imdb/tt0111161/film",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Получение фильма по внешнему идентификатору",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "источник: imdb, tmdb или kinopoisk",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "идентификатор в источнике",
+                        "name": "externalId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные фильма",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.FilmAggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный источник",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films": {
+            "post": {
+                "description": "Доступно только админам. Фильм, новые актеры из actors и связи с actorIds сохраняются одной транзакцией, при ошибке не создается ничего",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Создание фильма с составом [Админы]",
+                "parameters": [
+                    {
+                        "description": "Данные фильма и состав",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/appDto.CreateFilmUseCaseDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданный фильм с актерами",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.FilmAggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Внешний идентификатор уже у другой записи",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films/{id}": {
+            "get": {
+                "description": "Фильм вместе с актерами и жанрами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Получение фильма по id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при совпадении 304 без тела",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные фильма",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.FilmAggregate"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "версия записи и хеш оценки пользователей"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Не изменился"
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Доступно только админам, фильм передается целиком, id берется из пути. manualRate без поля не меняется, null сбрасывает ручную оценку",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Замена фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные фильма",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateFilmBody"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные обновленного фильма",
+                        "schema": {
+                            "$ref": "#/definitions/model.Film"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Доступно только админам, ничего не возвращает. Фильм переносится в корзину, его можно восстановить",
+                "tags": [
+                    "film"
+                ],
+                "summary": "Удаление фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, поэтому {\"description\": null} очищает описание. Проверяется только итоговый фильм",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Частичное обновление фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля фильма",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Film"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из прошлого ответа, при устаревшей версии 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные обновленного фильма",
+                        "schema": {
+                            "$ref": "#/definitions/model.Film"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Ошибка 409",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "412": {
+                        "description": "Ошибка 412",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "Ошибка 415",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films/{id}/actors": {
+            "get": {
+                "description": "Принимает те же query что и список актеров",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "actor"
+                ],
+                "summary": "Актеры фильма",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во актеров на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "токен next/prev из прошлого ответа, вместо page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc либо desc",
+                        "name": "order-by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "поле по которому сортируют (name, birthday)",
+                        "name": "order-field",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "актеры фильма",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.ActorGetByQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Доступно только админам. Все текущие связи фильма с актерами заменяются переданными, повторы пропускаются. Пустой объект {} убирает всех актеров",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "film"
+                ],
+                "summary": "Замена состава фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый состав фильма",
+                        "name": "reg",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetCastDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Фильм с новым составом",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.FilmAggregate"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка 422",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films/{id}/actors/{actorId}": {
+            "delete": {
+                "description": "Доступно только админам, убирает все роли актера в фильме. Отсутствующая связь не ошибка",
+                "tags": [
+                    "film"
+                ],
+                "summary": "Удаление актера из фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "actorId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Ошибка 404",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films/{id}/revisions": {
+            "get": {
+                "description": "Доступно только админам, от новых к старым. snapshot - фильм с титрами после правки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Ревизии фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во ревизий на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ревизии",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RevisionGetByQueryResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильм не найден",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films/{id}/revisions/diff": {
+            "get": {
+                "description": "Доступно только админам, поля которые поменялись от ревизии from к ревизии to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Разница между ревизиями фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Изменившиеся поля",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.RevisionDiffResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Нет номеров ревизий",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Ревизия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films/{id}/revisions/{number}": {
+            "get": {
+                "description": "Доступно только админам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Ревизия фильма [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ревизия",
+                        "schema": {
+                            "$ref": "#/definitions/model.Revision"
+                        }
+                    },
+                    "404": {
+                        "description": "Ревизия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/films/{id}/revisions/{number}/revert": {
+            "post": {
+                "description": "Доступно только админам. Поля и титры возвращаются к ревизии, откат проходит валидацию и создает новую ревизию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revision"
+                ],
+                "summary": "Откат фильма к ревизии [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "номер ревизии",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Фильм после отката",
+                        "schema": {
+                            "$ref": "#/definitions/aggregate.FilmAggregate"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильм или ревизия не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Актеры ревизии удалены",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Ревизия не проходит валидацию",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/import/{kind}": {
+            "post": {
+                "description": "Доступно только админам. Тело - CSV с заголовком (text/csv) или NDJSON (application/x-ndjson).\nКолонки films: id, name, description, release, rate. actors: id, name, gender, birthday.\ncredits: film, filmName, filmRelease, actor, actorName, actorBirthday, role, character, billingOrder.\nЗапись ищется по id, иначе по названию или имени и дате. Пустые поля не меняют найденную запись.\nОшибка строки не прерывает импорт, итог каждой строки в rows",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Импорт каталога из файла [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "что импортируется (films, actors, credits)",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "только проверка, без сохранения",
+                        "name": "dry-run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "строк в одной транзакции, по умолчанию 100",
+                        "name": "batch-size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Итог по строкам",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "Ошибка 415",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/trash/actors": {
+            "get": {
+                "description": "Доступно только админам, сначала удаленные последними",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Актеры в корзине [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во актеров на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "удаленные актеры",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.ActorGetByQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/trash/actors/{id}": {
+            "delete": {
+                "description": "Доступно только админам, удаляется только актер из корзины вместе со связями",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Удаление актера навсегда [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Актера нет в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/trash/actors/{id}/restore": {
+            "post": {
+                "description": "Доступно только админам, актер возвращается со всеми связями",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановление актера из корзины [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id актера",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Актера нет в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/trash/films": {
+            "get": {
+                "description": "Доступно только админам, сначала удаленные последними",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Фильмы в корзине [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "текущая страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "кол-во фильмов на странице",
+                        "name": "page-count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "удаленные фильмы",
+                        "schema": {
+                            "$ref": "#/definitions/appDto.FilmGetByQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка 400",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/trash/films/{id}": {
+            "delete": {
+                "description": "Доступно только админам, удаляется только фильм из корзины вместе с оценками, рецензиями и связями",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Удаление фильма навсегда [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Фильма нет в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/http/v2/trash/films/{id}/restore": {
+            "post": {
+                "description": "Доступно только админам, фильм возвращается со всеми связями",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановление фильма из корзины [Админы]",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Фильма нет в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/appErrors.ResponseError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "aggregate.ActorAggregate": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/model.Actor"
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Credit"
+                    }
+                },
+                "externalIds": {
+                    "description": "ExternalIds идентификаторы во внешних системах (источник -\u003e идентификатор), заполняются при чтении по id",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "films": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Film"
+                    }
+                }
+            }
+        },
+        "aggregate.FilmAggregate": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Actor"
+                    }
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Credit"
+                    }
+                },
+                "externalIds": {
+                    "description": "ExternalIds идентификаторы во внешних системах (источник -\u003e идентификатор), заполняются при чтении по id",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "film": {
+                    "$ref": "#/definitions/model.Film"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Genre"
+                    }
+                },
+                "search": {
+                    "description": "Search заполняется только в результатах поиска",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FilmSearchMatch"
+                        }
+                    ]
+                }
+            }
+        },
+        "aggregate.ReviewAggregate": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "$ref": "#/definitions/model.Rating"
+                },
+                "review": {
+                    "$ref": "#/definitions/model.Review"
+                }
+            }
+        },
+        "appDto.ActorGetByQueryResult": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aggregate.ActorAggregate"
+                    }
+                },
+                "next": {
+                    "description": "Next и Prev токены курсора соседних страниц, пустые если страницы нет",
+                    "type": "string"
+                },
+                "pageCount": {
+                    "type": "integer"
+                },
+                "prev": {
+                    "type": "string"
+                }
+            }
+        },
+        "appDto.AuditGetByQueryResult": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AuditEntry"
+                    }
+                },
+                "pageCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appDto.CreateActorUseCaseDto": {
+            "type": "object",
+            "required": [
+                "birthday",
+                "gender",
+                "name"
+            ],
+            "properties": {
+                "birthday": {
+                    "type": "string"
+                },
+                "externalIds": {
+                    "description": "ExternalIds идентификаторы актера во внешних системах, например imdb: nm0000209",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "appDto.CreateFilmUseCaseDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "release"
+            ],
+            "properties": {
+                "actorIds": {
+                    "description": "ActorIds существующие актеры, Actors новые актеры. Все они попадают в титры с ролью actor в порядке передачи",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/appDto.CreateActorUseCaseDto"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "externalIds": {
+                    "description": "ExternalIds идентификаторы фильма во внешних системах, источник -\u003e идентификатор, например imdb: tt0111161",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "manualRate": {
+                    "description": "ManualRate ручная оценка админа, учитывается только при honor_manual_rate. Без нее rate считается по оценкам пользователей",
+                    "type": "number",
+                    "maximum": 10,
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 1
+                },
+                "release": {
+                    "type": "string"
+                }
+            }
+        },
+        "appDto.CreateGenreUseCaseDto": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                }
+            }
+        },
+        "appDto.CreateReviewUseCaseDto": {
+            "type": "object",
+            "required": [
+                "body",
+                "filmId",
+                "title"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1
+                },
+                "filmId": {
+                    "type": "string"
+                },
+                "spoiler": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 1
+                }
+            }
+        },
+        "appDto.CreateUserListUseCaseDto": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "shared": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "appDto.FilmGetByQueryResult": {
+            "type": "object",
+            "properties": {
+                "films": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aggregate.FilmAggregate"
+                    }
+                },
+                "next": {
+                    "description": "Next и Prev токены курсора соседних страниц, пустые если страницы нет",
+                    "type": "string"
+                },
+                "pageCount": {
+                    "type": "integer"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appDto.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/appDto.ImportRowResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appDto.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "filmId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "appDto.LoginUseCaseDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "password"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 35,
+                    "minLength": 8
+                }
+            }
+        },
+        "appDto.ModerateReviewUseCaseDto": {
+            "type": "object",
+            "required": [
+                "action",
+                "id"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "approve",
+                        "reject",
+                        "hide"
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "appDto.MoveUserListFilmUseCaseDto": {
+            "type": "object",
+            "required": [
+                "filmId",
+                "listId"
+            ],
+            "properties": {
+                "filmId": {
+                    "type": "string"
+                },
+                "listId": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "appDto.RateFilmUseCaseDto": {
+            "type": "object",
+            "required": [
+                "filmId",
+                "score"
+            ],
+            "properties": {
+                "filmId": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                }
+            }
+        },
+        "appDto.RatingUseCaseResult": {
+            "type": "object",
+            "properties": {
+                "filmId": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "rating": {
+                    "$ref": "#/definitions/model.Rating"
+                },
+                "voteCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appDto.RegistrationUseCaseDto": {
+            "type": "object",
+            "required": [
+                "name",
+                "password"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 35,
+                    "minLength": 8
+                }
+            }
+        },
+        "appDto.ResponseUserDto": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "appDto.ReviewGetByQueryResult": {
+            "type": "object",
+            "properties": {
+                "pageCount": {
+                    "type": "integer"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aggregate.ReviewAggregate"
+                    }
+                }
+            }
+        },
+        "appDto.RevisionDiffResult": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "type": "object"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appDto.RevisionGetByQueryResult": {
+            "type": "object",
+            "properties": {
+                "pageCount": {
+                    "type": "integer"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Revision"
+                    }
+                }
+            }
+        },
+        "appDto.SuggestUseCaseResult": {
+            "type": "object",
+            "properties": {
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Suggestion"
+                    }
+                }
+            }
+        },
+        "appDto.UpdateReviewUseCaseDto": {
+            "type": "object",
+            "required": [
+                "body",
+                "id",
+                "title"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1
+                },
+                "id": {
+                    "type": "string"
+                },
+                "spoiler": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 1
+                }
+            }
+        },
+        "appDto.UpdateUserListUseCaseDto": {
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "shared": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "appDto.UserListFilmUseCaseDto": {
+            "type": "object",
+            "required": [
+                "filmId",
+                "listId"
+            ],
+            "properties": {
+                "filmId": {
+                    "type": "string"
                 },
+                "listId": {
+                    "type": "string"
+                }
+            }
+        },
+        "appDto.UserListFilmsResult": {
+            "type": "object",
+            "properties": {
                 "films": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Film"
+                        "$ref": "#/definitions/aggregate.FilmAggregate"
+                    }
+                },
+                "list": {
+                    "$ref": "#/definitions/model.UserList"
+                },
+                "pageCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "appErrors.ResponseError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddFilmToActorDto": {
+            "type": "object",
+            "required": [
+                "actorId"
+            ],
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CreditDto"
+                    }
+                },
+                "filmIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CastCreditDto": {
+            "type": "object",
+            "required": [
+                "actorId"
+            ],
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "billingOrder": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "character": {
+                    "type": "string",
+                    "maxLength": 150
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreditDto": {
+            "type": "object",
+            "required": [
+                "filmId"
+            ],
+            "properties": {
+                "billingOrder": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "character": {
+                    "type": "string",
+                    "maxLength": 150
+                },
+                "filmId": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FilmsToGenreDto": {
+            "type": "object",
+            "required": [
+                "filmIds",
+                "genreId"
+            ],
+            "properties": {
+                "filmIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "genreId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SetActorFilmsDto": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CreditDto"
+                    }
+                },
+                "filmIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "aggregate.FilmAggregate": {
+        "dto.SetCastDto": {
             "type": "object",
             "properties": {
-                "actors": {
+                "actorIds": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Actor"
+                        "type": "string"
                     }
                 },
-                "film": {
-                    "$ref": "#/definitions/model.Film"
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CastCreditDto"
+                    }
                 }
             }
         },
-        "appDto.ActorGetByQueryResult": {
+        "dto.UpdateFilmBody": {
             "type": "object",
+            "required": [
+                "id",
+                "name",
+                "release"
+            ],
             "properties": {
-                "actors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/aggregate.ActorAggregate"
-                    }
+                "deletedAt": {
+                    "type": "string"
                 },
-                "pageCount": {
-                    "type": "integer"
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manualRate": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 1
+                },
+                "rate": {
+                    "type": "number",
+                    "maximum": 10,
+                    "minimum": 0
+                },
+                "release": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "voteCount": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "appDto.CreateActorUseCaseDto": {
+        "model.Actor": {
             "type": "object",
             "required": [
-                "birthday",
+                "birhday",
                 "gender",
+                "id",
                 "name"
             ],
             "properties": {
-                "birthday": {
+                "birhday": {
+                    "type": "string"
+                },
+                "deletedAt": {
                     "type": "string"
                 },
                 "gender": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
+                },
+                "version": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "appDto.CreateFilmUseCaseDto": {
+        "model.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "object"
+                },
+                "entityId": {
+                    "type": "string"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Credit": {
+            "type": "object",
+            "required": [
+                "actorId",
+                "filmId",
+                "role"
+            ],
+            "properties": {
+                "actorId": {
+                    "type": "string"
+                },
+                "billingOrder": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "character": {
+                    "type": "string",
+                    "maxLength": 150
+                },
+                "filmId": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Film": {
             "type": "object",
             "required": [
+                "id",
                 "name",
                 "release"
             ],
             "properties": {
+                "deletedAt": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string",
                     "maxLength": 1000
                 },
+                "id": {
+                    "type": "string"
+                },
+                "manualRate": {
+                    "type": "number",
+                    "maximum": 10,
+                    "minimum": 0
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 150,
@@ -628,155 +4733,207 @@ const docTemplate = `{
                 },
                 "release": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "voteCount": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "appDto.FilmGetByQueryResult": {
+        "model.FilmSearchMatch": {
             "type": "object",
             "properties": {
-                "films": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/aggregate.FilmAggregate"
-                    }
+                "description": {
+                    "type": "string"
                 },
-                "pageCount": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "titleMatch": {
+                    "type": "boolean"
                 }
             }
         },
-        "appDto.LoginUseCaseDto": {
+        "model.Genre": {
             "type": "object",
             "required": [
-                "name",
-                "password"
+                "id",
+                "name"
             ],
             "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                "id": {
+                    "type": "string"
                 },
-                "password": {
+                "name": {
                     "type": "string",
-                    "maxLength": 35,
-                    "minLength": 8
+                    "maxLength": 50,
+                    "minLength": 1
                 }
             }
         },
-        "appDto.RegistrationUseCaseDto": {
+        "model.Rating": {
             "type": "object",
             "required": [
-                "name",
-                "password"
+                "filmId",
+                "score",
+                "userId"
             ],
             "properties": {
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                "createdAt": {
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 35,
-                    "minLength": 8
+                "filmId": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
                 }
             }
         },
-        "appDto.ResponseUserDto": {
+        "model.Review": {
             "type": "object",
+            "required": [
+                "body",
+                "filmId",
+                "id",
+                "status",
+                "title",
+                "userId"
+            ],
             "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "minLength": 1
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "filmId": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "moderationNote": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "spoiler": {
+                    "type": "boolean"
+                },
+                "status": {
                     "type": "string"
                 },
-                "role": {
+                "title": {
+                    "type": "string",
+                    "maxLength": 150,
+                    "minLength": 1
+                },
+                "updatedAt": {
                     "type": "string"
-                }
-            }
-        },
-        "appErrors.ResponseError": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
                 },
-                "message": {
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "dto.AddFilmToActorDto": {
+        "model.Revision": {
             "type": "object",
-            "required": [
-                "actorId",
-                "filmIds"
-            ],
             "properties": {
-                "actorId": {
+                "action": {
                     "type": "string"
                 },
-                "filmIds": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "createdAt": {
+                    "type": "string"
+                },
+                "entityId": {
+                    "type": "string"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "snapshot": {
+                    "type": "object"
+                },
+                "userId": {
+                    "type": "string"
                 }
             }
         },
-        "model.Actor": {
+        "model.Suggestion": {
             "type": "object",
-            "required": [
-                "birhday",
-                "gender",
-                "id",
-                "name"
-            ],
             "properties": {
-                "birhday": {
+                "id": {
                     "type": "string"
                 },
-                "gender": {
+                "name": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "popularity": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                "similarity": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "model.Film": {
+        "model.UserList": {
             "type": "object",
             "required": [
                 "id",
+                "kind",
                 "name",
-                "release"
+                "userId"
             ],
             "properties": {
-                "description": {
-                    "type": "string",
-                    "maxLength": 1000
+                "createdAt": {
+                    "type": "string"
+                },
+                "filmCount": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
+                "kind": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string",
-                    "maxLength": 150,
+                    "maxLength": 100,
                     "minLength": 1
                 },
-                "rate": {
-                    "type": "number",
-                    "maximum": 10,
-                    "minimum": 0
+                "shareSlug": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 8
                 },
-                "release": {
+                "userId": {
                     "type": "string"
                 }
             }
@@ -10,10 +10,15 @@ type (
 		Name     string    `json:"name" validate:"required,min=1,max=100"`
 		Gender   string    `json:"gender" validate:"required,gender"`
 		Birthday time.Time `json:"birthday" validate:"required,dateIsLessNow"`
+		// ExternalIds идентификаторы актера во внешних системах, например imdb: nm0000209
+		ExternalIds map[string]string `json:"externalIds,omitempty"`
 	}
 
 	ActorGetByQueryResult struct {
 		Actors    []*aggregate.ActorAggregate `json:"actors"`
 		PageCount int                         `json:"pageCount"`
+		// Next и Prev токены курсора соседних страниц, пустые если страницы нет
+		Next string `json:"next,omitempty"`
+		Prev string `json:"prev,omitempty"`
 	}
 )
@@ -0,0 +1,10 @@
+package appDto
+
+import "github.com/OddEer0/vk-filmoteka/internal/domain/model"
+
+type (
+	AuditGetByQueryResult struct {
+		Entries   []*model.AuditEntry `json:"entries"`
+		PageCount int                 `json:"pageCount"`
+	}
+)
@@ -10,11 +10,21 @@ type (
 		Name        string    `json:"name" validate:"required,min=1,max=150"`
 		Description *string   `json:"description,omitempty" validate:"omitempty,max=1000"`
 		ReleaseDate time.Time `json:"release" validate:"required"`
-		Rate        float32   `json:"rate" validate:"min=0,max=10"`
+		// ManualRate ручная оценка админа, учитывается только при honor_manual_rate. Без нее rate считается по оценкам пользователей
+		ManualRate *float32 `json:"manualRate,omitempty" validate:"omitempty,min=0,max=10"`
+		// ActorIds существующие актеры, Actors новые актеры. Все они попадают в титры с ролью actor в порядке передачи
+		ActorIds []string                `json:"actorIds,omitempty" validate:"omitempty,dive,uuidv4"`
+		Actors   []CreateActorUseCaseDto `json:"actors,omitempty" validate:"omitempty,dive"`
+		// ExternalIds идентификаторы фильма во внешних системах, источник -> идентификатор, например imdb: tt0111161
+		ExternalIds map[string]string `json:"externalIds,omitempty"`
 	}
 
 	FilmGetByQueryResult struct {
 		Films     []*aggregate.FilmAggregate `json:"films"`
 		PageCount int                        `json:"pageCount"`
+		Total     int                        `json:"total,omitempty"`
+		// Next и Prev токены курсора соседних страниц, пустые если страницы нет
+		Next string `json:"next,omitempty"`
+		Prev string `json:"prev,omitempty"`
 	}
 )
@@ -0,0 +1,7 @@
+package appDto
+
+type (
+	CreateGenreUseCaseDto struct {
+		Name string `json:"name" validate:"required,min=1,max=50"`
+	}
+)
@@ -0,0 +1,66 @@
+package appDto
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+type (
+	// ImportUseCaseDto файл импорта. Kind - constants.ImportKinds, Format - constants.ImportFormats
+	ImportUseCaseDto struct {
+		Kind   string
+		Format string
+		Data   io.Reader
+		// DryRun только проверяет строки и сопоставляет их с каталогом, ничего не сохраняя
+		DryRun bool
+		// BatchSize сколько строк сохраняется одной транзакцией, 0 - значение по умолчанию
+		BatchSize int
+	}
+
+	// ImportRowResult итог строки файла. Id фильм или актер строки, у титров FilmId и ActorId
+	ImportRowResult struct {
+		Line    int    `json:"line"`
+		Status  string `json:"status"`
+		Id      string `json:"id,omitempty"`
+		FilmId  string `json:"filmId,omitempty"`
+		ActorId string `json:"actorId,omitempty"`
+		Reason  string `json:"reason,omitempty"`
+	}
+
+	// ImportReport при DryRun статусы означают то, что произошло бы при импорте
+	ImportReport struct {
+		Kind    string            `json:"kind"`
+		DryRun  bool              `json:"dryRun"`
+		Created int               `json:"created"`
+		Updated int               `json:"updated"`
+		Skipped int               `json:"skipped"`
+		Failed  int               `json:"failed"`
+		Rows    []ImportRowResult `json:"rows"`
+	}
+
+	// ImdbImportUseCaseDto датасет IMDb в каталоге Dir: title.basics.tsv.gz, title.principals.tsv.gz, name.basics.tsv.gz
+	// и title.ratings.tsv.gz, без которого недоступен MinVotes
+	ImdbImportUseCaseDto struct {
+		Dir fs.FS
+		// TitleTypes загружаемые titleType, пустой - только movie
+		TitleTypes []string
+		// MinVotes минимальное кол-во голосов IMDb у фильма, 0 - без фильтра
+		MinVotes  int
+		BatchSize int
+		// Restart начинает импорт заново, не продолжая прерванный
+		Restart bool
+		// Progress вызывается после каждой сохраненной пачки этапа, nil - не вызывается
+		Progress func(stage string, done int, total int)
+	}
+
+	// ImdbImportReport итоги этапов films, actors и credits. Rows этапов содержат только строки с ошибками,
+	// Line в них - строка файла датасета. Resumed - прогресс, с которого продолжен прерванный импорт
+	ImdbImportReport struct {
+		Resumed *model.ImdbProgress `json:"resumed,omitempty"`
+		Films   ImportReport        `json:"films"`
+		Actors  ImportReport        `json:"actors"`
+		Credits ImportReport        `json:"credits"`
+	}
+)
@@ -0,0 +1,18 @@
+package appDto
+
+import "github.com/OddEer0/vk-filmoteka/internal/domain/model"
+
+type (
+	RateFilmUseCaseDto struct {
+		FilmId string `json:"filmId" validate:"required,uuidv4"`
+		Score  int    `json:"score" validate:"required,min=1,max=10"`
+	}
+
+	// RatingUseCaseResult оценка пользователя и пересчитанные показатели фильма
+	RatingUseCaseResult struct {
+		Rating    *model.Rating `json:"rating,omitempty"`
+		FilmId    string        `json:"filmId"`
+		Rate      float32       `json:"rate"`
+		VoteCount int           `json:"voteCount"`
+	}
+)
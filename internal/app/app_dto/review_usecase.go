@@ -0,0 +1,30 @@
+package appDto
+
+import "github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+
+type (
+	CreateReviewUseCaseDto struct {
+		FilmId  string `json:"filmId" validate:"required,uuidv4"`
+		Title   string `json:"title" validate:"required,min=1,max=150"`
+		Body    string `json:"body" validate:"required,min=1,max=10000"`
+		Spoiler bool   `json:"spoiler"`
+	}
+
+	UpdateReviewUseCaseDto struct {
+		Id      string `json:"id" validate:"required,uuidv4"`
+		Title   string `json:"title" validate:"required,min=1,max=150"`
+		Body    string `json:"body" validate:"required,min=1,max=10000"`
+		Spoiler bool   `json:"spoiler"`
+	}
+
+	ModerateReviewUseCaseDto struct {
+		Id     string  `json:"id" validate:"required,uuidv4"`
+		Action string  `json:"action" validate:"required,oneof=approve reject hide"`
+		Note   *string `json:"note,omitempty" validate:"omitempty,max=500"`
+	}
+
+	ReviewGetByQueryResult struct {
+		Reviews   []*aggregate.ReviewAggregate `json:"reviews"`
+		PageCount int                          `json:"pageCount"`
+	}
+)
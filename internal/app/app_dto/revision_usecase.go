@@ -0,0 +1,21 @@
+package appDto
+
+import (
+	"encoding/json"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+type (
+	RevisionGetByQueryResult struct {
+		Revisions []*model.Revision `json:"revisions"`
+		PageCount int               `json:"pageCount"`
+	}
+
+	// RevisionDiffResult изменившиеся поля от ревизии From к ревизии To {"путь": {"before", "after"}}
+	RevisionDiffResult struct {
+		From int             `json:"from"`
+		To   int             `json:"to"`
+		Diff json.RawMessage `json:"diff" swaggertype:"object"`
+	}
+)
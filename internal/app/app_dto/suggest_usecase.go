@@ -0,0 +1,9 @@
+package appDto
+
+import "github.com/OddEer0/vk-filmoteka/internal/domain/model"
+
+type (
+	SuggestUseCaseResult struct {
+		Suggestions []*model.Suggestion `json:"suggestions"`
+	}
+)
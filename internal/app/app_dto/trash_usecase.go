@@ -0,0 +1,9 @@
+package appDto
+
+type (
+	// PurgeTrashResult сколько записей очистка корзины удалила навсегда
+	PurgeTrashResult struct {
+		Films  int `json:"films"`
+		Actors int `json:"actors"`
+	}
+)
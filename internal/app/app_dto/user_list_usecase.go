@@ -0,0 +1,37 @@
+package appDto
+
+import (
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+type (
+	CreateUserListUseCaseDto struct {
+		Name   string `json:"name" validate:"required,min=1,max=100"`
+		Shared bool   `json:"shared"`
+	}
+
+	// UpdateUserListUseCaseDto Shared=true выдает ссылку (существующая сохраняется), false закрывает доступ
+	UpdateUserListUseCaseDto struct {
+		Id     string `json:"id" validate:"required,uuidv4"`
+		Name   string `json:"name" validate:"required,min=1,max=100"`
+		Shared bool   `json:"shared"`
+	}
+
+	UserListFilmUseCaseDto struct {
+		ListId string `json:"listId" validate:"required,uuidv4"`
+		FilmId string `json:"filmId" validate:"required,uuidv4"`
+	}
+
+	MoveUserListFilmUseCaseDto struct {
+		ListId   string `json:"listId" validate:"required,uuidv4"`
+		FilmId   string `json:"filmId" validate:"required,uuidv4"`
+		Position int    `json:"position" validate:"min=0"`
+	}
+
+	UserListFilmsResult struct {
+		List      *model.UserList            `json:"list"`
+		Films     []*aggregate.FilmAggregate `json:"films"`
+		PageCount int                        `json:"pageCount"`
+	}
+)
@@ -0,0 +1,62 @@
+package auditService
+
+import (
+	"context"
+
+	tokenService "github.com/OddEer0/vk-filmoteka/internal/app/services/token_service"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	// MutateFunc изменение сущности, возвращает ее состояние до и после. nil - сущности нет (создание, удаление)
+	MutateFunc func(ctx context.Context) (before interface{}, after interface{}, err error)
+
+	// Service журнал изменений: кто, что и в каком запросе поменял
+	Service interface {
+		// Track выполняет fn и пишет запись о ней в одной транзакции, так изменение без записи в журнал невозможно.
+		// Ошибка fn возвращается как есть и ничего не пишется. Пользователь и id запроса берутся из ctx
+		Track(ctx context.Context, action string, entityType string, entityId string, fn MutateFunc) error
+	}
+
+	auditService struct {
+		repository.AuditRepository
+		repository.TxManager
+	}
+)
+
+func (a *auditService) Track(ctx context.Context, action string, entityType string, entityId string, fn MutateFunc) error {
+	return a.TxManager.Do(ctx, func(ctx context.Context) error {
+		before, after, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+
+		diff, err := Diff(before, after)
+		if err != nil {
+			return err
+		}
+		entry := &model.AuditEntry{
+			Id:         uuid.New().String(),
+			Action:     action,
+			EntityType: entityType,
+			EntityId:   entityId,
+			Diff:       diff,
+			RequestId:  appErrors.RequestIdFromContext(ctx),
+		}
+		if user, ok := tokenService.UserFromContext(ctx); ok {
+			entry.UserId = user.Id
+		}
+		_, err = a.AuditRepository.Create(ctx, entry)
+		return err
+	})
+}
+
+func New(auditRepository repository.AuditRepository, txManager repository.TxManager) Service {
+	return &auditService{
+		AuditRepository: auditRepository,
+		TxManager:       txManager,
+	}
+}
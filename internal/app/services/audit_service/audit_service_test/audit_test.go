@@ -0,0 +1,67 @@
+package audit_service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	tokenService "github.com/OddEer0/vk-filmoteka/internal/app/services/token_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditService(t *testing.T) {
+	db := inMemDb.New()
+	db.CleanUp()
+	service := auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager())
+
+	t.Run("Should diff changed fields only", func(t *testing.T) {
+		before := map[string]interface{}{"film": map[string]interface{}{"name": "A", "rate": 5}, "credits": []int{1}}
+		after := map[string]interface{}{"film": map[string]interface{}{"name": "B", "rate": 5}, "credits": []int{1, 2}}
+		diff, err := auditService.Diff(before, after)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"credits":{"before":[1],"after":[1,2]},"film.name":{"before":"A","after":"B"}}`, string(diff))
+
+		diff, err = auditService.Diff(nil, map[string]string{"name": "C"})
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"name":{"before":null,"after":"C"}}`, string(diff))
+	})
+
+	t.Run("Should record user and request id", func(t *testing.T) {
+		ctx := tokenService.WithUser(context.Background(), &tokenService.JwtUserData{Id: "admin", Role: constants.AdminRole})
+		ctx = context.WithValue(ctx, appErrors.RequestIdContextKey, "request-1")
+		err := service.Track(ctx, constants.AuditUpdate, constants.AuditGenre, "genre-1", func(ctx context.Context) (interface{}, interface{}, error) {
+			db.Genre = append(db.Genre, &model.Genre{Id: "genre-1", Name: "Drama"})
+			return model.Genre{Id: "genre-1", Name: "Dram"}, model.Genre{Id: "genre-1", Name: "Drama"}, nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(db.Audit))
+		entry := db.Audit[0]
+		assert.Equal(t, "admin", entry.UserId)
+		assert.Equal(t, "request-1", entry.RequestId)
+		assert.Equal(t, "genre-1", entry.EntityId)
+		var diff map[string]auditService.FieldChange
+		assert.Nil(t, json.Unmarshal(entry.Diff, &diff))
+		assert.Equal(t, 1, len(diff))
+		assert.Equal(t, `"Drama"`, string(diff["name"].After))
+	})
+
+	t.Run("Should not record failed change", func(t *testing.T) {
+		failed := errors.New("failed")
+		err := service.Track(context.Background(), constants.AuditDelete, constants.AuditGenre, "genre-1", func(ctx context.Context) (interface{}, interface{}, error) {
+			db.Genre = nil
+			return nil, nil, failed
+		})
+		assert.Equal(t, failed, err)
+		assert.Equal(t, 1, len(db.Audit))
+		assert.Equal(t, 1, len(db.Genre))
+	})
+
+	db.CleanUp()
+}
@@ -0,0 +1,77 @@
+package auditService
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// FieldChange значение поля до и после изменения, null - поля не было
+type FieldChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Diff изменившиеся поля json представлений before и after. Вложенные объекты разворачиваются в путь через точку
+// (film.name), массивы сравниваются целиком
+func Diff(before interface{}, after interface{}) (json.RawMessage, error) {
+	beforeFields, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]FieldChange)
+	for _, fields := range []map[string]json.RawMessage{beforeFields, afterFields} {
+		for path := range fields {
+			if !bytes.Equal(beforeFields[path], afterFields[path]) {
+				changes[path] = FieldChange{Before: beforeFields[path], After: afterFields[path]}
+			}
+		}
+	}
+	// ключи map json.Marshal сортирует сам
+	return json.Marshal(changes)
+}
+
+// flatten поля json объекта по путям, nil и не объект (массив, значение) - поле с пустым путем
+func flatten(value interface{}) (map[string]json.RawMessage, error) {
+	result := make(map[string]json.RawMessage)
+	if value == nil {
+		return result, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return result, flattenInto(result, "", decoded)
+}
+
+func flattenInto(result map[string]json.RawMessage, path string, value interface{}) error {
+	if object, ok := value.(map[string]interface{}); ok && len(object) != 0 {
+		for key, field := range object {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			if err := flattenInto(result, fieldPath, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	result[path] = data
+	return nil
+}
@@ -0,0 +1,132 @@
+package revisionService
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"sort"
+
+	tokenService "github.com/OddEer0/vk-filmoteka/internal/app/services/token_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	// Service сохраняет ревизии фильмов и актеров. Вызывается внутри транзакции правки, чтобы ревизия
+	// видела ее результат и откатывалась вместе с ней
+	Service interface {
+		// RecordFilms текущее состояние фильмов как их новые ревизии, фильмы вне каталога пропускаются
+		RecordFilms(ctx context.Context, action string, ids ...string) error
+		RecordActors(ctx context.Context, action string, ids ...string) error
+	}
+
+	revisionService struct {
+		repository.RevisionRepository
+		repository.FilmRepository
+		repository.ActorRepository
+	}
+)
+
+func (r *revisionService) RecordFilms(ctx context.Context, action string, ids ...string) error {
+	for _, id := range ids {
+		film, err := r.FilmRepository.GetById(ctx, id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.record(ctx, action, constants.AuditFilm, id, FilmSnapshot(film)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *revisionService) RecordActors(ctx context.Context, action string, ids ...string) error {
+	for _, id := range ids {
+		actor, err := r.ActorRepository.GetById(ctx, id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.record(ctx, action, constants.AuditActor, id, ActorSnapshot(actor)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *revisionService) record(ctx context.Context, action string, entityType string, entityId string, snapshot interface{}) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	revision := &model.Revision{
+		Id:         uuid.New().String(),
+		EntityType: entityType,
+		EntityId:   entityId,
+		Action:     action,
+		Snapshot:   data,
+	}
+	if user, ok := tokenService.UserFromContext(ctx); ok {
+		revision.UserId = user.Id
+	}
+	_, err = r.RevisionRepository.Create(ctx, revision)
+	return err
+}
+
+// FilmSnapshot поля фильма и титры без служебных полей, которые меняются не правкой (версия, голоса, корзина)
+func FilmSnapshot(film *aggregate.FilmAggregate) *aggregate.FilmAggregate {
+	snapshot := &aggregate.FilmAggregate{Film: film.Film, Credits: film.Credits}
+	snapshot.Film.Version, snapshot.Film.VoteCount, snapshot.Film.DeletedAt = 0, 0, nil
+	return snapshot
+}
+
+func ActorSnapshot(actor *aggregate.ActorAggregate) *aggregate.ActorAggregate {
+	snapshot := &aggregate.ActorAggregate{Actor: actor.Actor, Credits: actor.Credits}
+	snapshot.Actor.Version, snapshot.Actor.DeletedAt = 0, nil
+	return snapshot
+}
+
+// ChangedLinks id второй стороны титров (key), у которых состав поменялся между before и after
+func ChangedLinks(before []*model.Credit, after []*model.Credit, key func(credit *model.Credit) string) []string {
+	group := func(credits []*model.Credit) map[string][]model.Credit {
+		result := make(map[string][]model.Credit)
+		for _, credit := range credits {
+			result[key(credit)] = append(result[key(credit)], *credit)
+		}
+		return result
+	}
+	beforeLinks, afterLinks := group(before), group(after)
+
+	changed := make([]string, 0, len(beforeLinks)+len(afterLinks))
+	for _, links := range []map[string][]model.Credit{beforeLinks, afterLinks} {
+		for id := range links {
+			if slices.Contains(changed, id) {
+				continue
+			}
+			beforeData, _ := json.Marshal(beforeLinks[id])
+			afterData, _ := json.Marshal(afterLinks[id])
+			if string(beforeData) != string(afterData) {
+				changed = append(changed, id)
+			}
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func New(revisionRepository repository.RevisionRepository, filmRepository repository.FilmRepository, actorRepository repository.ActorRepository) Service {
+	return &revisionService{
+		RevisionRepository: revisionRepository,
+		FilmRepository:     filmRepository,
+		ActorRepository:    actorRepository,
+	}
+}
@@ -0,0 +1,20 @@
+package tokenService
+
+import (
+	"context"
+
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+)
+
+// UserContextKey ключ, под которым middleware авторизации кладет *JwtUserData в контекст
+const UserContextKey = appErrors.ContextKey("user")
+
+func WithUser(ctx context.Context, user *JwtUserData) context.Context {
+	return context.WithValue(ctx, UserContextKey, user)
+}
+
+// UserFromContext данные авторизованного пользователя запроса
+func UserFromContext(ctx context.Context) (*JwtUserData, bool) {
+	user, ok := ctx.Value(UserContextKey).(*JwtUserData)
+	return user, ok && user != nil
+}
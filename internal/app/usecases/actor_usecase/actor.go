@@ -3,13 +3,18 @@ package actorUseCase
 import (
 	"context"
 	"database/sql"
+	"errors"
 	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
 	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 	"github.com/google/uuid"
+	"slices"
 )
 
 type (
@@ -18,13 +23,21 @@ type (
 		Update(ctx context.Context, data *aggregate.ActorAggregate) (*aggregate.ActorAggregate, error)
 		Delete(ctx context.Context, id string) error
 		GetById(ctx context.Context, id string) (*aggregate.ActorAggregate, error)
+		// GetByExternalId актер по идентификатору источника из constants.ExternalSources
+		GetByExternalId(ctx context.Context, source string, externalId string) (*aggregate.ActorAggregate, error)
 		GetByQuery(ctx context.Context, query domainQuery.ActorRepositoryQuery) (*appDto.ActorGetByQueryResult, error)
-		AddFilm(ctx context.Context, actorId string, filmIds ...string) error
+		GetByFilm(ctx context.Context, filmId string, query domainQuery.ActorRepositoryQuery) (*appDto.ActorGetByQueryResult, error)
+		AddFilm(ctx context.Context, actorId string, credits ...*model.Credit) error
+		SetFilms(ctx context.Context, actorId string, credits ...*model.Credit) (*aggregate.ActorAggregate, error)
+		RemoveFilm(ctx context.Context, actorId string, filmIds ...string) error
 	}
 
 	actorUseCase struct {
 		repository.ActorRepository
 		repository.FilmRepository
+		repository.ExternalIdRepository
+		AuditService    auditService.Service
+		RevisionService revisionService.Service
 	}
 )
 
@@ -36,23 +49,65 @@ func (a *actorUseCase) Create(ctx context.Context, data appDto.CreateActorUseCas
 		Birthday: data.Birthday,
 	})
 
+	if err == nil {
+		err = aggregate.ValidateActorExternalIds(data.ExternalIds)
+	}
 	if err != nil {
 		return nil, appErrors.UnprocessableEntity("", "target: ActorUseCase, method: Create ", "error: ", err.Error())
 	}
 
-	createAggregate, err := a.ActorRepository.Create(ctx, actorAggregate)
+	var createAggregate *aggregate.ActorAggregate
+	err = a.AuditService.Track(ctx, constants.AuditCreate, constants.AuditActor, actorAggregate.Actor.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+		createAggregate, err = a.ActorRepository.Create(ctx, actorAggregate)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := repository.CheckExternalIds(ctx, a.ExternalIdRepository.ActorIds, createAggregate.Actor.Id, data.ExternalIds); err != nil {
+			return nil, nil, err
+		}
+		if err := a.ExternalIdRepository.SetActor(ctx, createAggregate.Actor.Id, data.ExternalIds); err != nil {
+			return nil, nil, err
+		}
+		if err := a.RevisionService.RecordActors(ctx, constants.AuditCreate, actorAggregate.Actor.Id); err != nil {
+			return nil, nil, err
+		}
+		return nil, &aggregate.ActorAggregate{Actor: createAggregate.Actor, ExternalIds: data.ExternalIds}, nil
+	})
+	if errors.Is(err, repository.ErrExternalIdConflict) {
+		return nil, appErrors.Conflict("", "target: ActorUseCase, method: Create ", "error: ", err.Error())
+	}
 	if err != nil {
 		return nil, appErrors.InternalServerError("", "target: ActorUseCase, method: Create ", "repository create error: ", err.Error())
 	}
 
+	if len(data.ExternalIds) > 0 {
+		createAggregate.ExternalIds = data.ExternalIds
+	}
 	return createAggregate, nil
 }
 
 func (a *actorUseCase) Update(ctx context.Context, data *aggregate.ActorAggregate) (*aggregate.ActorAggregate, error) {
-	updateAggregate, err := a.ActorRepository.Update(ctx, data)
+	var updateAggregate *aggregate.ActorAggregate
+	err := a.AuditService.Track(ctx, constants.AuditUpdate, constants.AuditActor, data.Actor.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := a.ActorRepository.GetById(ctx, data.Actor.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		updateAggregate, err = a.ActorRepository.Update(ctx, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := a.RevisionService.RecordActors(ctx, constants.AuditUpdate, data.Actor.Id); err != nil {
+			return nil, nil, err
+		}
+		return &aggregate.ActorAggregate{Actor: before.Actor}, &aggregate.ActorAggregate{Actor: updateAggregate.Actor}, nil
+	})
 	if err == sql.ErrNoRows {
 		return nil, appErrors.NotFound("")
 	}
+	if err == repository.ErrVersionConflict {
+		return nil, appErrors.PreconditionFailed("", "target: ActorUseCase, method: Update ", "error: ", err.Error())
+	}
 	if err != nil {
 		return nil, appErrors.InternalServerError("")
 	}
@@ -61,7 +116,13 @@ func (a *actorUseCase) Update(ctx context.Context, data *aggregate.ActorAggregat
 }
 
 func (a *actorUseCase) Delete(ctx context.Context, id string) error {
-	err := a.ActorRepository.Delete(ctx, id)
+	err := a.AuditService.Track(ctx, constants.AuditDelete, constants.AuditActor, id, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := a.ActorRepository.GetById(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &aggregate.ActorAggregate{Actor: before.Actor}, nil, a.ActorRepository.Delete(ctx, id)
+	})
 	if err == sql.ErrNoRows {
 		return appErrors.NotFound("")
 	} else if err != nil {
@@ -71,16 +132,53 @@ func (a *actorUseCase) Delete(ctx context.Context, id string) error {
 }
 
 func (a *actorUseCase) GetByQuery(ctx context.Context, query domainQuery.ActorRepositoryQuery) (*appDto.ActorGetByQueryResult, error) {
+	if query.Cursor != nil {
+		query.SortField, query.OrderBy = query.Cursor.SortField, query.Cursor.OrderBy
+	}
 	byQuery, pageCount, err := a.ActorRepository.GetByQuery(ctx, query)
 	if err != nil {
 		return nil, appErrors.InternalServerError("", "target: ActorUseCase, method: GetByQuery. ", "get by query error: ", err.Error())
 	}
+
+	actors, next, prev := domainQuery.CursorPage(byQuery, query.Cursor, query.PageCount, query.CurrentPage, pageCount,
+		func(actor *aggregate.ActorAggregate, before bool) domainQuery.Cursor {
+			return actorCursor(actor.Actor, query.SortField, query.OrderBy, before)
+		})
+
 	return &appDto.ActorGetByQueryResult{
-		Actors:    byQuery,
+		Actors:    actors,
 		PageCount: pageCount,
+		Next:      next,
+		Prev:      prev,
 	}, nil
 }
 
+// GetByFilm актеры фильма, неизвестный фильм - 404 а не пустой список
+func (a *actorUseCase) GetByFilm(ctx context.Context, filmId string, query domainQuery.ActorRepositoryQuery) (*appDto.ActorGetByQueryResult, error) {
+	_, err := a.FilmRepository.GetById(ctx, filmId)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ActorUseCase, method: GetByFilm ", "error: ", err.Error())
+	}
+
+	query.FilmId = filmId
+	return a.GetByQuery(ctx, query)
+}
+
+// actorCursor курсор с ключом сортировки актера, без известного поля достаточно id
+func actorCursor(actor model.Actor, sortField string, orderBy domainQuery.OrderDirection, before bool) domainQuery.Cursor {
+	cursor := domainQuery.Cursor{SortField: sortField, OrderBy: orderBy, Id: actor.Id, Before: before}
+	switch sortField {
+	case "name":
+		cursor.Value = actor.Name
+	case "birthday":
+		cursor.Value = domainQuery.TimeCursorValue(actor.Birthday)
+	}
+	return cursor
+}
+
 func (a *actorUseCase) GetById(ctx context.Context, id string) (*aggregate.ActorAggregate, error) {
 	byId, err := a.ActorRepository.GetById(ctx, id)
 	if err == sql.ErrNoRows {
@@ -89,14 +187,45 @@ func (a *actorUseCase) GetById(ctx context.Context, id string) (*aggregate.Actor
 	if err != nil {
 		return nil, appErrors.InternalServerError("")
 	}
+	ids, err := a.ExternalIdRepository.GetActor(ctx, id)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ActorUseCase, method: GetById ", "external ids error: ", err.Error())
+	}
+	if len(ids) > 0 {
+		byId.ExternalIds = ids
+	}
 	return byId, nil
 }
 
-func (a *actorUseCase) AddFilm(ctx context.Context, actorId string, filmIds ...string) error {
-	if len(filmIds) == 0 {
+// GetByExternalId неизвестный источник - 400, актер в корзине не находится
+func (a *actorUseCase) GetByExternalId(ctx context.Context, source string, externalId string) (*aggregate.ActorAggregate, error) {
+	if !slices.Contains(constants.ExternalSources, source) {
+		return nil, appErrors.BadRequest("", "target: ActorUseCase, method: GetByExternalId ", "unknown source: ", source)
+	}
+	ids, err := a.ExternalIdRepository.ActorIds(ctx, source, externalId)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ActorUseCase, method: GetByExternalId ", "error: ", err.Error())
+	}
+	id, ok := ids[externalId]
+	if !ok {
+		return nil, appErrors.NotFound("")
+	}
+	return a.GetById(ctx, id)
+}
+
+func (a *actorUseCase) AddFilm(ctx context.Context, actorId string, credits ...*model.Credit) error {
+	if len(credits) == 0 {
 		return appErrors.InternalServerError("", "target: ActorUseCase, method: AddFilm", "error: ", "not id or ids")
 	}
-	err := a.ActorRepository.AddFilm(ctx, actorId, filmIds...)
+	for _, credit := range credits {
+		credit.ActorId = actorId
+	}
+	if err := aggregate.ValidateCredits(credits); err != nil {
+		return appErrors.UnprocessableEntity("", "target: ActorUseCase, method: AddFilm", " credit validation error: ", err.Error())
+	}
+	err := a.trackCredits(ctx, constants.AuditLink, actorId, func(ctx context.Context) error {
+		return a.ActorRepository.AddFilm(ctx, actorId, credits...)
+	})
 	if err == sql.ErrNoRows {
 		return appErrors.NotFound("")
 	}
@@ -107,9 +236,75 @@ func (a *actorUseCase) AddFilm(ctx context.Context, actorId string, filmIds ...s
 	return nil
 }
 
-func New(actorRepository repository.ActorRepository, filmRepository repository.FilmRepository) ActorUseCase {
+// SetFilms заменяет все участия актера и возвращает актера с новыми фильмами, пустой список убирает актера из всех фильмов
+func (a *actorUseCase) SetFilms(ctx context.Context, actorId string, credits ...*model.Credit) (*aggregate.ActorAggregate, error) {
+	for _, credit := range credits {
+		credit.ActorId = actorId
+	}
+	if err := aggregate.ValidateCredits(credits); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: ActorUseCase, method: SetFilms", " credit validation error: ", err.Error())
+	}
+	err := a.trackCredits(ctx, constants.AuditReplaceLinks, actorId, func(ctx context.Context) error {
+		return a.ActorRepository.SetFilms(ctx, actorId, credits...)
+	})
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ActorUseCase, method: SetFilms", " repository error: ", err.Error())
+	}
+
+	return a.GetById(ctx, actorId)
+}
+
+func (a *actorUseCase) RemoveFilm(ctx context.Context, actorId string, filmIds ...string) error {
+	err := a.trackCredits(ctx, constants.AuditUnlink, actorId, func(ctx context.Context) error {
+		return a.ActorRepository.RemoveFilm(ctx, actorId, filmIds...)
+	})
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: ActorUseCase, method: RemoveFilm", " repository error: ", err.Error())
+	}
+
+	return nil
+}
+
+// trackCredits пишет в журнал изменение участий актера, титры до и после читаются в той же транзакции.
+// Ревизии получают актер и фильмы, чьи титры с ним поменялись
+func (a *actorUseCase) trackCredits(ctx context.Context, action string, actorId string, fn func(ctx context.Context) error) error {
+	return a.AuditService.Track(ctx, action, constants.AuditActor, actorId, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := a.ActorRepository.GetById(ctx, actorId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := fn(ctx); err != nil {
+			return nil, nil, err
+		}
+		after, err := a.ActorRepository.GetById(ctx, actorId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := a.RevisionService.RecordActors(ctx, action, actorId); err != nil {
+			return nil, nil, err
+		}
+		filmIds := revisionService.ChangedLinks(before.Credits, after.Credits, func(credit *model.Credit) string {
+			return credit.FilmId
+		})
+		if err := a.RevisionService.RecordFilms(ctx, action, filmIds...); err != nil {
+			return nil, nil, err
+		}
+		return &aggregate.ActorAggregate{Credits: before.Credits}, &aggregate.ActorAggregate{Credits: after.Credits}, nil
+	})
+}
+
+func New(actorRepository repository.ActorRepository, filmRepository repository.FilmRepository, externalIdRepository repository.ExternalIdRepository, auditService auditService.Service, revisionService revisionService.Service) ActorUseCase {
 	return &actorUseCase{
-		ActorRepository: actorRepository,
-		FilmRepository:  filmRepository,
+		ActorRepository:      actorRepository,
+		FilmRepository:       filmRepository,
+		ExternalIdRepository: externalIdRepository,
+		AuditService:         auditService,
+		RevisionService:      revisionService,
 	}
 }
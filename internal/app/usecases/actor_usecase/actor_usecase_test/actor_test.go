@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
 	actorUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/actor_usecase"
 	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
@@ -20,7 +22,7 @@ import (
 func TestActorUseCase(t *testing.T) {
 	actorRepo := mockRepository.NewActorRepository()
 	filmRepo := mockRepository.NewFilmRepository()
-	useCase := actorUseCase.New(actorRepo, filmRepo)
+	useCase := actorUseCase.New(actorRepo, filmRepo, mockRepository.NewExternalIdRepository(), auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()), revisionService.New(mockRepository.NewRevisionRepository(), mockRepository.NewFilmRepository(), mockRepository.NewActorRepository()))
 
 	testId := uuid.New().String()
 	var actorAggr *aggregate.ActorAggregate
@@ -110,9 +112,21 @@ func TestActorUseCase(t *testing.T) {
 	}
 
 	t.Run("Should correct add film to actor", func(t *testing.T) {
-		err = useCase.AddFilm(context.Background(), testId, film1Id, film2Id)
+		character := "Jack"
+		err = useCase.AddFilm(context.Background(), testId,
+			&model.Credit{FilmId: film1Id, Character: &character, BillingOrder: 1},
+			&model.Credit{FilmId: film2Id, Role: "director"},
+		)
 		assert.Nil(t, err)
 
+		actor, err := useCase.GetById(context.Background(), testId)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(actor.Credits))
+		assert.Equal(t, film2Id, actor.Credits[0].FilmId)
+		assert.Equal(t, "director", actor.Credits[0].Role)
+		assert.Equal(t, "actor", actor.Credits[1].Role)
+		assert.Equal(t, "Jack", *actor.Credits[1].Character)
+
 		err = useCase.AddFilm(context.Background(), testId)
 		var appErr *appErrors.AppError
 		if errors.As(err, &appErr) {
@@ -121,14 +135,21 @@ func TestActorUseCase(t *testing.T) {
 			t.Fatal("incorrect error type")
 		}
 
-		err = useCase.AddFilm(context.Background(), testId, "incorrectfilmid")
+		err = useCase.AddFilm(context.Background(), testId, &model.Credit{FilmId: film1Id, Role: "incorrect"})
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusUnprocessableEntity, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+
+		err = useCase.AddFilm(context.Background(), testId, &model.Credit{FilmId: "incorrectfilmid"})
 		if errors.As(err, &appErr) {
 			assert.Equal(t, http.StatusNotFound, appErr.Code)
 		} else {
 			t.Fatal("incorrect error type")
 		}
 
-		err = useCase.AddFilm(context.Background(), "incorrectactorid", film1Id)
+		err = useCase.AddFilm(context.Background(), "incorrectactorid", &model.Credit{FilmId: film1Id})
 		if errors.As(err, &appErr) {
 			assert.Equal(t, http.StatusNotFound, appErr.Code)
 		} else {
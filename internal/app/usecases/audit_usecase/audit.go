@@ -0,0 +1,36 @@
+package auditUseCase
+
+import (
+	"context"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type (
+	// AuditUseCase чтение журнала изменений, записи в него делает auditService внутри остальных usecase
+	AuditUseCase interface {
+		GetByQuery(ctx context.Context, query domainQuery.AuditRepositoryQuery) (*appDto.AuditGetByQueryResult, error)
+	}
+
+	auditUseCase struct {
+		repository.AuditRepository
+	}
+)
+
+func (a *auditUseCase) GetByQuery(ctx context.Context, query domainQuery.AuditRepositoryQuery) (*appDto.AuditGetByQueryResult, error) {
+	entries, pageCount, err := a.AuditRepository.GetByQuery(ctx, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: AuditUseCase, method: GetByQuery ", "error: ", err.Error())
+	}
+
+	return &appDto.AuditGetByQueryResult{Entries: entries, PageCount: pageCount}, nil
+}
+
+func New(auditRepository repository.AuditRepository) AuditUseCase {
+	return &auditUseCase{
+		AuditRepository: auditRepository,
+	}
+}
@@ -26,9 +26,10 @@ type (
 		repository.UserRepository
 		UserService  userService.Service
 		TokenService tokenService.Service
+		TxManager    repository.TxManager
 	}
 )
 
-func New(userService userService.Service, tokenService tokenService.Service, userRepo repository.UserRepository) AuthUseCase {
-	return &authUseCase{UserService: userService, TokenService: tokenService, UserRepository: userRepo}
+func New(userService userService.Service, tokenService tokenService.Service, userRepo repository.UserRepository, txManager repository.TxManager) AuthUseCase {
+	return &authUseCase{UserService: userService, TokenService: tokenService, UserRepository: userRepo, TxManager: txManager}
 }
@@ -53,7 +53,7 @@ func TestAuthLogin(t *testing.T) {
 	userRepo := mockRepository.NewUserRepository()
 	tokenRepo := mockRepository.NewTokenRepository()
 
-	useCase := authUseCase.New(userService.New(userRepo), tokenService.New(tokenRepo), userRepo)
+	useCase := authUseCase.New(userService.New(userRepo), tokenService.New(tokenRepo), userRepo, mockRepository.NewTxManager())
 	cfg := config.MustLoad()
 	_, _ = useCase.Registration(context.Background(), mockData.Registration.CorrectRegInput1)
 
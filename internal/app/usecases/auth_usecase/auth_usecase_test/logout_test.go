@@ -17,7 +17,7 @@ func TestAuthLogout(t *testing.T) {
 	userRepo := mockRepository.NewUserRepository()
 	tokenRepo := mockRepository.NewTokenRepository()
 
-	useCase := authUseCase.New(userService.New(userRepo), tokenService.New(tokenRepo), userRepo)
+	useCase := authUseCase.New(userService.New(userRepo), tokenService.New(tokenRepo), userRepo, mockRepository.NewTxManager())
 	config.MustLoad()
 	_, _ = useCase.Registration(context.Background(), mockData.Registration.CorrectRegInput1)
 	res, _ := useCase.Login(context.Background(), mockData.Login.CorrectRegInput1)
@@ -65,7 +65,7 @@ func TestAuthRegistration(t *testing.T) {
 	userRepo := mockRepository.NewUserRepository()
 	tokenRepo := mockRepository.NewTokenRepository()
 
-	useCase := authUseCase.New(userService.New(userRepo), tokenService.New(tokenRepo), userRepo)
+	useCase := authUseCase.New(userService.New(userRepo), tokenService.New(tokenRepo), userRepo, mockRepository.NewTxManager())
 	cfg := config.MustLoad()
 
 	for _, testCase := range testCases {
@@ -84,6 +84,9 @@ func TestAuthRegistration(t *testing.T) {
 				assert.NotEmpty(t, result.Tokens.RefreshToken)
 				assert.NotEmpty(t, result.Tokens.AccessToken)
 				assert.True(t, isEqualUser(testCase.expectedResult.User, result.User))
+				saved, err := tokenRepo.HasByValue(context.Background(), result.Tokens.RefreshToken)
+				assert.Nil(t, err)
+				assert.True(t, saved)
 			} else {
 				assert.Nil(t, testCase.expectedResult)
 				var appErr *appErrors.AppError
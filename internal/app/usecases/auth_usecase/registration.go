@@ -7,6 +7,7 @@ import (
 	appMapper "github.com/OddEer0/vk-filmoteka/internal/app/app_mapper"
 	tokenService "github.com/OddEer0/vk-filmoteka/internal/app/services/token_service"
 	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 )
 
 func (a *authUseCase) Registration(ctx context.Context, data appDto.RegistrationUseCaseDto) (*AuthResult, error) {
@@ -23,9 +24,18 @@ func (a *authUseCase) Registration(ctx context.Context, data appDto.Registration
 		return nil, appErrors.UnprocessableEntity("", "target: AuthUseCase, method: Registration. ", "Aggregate SetToken method error: ", err.Error())
 	}
 
-	dbUserAggregate, err := a.UserRepository.Create(ctx, userAggregate)
+	// пользователь без сохраненного refresh токена не сможет обновить сессию, поэтому они создаются вместе
+	var dbUserAggregate *aggregate.UserAggregate
+	err = a.TxManager.Do(ctx, func(ctx context.Context) error {
+		dbUserAggregate, err = a.UserRepository.Create(ctx, userAggregate)
+		if err != nil {
+			return appErrors.InternalServerError("", "target: AuthUseCase, method: Registration. ", "UserRepository create user error: ", err.Error())
+		}
+		_, err = a.TokenService.Save(ctx, appDto.SaveTokenServiceDto{Id: dbUserAggregate.User.Id, RefreshToken: tokens.RefreshToken})
+		return err
+	})
 	if err != nil {
-		return nil, appErrors.InternalServerError("", "target: AuthUseCase, method: Registration. ", "UserRepository create user error: ", err.Error())
+		return nil, err
 	}
 	userMapper := appMapper.NewUserAggregateMapper()
 	responseUser := userMapper.ToResponseUserDto(dbUserAggregate)
@@ -0,0 +1,69 @@
+package exportUseCase
+
+import (
+	"context"
+	"time"
+
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+)
+
+type (
+	// ExportUseCase выгрузка каталога с фильтрами и сортировкой GetByQuery, но без страниц. Записи пишутся в out
+	// по одной прямо из курсора базы в колонках constants.CatalogColumns, которые принимает импорт. В конце out закрывается
+	ExportUseCase interface {
+		Films(ctx context.Context, query domainQuery.FilmRepositoryQuery, out httpUtils.StreamWriter) error
+		Actors(ctx context.Context, query domainQuery.ActorRepositoryQuery, out httpUtils.StreamWriter) error
+		// Credits титры фильмов, отобранных query
+		Credits(ctx context.Context, query domainQuery.FilmRepositoryQuery, out httpUtils.StreamWriter) error
+	}
+
+	exportUseCase struct {
+		repository.FilmRepository
+		repository.ActorRepository
+	}
+)
+
+func (e *exportUseCase) Films(ctx context.Context, query domainQuery.FilmRepositoryQuery, out httpUtils.StreamWriter) error {
+	err := e.FilmRepository.Each(ctx, query, func(film *model.Film) error {
+		return out.Write(film.Id, film.Name, film.Description, film.ReleaseDate.Format(time.DateOnly), film.Rate)
+	})
+	return finish(out, "Films", err)
+}
+
+func (e *exportUseCase) Actors(ctx context.Context, query domainQuery.ActorRepositoryQuery, out httpUtils.StreamWriter) error {
+	err := e.ActorRepository.Each(ctx, query, func(actor *model.Actor) error {
+		return out.Write(actor.Id, actor.Name, actor.Gender, actor.Birthday.Format(time.DateOnly))
+	})
+	return finish(out, "Actors", err)
+}
+
+func (e *exportUseCase) Credits(ctx context.Context, query domainQuery.FilmRepositoryQuery, out httpUtils.StreamWriter) error {
+	err := e.FilmRepository.EachCredit(ctx, query, func(credit *model.CreditDetails) error {
+		return out.Write(credit.FilmId, credit.FilmName, credit.FilmRelease.Format(time.DateOnly),
+			credit.ActorId, credit.ActorName, credit.ActorBirthday.Format(time.DateOnly),
+			credit.Role, credit.Character, credit.BillingOrder)
+	})
+	return finish(out, "Credits", err)
+}
+
+// finish закрывает out после успешного обхода, иначе ошибка обхода или записи
+func finish(out httpUtils.StreamWriter, method string, err error) error {
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: ExportUseCase, method: ", method, " error: ", err.Error())
+	}
+	return nil
+}
+
+func New(filmRepository repository.FilmRepository, actorRepository repository.ActorRepository) ExportUseCase {
+	return &exportUseCase{
+		FilmRepository:  filmRepository,
+		ActorRepository: actorRepository,
+	}
+}
@@ -0,0 +1,88 @@
+package export_usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	exportUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/export_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportUseCase(t *testing.T) {
+	db := inMemDb.New()
+	db.CleanUp()
+	description, character := "Comma, \"quoted\"", "Hero"
+	release := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)
+	db.Film = append(db.Film,
+		&model.Film{Id: "film-1", Name: "Export film", Description: &description, ReleaseDate: release, Rate: 7.5},
+		&model.Film{Id: "film-2", Name: "Other film", ReleaseDate: release, Rate: 3},
+	)
+	db.Actor = append(db.Actor,
+		&model.Actor{Id: "actor-1", Name: "Export actor", Gender: "male", Birthday: release},
+		&model.Actor{Id: "actor-2", Name: "Second actor", Gender: "female", Birthday: release},
+	)
+	db.ActorFilm = append(db.ActorFilm,
+		&inMemDb.ActorFilm{ActorId: "actor-2", FilmId: "film-1", Role: "actor", BillingOrder: 2},
+		&inMemDb.ActorFilm{ActorId: "actor-1", FilmId: "film-1", Role: "actor", Character: &character, BillingOrder: 1},
+	)
+	useCase := exportUseCase.New(mockRepository.NewFilmRepository(), mockRepository.NewActorRepository())
+	defer db.CleanUp()
+
+	writer := func(t *testing.T, buf *bytes.Buffer, format, kind string) httpUtils.StreamWriter {
+		out, err := httpUtils.NewStreamWriter(buf, format, constants.CatalogColumns[kind])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	t.Run("Should export filtered films as csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		query := domainQuery.NewFilmRepositoryQuery()
+		rate := float32(5)
+		query.RateFrom = &rate
+		err := useCase.Films(context.Background(), *query, writer(t, &buf, httpUtils.StreamCsv, constants.ImportFilms))
+		assert.Nil(t, err)
+		assert.Equal(t, "id,name,description,release,rate\nfilm-1,Export film,\"Comma, \"\"quoted\"\"\",2001-02-03,7.5\n", buf.String())
+	})
+
+	t.Run("Should export actors as one json array", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := useCase.Actors(context.Background(), *domainQuery.NewActorRepositoryQuery(), writer(t, &buf, httpUtils.StreamJson, constants.ImportActors))
+		assert.Nil(t, err)
+		var actors []map[string]string
+		if err := json.Unmarshal(buf.Bytes(), &actors); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []map[string]string{
+			{"id": "actor-1", "name": "Export actor", "gender": "male", "birthday": "2001-02-03"},
+			{"id": "actor-2", "name": "Second actor", "gender": "female", "birthday": "2001-02-03"},
+		}, actors)
+
+		buf.Reset()
+		query := domainQuery.NewActorRepositoryQuery()
+		query.Gender = "other"
+		err = useCase.Actors(context.Background(), *query, writer(t, &buf, httpUtils.StreamJson, constants.ImportActors))
+		assert.Nil(t, err)
+		assert.Equal(t, "[\n\n]\n", buf.String())
+	})
+
+	t.Run("Should export credits in billing order as ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := useCase.Credits(context.Background(), *domainQuery.NewFilmRepositoryQuery(), writer(t, &buf, httpUtils.StreamNdjson, constants.ImportCredits))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"film":"film-1","filmName":"Export film","filmRelease":"2001-02-03","actor":"actor-1","actorName":"Export actor",`+
+			`"actorBirthday":"2001-02-03","role":"actor","character":"Hero","billingOrder":1}`+"\n"+
+			`{"film":"film-1","filmName":"Export film","filmRelease":"2001-02-03","actor":"actor-2","actorName":"Second actor",`+
+			`"actorBirthday":"2001-02-03","role":"actor","character":null,"billingOrder":2}`+"\n", buf.String())
+	})
+}
@@ -3,58 +3,172 @@ package filmUseCase
 import (
 	"context"
 	"database/sql"
+	"errors"
 	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
 	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 	"github.com/google/uuid"
+	"slices"
 )
 
 type (
 	FilmUseCase interface {
 		Create(ctx context.Context, data appDto.CreateFilmUseCaseDto) (*aggregate.FilmAggregate, error)
-		Update(ctx context.Context, data *aggregate.FilmAggregate) (*aggregate.FilmAggregate, error)
+		// Update ManualRate из data сохраняется только при setManualRate, иначе остается прежней. nil с setManualRate сбрасывает ее
+		Update(ctx context.Context, data *aggregate.FilmAggregate, setManualRate bool) (*aggregate.FilmAggregate, error)
 		Delete(ctx context.Context, id string) error
 		GetById(ctx context.Context, id string) (*aggregate.FilmAggregate, error)
+		// GetByExternalId фильм по идентификатору источника из constants.ExternalSources
+		GetByExternalId(ctx context.Context, source string, externalId string) (*aggregate.FilmAggregate, error)
 		GetByQuery(ctx context.Context, query domainQuery.FilmRepositoryQuery) (*appDto.FilmGetByQueryResult, error)
-		SearchByNameAndActorName(ctx context.Context, searchValue string) (*appDto.FilmGetByQueryResult, error)
+		SetCast(ctx context.Context, filmId string, credits ...*model.Credit) (*aggregate.FilmAggregate, error)
+		RemoveActor(ctx context.Context, filmId string, actorIds ...string) error
+		GetByActor(ctx context.Context, actorId string, query domainQuery.FilmRepositoryQuery) (*appDto.FilmGetByQueryResult, error)
+		SearchByNameAndActorName(ctx context.Context, query domainQuery.FilmSearchQuery) (*appDto.FilmGetByQueryResult, error)
 	}
 
 	filmUseCase struct {
 		repository.FilmRepository
+		repository.ActorRepository
+		repository.ExternalIdRepository
+		AuditService    auditService.Service
+		RevisionService revisionService.Service
+		honorManualRate bool
 	}
 )
 
+// Create фильм вместе с составом: существующие актеры по ActorIds и новые из Actors сохраняются одной транзакцией.
+// Внешний идентификатор, уже связанный с другой записью - 409
 func (f filmUseCase) Create(ctx context.Context, data appDto.CreateFilmUseCaseDto) (*aggregate.FilmAggregate, error) {
 	filmAggregate, err := aggregate.NewFilmAggregate(model.Film{
 		Id:          uuid.New().String(),
 		Name:        data.Name,
 		Description: data.Description,
 		ReleaseDate: data.ReleaseDate,
-		Rate:        data.Rate,
+		ManualRate:  data.ManualRate,
 	})
 
+	if err == nil {
+		filmAggregate.Film.ManualRate = f.manualRate(true, data.ManualRate, nil)
+		err = aggregate.ValidateFilmExternalIds(data.ExternalIds)
+	}
 	if err != nil {
-		return nil, appErrors.UnprocessableEntity("", "target: ActorUseCase, method: Create ", "error: ", err.Error())
+		return nil, appErrors.UnprocessableEntity("", "target: FilmUseCase, method: Create ", "error: ", err.Error())
+	}
+
+	castIds := make([]string, 0, len(data.ActorIds)+len(data.Actors))
+	for _, actorId := range data.ActorIds {
+		if !slices.Contains(castIds, actorId) {
+			castIds = append(castIds, actorId)
+		}
+	}
+	for _, actor := range data.Actors {
+		actorAggregate, err := aggregate.NewActorAggregate(model.Actor{
+			Id:       uuid.New().String(),
+			Name:     actor.Name,
+			Gender:   actor.Gender,
+			Birthday: actor.Birthday,
+		})
+		if err == nil {
+			err = aggregate.ValidateActorExternalIds(actor.ExternalIds)
+		}
+		if err != nil {
+			return nil, appErrors.UnprocessableEntity("", "target: FilmUseCase, method: Create ", "actor error: ", err.Error())
+		}
+		filmAggregate.Actors = append(filmAggregate.Actors, &actorAggregate.Actor)
+		castIds = append(castIds, actorAggregate.Actor.Id)
+	}
+	for i, actorId := range castIds {
+		filmAggregate.Credits = append(filmAggregate.Credits, &model.Credit{
+			ActorId:      actorId,
+			FilmId:       filmAggregate.Film.Id,
+			Role:         constants.CreditActor,
+			BillingOrder: i,
+		})
 	}
 
-	createAggregate, err := f.FilmRepository.Create(ctx, filmAggregate)
+	var createAggregate *aggregate.FilmAggregate
+	err = f.AuditService.Track(ctx, constants.AuditCreate, constants.AuditFilm, filmAggregate.Film.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+		createAggregate, err = f.FilmRepository.Create(ctx, filmAggregate)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := repository.CheckExternalIds(ctx, f.ExternalIdRepository.FilmIds, createAggregate.Film.Id, data.ExternalIds); err != nil {
+			return nil, nil, err
+		}
+		if err := f.ExternalIdRepository.SetFilm(ctx, createAggregate.Film.Id, data.ExternalIds); err != nil {
+			return nil, nil, err
+		}
+		newActors := make([]string, 0, len(filmAggregate.Actors))
+		for i, actor := range filmAggregate.Actors {
+			if err := repository.CheckExternalIds(ctx, f.ExternalIdRepository.ActorIds, actor.Id, data.Actors[i].ExternalIds); err != nil {
+				return nil, nil, err
+			}
+			if err := f.ExternalIdRepository.SetActor(ctx, actor.Id, data.Actors[i].ExternalIds); err != nil {
+				return nil, nil, err
+			}
+			newActors = append(newActors, actor.Id)
+		}
+		if err := f.RevisionService.RecordFilms(ctx, constants.AuditCreate, createAggregate.Film.Id); err != nil {
+			return nil, nil, err
+		}
+		if err := f.RevisionService.RecordActors(ctx, constants.AuditCreate, newActors...); err != nil {
+			return nil, nil, err
+		}
+		if err := f.RevisionService.RecordActors(ctx, constants.AuditLink, castIds[:len(castIds)-len(newActors)]...); err != nil {
+			return nil, nil, err
+		}
+		return nil, &aggregate.FilmAggregate{Film: createAggregate.Film, Credits: filmAggregate.Credits, ExternalIds: data.ExternalIds}, nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("", "target: FilmUseCase, method: Create ", "error: unknown actor in cast")
+	}
+	if errors.Is(err, repository.ErrExternalIdConflict) {
+		return nil, appErrors.Conflict("", "target: FilmUseCase, method: Create ", "error: ", err.Error())
+	}
 	if err != nil {
-		return nil, appErrors.InternalServerError("", "target: ActorUseCase, method: Create ", "repository create error: ", err.Error())
+		return nil, appErrors.InternalServerError("", "target: FilmUseCase, method: Create ", "repository create error: ", err.Error())
+	}
+	if len(castIds) == 0 {
+		if len(data.ExternalIds) > 0 {
+			createAggregate.ExternalIds = data.ExternalIds
+		}
+		return createAggregate, nil
 	}
 
-	return createAggregate, nil
+	return f.GetById(ctx, createAggregate.Film.Id)
 }
 
-func (f filmUseCase) Update(ctx context.Context, data *aggregate.FilmAggregate) (*aggregate.FilmAggregate, error) {
-	user, _ := f.FilmRepository.GetById(ctx, data.Film.Id)
-	if user == nil {
+func (f filmUseCase) Update(ctx context.Context, data *aggregate.FilmAggregate, setManualRate bool) (*aggregate.FilmAggregate, error) {
+	var updateAggregate *aggregate.FilmAggregate
+	err := f.AuditService.Track(ctx, constants.AuditUpdate, constants.AuditFilm, data.Film.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := f.FilmRepository.GetById(ctx, data.Film.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data.Film.ManualRate = f.manualRate(setManualRate, data.Film.ManualRate, before.Film.ManualRate)
+		updateAggregate, err = f.FilmRepository.Update(ctx, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := f.RevisionService.RecordFilms(ctx, constants.AuditUpdate, data.Film.Id); err != nil {
+			return nil, nil, err
+		}
+		return &aggregate.FilmAggregate{Film: before.Film}, &aggregate.FilmAggregate{Film: updateAggregate.Film}, nil
+	})
+	if err == sql.ErrNoRows {
 		return nil, appErrors.NotFound("")
 	}
-
-	updateAggregate, err := f.FilmRepository.Update(ctx, data)
+	if err == repository.ErrVersionConflict {
+		return nil, appErrors.PreconditionFailed("", "target: FilmUseCase, method: Update ", "error: ", err.Error())
+	}
 	if err != nil {
 		return nil, appErrors.InternalServerError("")
 	}
@@ -63,7 +177,13 @@ func (f filmUseCase) Update(ctx context.Context, data *aggregate.FilmAggregate)
 }
 
 func (f filmUseCase) Delete(ctx context.Context, id string) error {
-	err := f.FilmRepository.Delete(ctx, id)
+	err := f.AuditService.Track(ctx, constants.AuditDelete, constants.AuditFilm, id, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := f.FilmRepository.GetById(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &aggregate.FilmAggregate{Film: before.Film}, nil, f.FilmRepository.Delete(ctx, id)
+	})
 	if err == sql.ErrNoRows {
 		return appErrors.NotFound("", "error: ", err.Error())
 	}
@@ -82,37 +202,182 @@ func (f filmUseCase) GetById(ctx context.Context, id string) (*aggregate.FilmAgg
 	if err != nil {
 		return nil, appErrors.InternalServerError("")
 	}
+	ids, err := f.ExternalIdRepository.GetFilm(ctx, id)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: FilmUseCase, method: GetById ", "external ids error: ", err.Error())
+	}
+	if len(ids) > 0 {
+		film.ExternalIds = ids
+	}
 	return film, nil
 }
 
+// GetByExternalId неизвестный источник - 400, фильм в корзине не находится
+func (f filmUseCase) GetByExternalId(ctx context.Context, source string, externalId string) (*aggregate.FilmAggregate, error) {
+	if !slices.Contains(constants.ExternalSources, source) {
+		return nil, appErrors.BadRequest("", "target: FilmUseCase, method: GetByExternalId ", "unknown source: ", source)
+	}
+	ids, err := f.ExternalIdRepository.FilmIds(ctx, source, externalId)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: FilmUseCase, method: GetByExternalId ", "error: ", err.Error())
+	}
+	id, ok := ids[externalId]
+	if !ok {
+		return nil, appErrors.NotFound("")
+	}
+	return f.GetById(ctx, id)
+}
+
 func (f filmUseCase) GetByQuery(ctx context.Context, query domainQuery.FilmRepositoryQuery) (*appDto.FilmGetByQueryResult, error) {
+	if query.Cursor != nil {
+		query.SortField, query.OrderBy = query.Cursor.SortField, query.Cursor.OrderBy
+	}
 	byQuery, pageCount, err := f.FilmRepository.GetByQuery(ctx, query)
 	if err != nil {
 		return nil, appErrors.InternalServerError("", "error:", err.Error())
 	}
 
+	films, next, prev := domainQuery.CursorPage(byQuery, query.Cursor, query.PageCount, query.CurrentPage, pageCount,
+		func(film *aggregate.FilmAggregate, before bool) domainQuery.Cursor {
+			return filmCursor(film.Film, query.SortField, query.OrderBy, before)
+		})
+
 	return &appDto.FilmGetByQueryResult{
-		Films:     byQuery,
+		Films:     films,
 		PageCount: pageCount,
+		Next:      next,
+		Prev:      prev,
 	}, nil
 }
 
-func (f filmUseCase) SearchByNameAndActorName(ctx context.Context, searchValue string) (*appDto.FilmGetByQueryResult, error) {
-	films, pageCount, err := f.FilmRepository.SearchByNameAndActorName(ctx, searchValue)
-	if len(films) == 0 {
-		return nil, appErrors.NotFound("Search film not found")
+// SetCast заменяет все титры фильма и возвращает фильм с новым составом
+func (f filmUseCase) SetCast(ctx context.Context, filmId string, credits ...*model.Credit) (*aggregate.FilmAggregate, error) {
+	for _, credit := range credits {
+		credit.FilmId = filmId
+	}
+	if err := aggregate.ValidateCredits(credits); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: FilmUseCase, method: SetCast ", "credit validation error: ", err.Error())
+	}
+	err := f.trackCredits(ctx, constants.AuditReplaceLinks, filmId, func(ctx context.Context) error {
+		return f.FilmRepository.SetCast(ctx, filmId, credits...)
+	})
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
 	}
 	if err != nil {
-		return nil, appErrors.InternalServerError("")
+		return nil, appErrors.InternalServerError("", "target: FilmUseCase, method: SetCast ", "repository error: ", err.Error())
 	}
+
+	return f.GetById(ctx, filmId)
+}
+
+func (f filmUseCase) RemoveActor(ctx context.Context, filmId string, actorIds ...string) error {
+	err := f.trackCredits(ctx, constants.AuditUnlink, filmId, func(ctx context.Context) error {
+		return f.FilmRepository.RemoveActor(ctx, filmId, actorIds...)
+	})
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: FilmUseCase, method: RemoveActor ", "repository error: ", err.Error())
+	}
+
+	return nil
+}
+
+// GetByActor фильмы актера, неизвестный актер - 404 а не пустой список
+func (f filmUseCase) GetByActor(ctx context.Context, actorId string, query domainQuery.FilmRepositoryQuery) (*appDto.FilmGetByQueryResult, error) {
+	_, err := f.ActorRepository.GetById(ctx, actorId)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: FilmUseCase, method: GetByActor ", "error: ", err.Error())
+	}
+
+	query.Actors, query.ActorMatch = []string{actorId}, domainQuery.MatchAny
+	return f.GetByQuery(ctx, query)
+}
+
+// filmCursor курсор с ключом сортировки фильма, неизвестное поле сортирует по rate как в репозитории
+func filmCursor(film model.Film, sortField string, orderBy domainQuery.OrderDirection, before bool) domainQuery.Cursor {
+	cursor := domainQuery.Cursor{SortField: sortField, OrderBy: orderBy, Id: film.Id, Before: before}
+	switch sortField {
+	case "name":
+		cursor.Value = film.Name
+	case "release_date":
+		cursor.Value = domainQuery.TimeCursorValue(film.ReleaseDate)
+	default:
+		cursor.Value = domainQuery.FloatCursorValue(film.Rate)
+	}
+	return cursor
+}
+
+func (f filmUseCase) SearchByNameAndActorName(ctx context.Context, query domainQuery.FilmSearchQuery) (*appDto.FilmGetByQueryResult, error) {
+	films, total, err := f.FilmRepository.SearchByNameAndActorName(ctx, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: FilmUseCase, method: SearchByNameAndActorName ", "error: ", err.Error())
+	}
+	if total == 0 {
+		return nil, appErrors.NotFound("Search film not found")
+	}
+
+	pageCount := total / query.PageCount
+	if total%query.PageCount != 0 {
+		pageCount++
+	}
+
 	return &appDto.FilmGetByQueryResult{
 		Films:     films,
 		PageCount: pageCount,
+		Total:     total,
 	}, nil
 }
 
-func New(filmRepository repository.FilmRepository) FilmUseCase {
+// trackCredits пишет в журнал изменение титров фильма, состав до и после читается в той же транзакции.
+// Ревизии получают фильм и актеры, чьи титры в нем поменялись
+func (f filmUseCase) trackCredits(ctx context.Context, action string, filmId string, fn func(ctx context.Context) error) error {
+	return f.AuditService.Track(ctx, action, constants.AuditFilm, filmId, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := f.FilmRepository.GetById(ctx, filmId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := fn(ctx); err != nil {
+			return nil, nil, err
+		}
+		after, err := f.FilmRepository.GetById(ctx, filmId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := f.RevisionService.RecordFilms(ctx, action, filmId); err != nil {
+			return nil, nil, err
+		}
+		actorIds := revisionService.ChangedLinks(before.Credits, after.Credits, func(credit *model.Credit) string {
+			return credit.ActorId
+		})
+		if err := f.RevisionService.RecordActors(ctx, action, actorIds...); err != nil {
+			return nil, nil, err
+		}
+		return &aggregate.FilmAggregate{Credits: before.Credits}, &aggregate.FilmAggregate{Credits: after.Credits}, nil
+	})
+}
+
+// manualRate оценка админа меняется только если она передана и это разрешено конфигом, иначе rate считается по оценкам
+// пользователей, а ручная оценка current остается прежней на случай включения политики
+func (f filmUseCase) manualRate(set bool, rate *float32, current *float32) *float32 {
+	if !set || !f.honorManualRate {
+		return current
+	}
+	return rate
+}
+
+func New(filmRepository repository.FilmRepository, actorRepository repository.ActorRepository, externalIdRepository repository.ExternalIdRepository, auditService auditService.Service, revisionService revisionService.Service, honorManualRate bool) FilmUseCase {
 	return &filmUseCase{
-		FilmRepository: filmRepository,
+		FilmRepository:       filmRepository,
+		ActorRepository:      actorRepository,
+		ExternalIdRepository: externalIdRepository,
+		AuditService:         auditService,
+		RevisionService:      revisionService,
+		honorManualRate:      honorManualRate,
 	}
 }
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
 	filmUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/film_usecase"
 	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
 	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
@@ -17,12 +22,12 @@ import (
 
 func TestFilmUseCase(t *testing.T) {
 	filmRepo := mockRepository.NewFilmRepository()
-	useCase := filmUseCase.New(filmRepo)
+	useCase := filmUseCase.New(filmRepo, mockRepository.NewActorRepository(), mockRepository.NewExternalIdRepository(), auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()), revisionService.New(mockRepository.NewRevisionRepository(), mockRepository.NewFilmRepository(), mockRepository.NewActorRepository()), true)
 
 	testId := ""
 	var film *aggregate.FilmAggregate
 	t.Run("Should create film", func(t *testing.T) {
-		create, err := useCase.Create(context.Background(), appDto.CreateFilmUseCaseDto{Name: "Titanic", ReleaseDate: time.Now().AddDate(-13, 0, 0), Rate: 10})
+		create, err := useCase.Create(context.Background(), appDto.CreateFilmUseCaseDto{Name: "Titanic", ReleaseDate: time.Now().AddDate(-13, 0, 0)})
 		assert.Nil(t, err)
 		assert.NotNil(t, create)
 
@@ -35,12 +40,12 @@ func TestFilmUseCase(t *testing.T) {
 
 	t.Run("Should update film", func(t *testing.T) {
 		film.Film.Name = "Titanic 2"
-		update, err := useCase.Update(context.Background(), film)
+		update, err := useCase.Update(context.Background(), film, false)
 		assert.Nil(t, err)
 		assert.Equal(t, "Titanic 2", update.Film.Name)
 
 		film.Film.Id = "incorrect"
-		update, err = useCase.Update(context.Background(), film)
+		update, err = useCase.Update(context.Background(), film, false)
 		assert.Nil(t, update)
 		var appErr *appErrors.AppError
 		if errors.As(err, &appErr) {
@@ -72,17 +77,23 @@ func TestFilmUseCase(t *testing.T) {
 		}
 	})
 
-	film, err := useCase.Create(context.Background(), appDto.CreateFilmUseCaseDto{Name: "Titanic", ReleaseDate: time.Now().AddDate(-13, 0, 0), Rate: 10})
+	film, err := useCase.Create(context.Background(), appDto.CreateFilmUseCaseDto{Name: "Titanic", ReleaseDate: time.Now().AddDate(-13, 0, 0)})
 	if err != nil {
 		t.Fatal("incorrect create")
 	}
 
 	t.Run("Should search by name and actor name", func(t *testing.T) {
-		res, err := useCase.SearchByNameAndActorName(context.Background(), "Tita")
+		res, err := useCase.SearchByNameAndActorName(context.Background(), *domainQuery.NewFilmSearchQuery("tita"))
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(res.Films))
 		assert.Equal(t, 1, res.PageCount)
-		res, err = useCase.SearchByNameAndActorName(context.Background(), "Murmur")
+		assert.Equal(t, 1, res.Total)
+		assert.Equal(t, "<b>Tita</b>nic", res.Films[0].Search.Name)
+		res, err = useCase.SearchByNameAndActorName(context.Background(), *domainQuery.NewFilmSearchQuery("Titanik"))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res.Films))
+		assert.True(t, res.Films[0].Search.TitleMatch)
+		res, err = useCase.SearchByNameAndActorName(context.Background(), *domainQuery.NewFilmSearchQuery("Murmur"))
 		assert.Nil(t, res)
 		var appErr *appErrors.AppError
 		if errors.As(err, &appErr) {
@@ -94,4 +105,88 @@ func TestFilmUseCase(t *testing.T) {
 
 	db := inMemDb.New()
 	db.CleanUp()
+
+	t.Run("Should paginate by cursor", func(t *testing.T) {
+		for _, name := range []string{"E", "C", "A", "D", "B"} {
+			_, err := useCase.Create(context.Background(), appDto.CreateFilmUseCaseDto{Name: name, ReleaseDate: time.Now().AddDate(-1, 0, 0)})
+			assert.Nil(t, err)
+		}
+		names := func(res *appDto.FilmGetByQueryResult) string {
+			result := ""
+			for _, film := range res.Films {
+				result += film.Film.Name
+			}
+			return result
+		}
+		page := func(token string) *appDto.FilmGetByQueryResult {
+			query := domainQuery.NewFilmRepositoryQuery()
+			query.PageCount = 2
+			cursor, err := domainQuery.DecodeCursor(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			query.Cursor = cursor
+			res, err := useCase.GetByQuery(context.Background(), *query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+
+		query := domainQuery.NewFilmRepositoryQuery()
+		query.PageCount, query.SortField = 2, "name"
+		first, err := useCase.GetByQuery(context.Background(), *query)
+		assert.Nil(t, err)
+		assert.Equal(t, "AB", names(first))
+		assert.Equal(t, "", first.Prev)
+
+		second := page(first.Next)
+		assert.Equal(t, "CD", names(second))
+		third := page(second.Next)
+		assert.Equal(t, "E", names(third))
+		assert.Equal(t, "", third.Next)
+
+		back := page(third.Prev)
+		assert.Equal(t, "CD", names(back))
+		back = page(back.Prev)
+		assert.Equal(t, "AB", names(back))
+		assert.Equal(t, "", back.Prev)
+		assert.NotEqual(t, "", back.Next)
+	})
+
+	t.Run("Should create film with cast in one step", func(t *testing.T) {
+		actorId := uuid.New().String()
+		db.Actor = append(db.Actor, &model.Actor{Id: actorId, Name: "Existing", Gender: "male", Birthday: time.Now().AddDate(-40, 0, 0)})
+		newActor := appDto.CreateActorUseCaseDto{Name: "Inline", Gender: "female", Birthday: time.Now().AddDate(-20, 0, 0)}
+
+		film, err := useCase.Create(context.Background(), appDto.CreateFilmUseCaseDto{
+			Name: "With cast", ReleaseDate: time.Now().AddDate(-1, 0, 0),
+			ActorIds: []string{actorId, actorId},
+			Actors:   []appDto.CreateActorUseCaseDto{newActor},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(film.Actors))
+		assert.Equal(t, actorId, film.Actors[0].Id)
+		assert.Equal(t, "Inline", film.Actors[1].Name)
+		assert.Equal(t, 2, len(db.Actor))
+
+		films := len(db.Film)
+		film, err = useCase.Create(context.Background(), appDto.CreateFilmUseCaseDto{
+			Name: "Broken cast", ReleaseDate: time.Now().AddDate(-1, 0, 0),
+			ActorIds: []string{uuid.New().String()},
+			Actors:   []appDto.CreateActorUseCaseDto{newActor},
+		})
+		assert.Nil(t, film)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusNotFound, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+		assert.Equal(t, films, len(db.Film))
+		assert.Equal(t, 2, len(db.Actor))
+		assert.Equal(t, 2, len(db.ActorFilm))
+	})
+
+	db.CleanUp()
 }
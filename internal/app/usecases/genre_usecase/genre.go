@@ -0,0 +1,181 @@
+package genreUseCase
+
+import (
+	"context"
+	"database/sql"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	GenreUseCase interface {
+		Create(ctx context.Context, data appDto.CreateGenreUseCaseDto) (*model.Genre, error)
+		Update(ctx context.Context, data *model.Genre) (*model.Genre, error)
+		Delete(ctx context.Context, id string) error
+		GetAll(ctx context.Context) ([]*model.Genre, error)
+		AddFilm(ctx context.Context, genreId string, filmIds ...string) error
+		RemoveFilm(ctx context.Context, genreId string, filmIds ...string) error
+	}
+
+	genreUseCase struct {
+		repository.GenreRepository
+		AuditService auditService.Service
+	}
+
+	// genreFilms фильмы, добавленные в жанр или убранные из него, для журнала изменений
+	genreFilms struct {
+		FilmIds []string `json:"filmIds"`
+	}
+)
+
+func (g *genreUseCase) Create(ctx context.Context, data appDto.CreateGenreUseCaseDto) (*model.Genre, error) {
+	genre := &model.Genre{Id: uuid.New().String(), Name: data.Name}
+	if err := appValidator.New().Struct(genre); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: GenreUseCase, method: Create ", "error: ", err.Error())
+	}
+
+	has, err := g.GenreRepository.HasByName(ctx, genre.Name)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: GenreUseCase, method: Create ", "repository has by name error: ", err.Error())
+	}
+	if has {
+		return nil, appErrors.Conflict(constants.GenreNameExist)
+	}
+
+	var created *model.Genre
+	err = g.AuditService.Track(ctx, constants.AuditCreate, constants.AuditGenre, genre.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+		created, err = g.GenreRepository.Create(ctx, genre)
+		return nil, created, err
+	})
+	// проверка выше не защищает от параллельного создания, его отклоняет уникальный индекс
+	if err == repository.ErrGenreNameExist {
+		return nil, appErrors.Conflict(constants.GenreNameExist)
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: GenreUseCase, method: Create ", "repository create error: ", err.Error())
+	}
+
+	return created, nil
+}
+
+func (g *genreUseCase) Update(ctx context.Context, data *model.Genre) (*model.Genre, error) {
+	if err := appValidator.New().Struct(data); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: GenreUseCase, method: Update ", "error: ", err.Error())
+	}
+
+	var updated *model.Genre
+	err := g.AuditService.Track(ctx, constants.AuditUpdate, constants.AuditGenre, data.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := g.GenreRepository.GetById(ctx, data.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if before.Name != data.Name {
+			has, err := g.GenreRepository.HasByName(ctx, data.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if has {
+				return nil, nil, repository.ErrGenreNameExist
+			}
+		}
+		updated, err = g.GenreRepository.Update(ctx, data)
+		return before, updated, err
+	})
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err == repository.ErrGenreNameExist {
+		return nil, appErrors.Conflict(constants.GenreNameExist)
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: GenreUseCase, method: Update ", "repository update error: ", err.Error())
+	}
+
+	return updated, nil
+}
+
+func (g *genreUseCase) Delete(ctx context.Context, id string) error {
+	if !isUuid(id) {
+		return appErrors.NotFound("")
+	}
+	err := g.AuditService.Track(ctx, constants.AuditDelete, constants.AuditGenre, id, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := g.GenreRepository.GetById(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return before, nil, g.GenreRepository.Delete(ctx, id)
+	})
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: GenreUseCase, method: Delete ", "error: ", err.Error())
+	}
+	return nil
+}
+
+func (g *genreUseCase) GetAll(ctx context.Context) ([]*model.Genre, error) {
+	genres, err := g.GenreRepository.GetAll(ctx)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: GenreUseCase, method: GetAll ", "error: ", err.Error())
+	}
+	return genres, nil
+}
+
+func (g *genreUseCase) AddFilm(ctx context.Context, genreId string, filmIds ...string) error {
+	if len(filmIds) == 0 {
+		return appErrors.BadRequest("", "target: GenreUseCase, method: AddFilm ", "error: ", "not id or ids")
+	}
+	if !isUuid(genreId) || !isUuid(filmIds...) {
+		return appErrors.NotFound("")
+	}
+	err := g.AuditService.Track(ctx, constants.AuditLink, constants.AuditGenre, genreId, func(ctx context.Context) (interface{}, interface{}, error) {
+		return nil, genreFilms{FilmIds: filmIds}, g.GenreRepository.AddFilm(ctx, genreId, filmIds...)
+	})
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: GenreUseCase, method: AddFilm ", "added repository error: ", err.Error())
+	}
+	return nil
+}
+
+func (g *genreUseCase) RemoveFilm(ctx context.Context, genreId string, filmIds ...string) error {
+	if len(filmIds) == 0 {
+		return appErrors.BadRequest("", "target: GenreUseCase, method: RemoveFilm ", "error: ", "not id or ids")
+	}
+	if !isUuid(genreId) || !isUuid(filmIds...) {
+		return appErrors.NotFound("")
+	}
+	err := g.AuditService.Track(ctx, constants.AuditUnlink, constants.AuditGenre, genreId, func(ctx context.Context) (interface{}, interface{}, error) {
+		return genreFilms{FilmIds: filmIds}, nil, g.GenreRepository.RemoveFilm(ctx, genreId, filmIds...)
+	})
+	if err != nil {
+		return appErrors.InternalServerError("", "target: GenreUseCase, method: RemoveFilm ", "remove repository error: ", err.Error())
+	}
+	return nil
+}
+
+// isUuid id жанров и фильмов в базе - uuid, остальные значения отклоняются до запроса
+func isUuid(ids ...string) bool {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func New(genreRepository repository.GenreRepository, auditService auditService.Service) GenreUseCase {
+	return &genreUseCase{
+		GenreRepository: genreRepository,
+		AuditService:    auditService,
+	}
+}
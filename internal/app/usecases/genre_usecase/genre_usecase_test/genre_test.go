@@ -0,0 +1,138 @@
+package genre_usecase_test
+
+import (
+	"context"
+	"errors"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	genreUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/genre_usecase"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// racedGenreRepository параллельный жанр с тем же названием появляется между проверкой и созданием
+type racedGenreRepository struct {
+	repository.GenreRepository
+}
+
+func (r racedGenreRepository) HasByName(ctx context.Context, name string) (bool, error) {
+	return false, nil
+}
+
+func TestGenreUseCase(t *testing.T) {
+	genreRepo := mockRepository.NewGenreRepository()
+	useCase := genreUseCase.New(genreRepo, auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()))
+
+	var genre *model.Genre
+	t.Run("Should create genre", func(t *testing.T) {
+		create, err := useCase.Create(context.Background(), appDto.CreateGenreUseCaseDto{Name: "Drama"})
+		assert.Nil(t, err)
+		assert.NotNil(t, create)
+		genre = create
+	})
+
+	t.Run("Should conflict genre name", func(t *testing.T) {
+		create, err := useCase.Create(context.Background(), appDto.CreateGenreUseCaseDto{Name: "Drama"})
+		assert.Nil(t, create)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusConflict, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+
+		raced := genreUseCase.New(racedGenreRepository{genreRepo}, auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()))
+		create, err = raced.Create(context.Background(), appDto.CreateGenreUseCaseDto{Name: "Drama"})
+		assert.Nil(t, create)
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusConflict, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+	})
+
+	t.Run("Should update genre", func(t *testing.T) {
+		update, err := useCase.Update(context.Background(), &model.Genre{Id: genre.Id, Name: "Horror"})
+		assert.Nil(t, err)
+		assert.Equal(t, "Horror", update.Name)
+
+		update, err = useCase.Update(context.Background(), &model.Genre{Id: uuid.New().String(), Name: "Horror"})
+		assert.Nil(t, update)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusNotFound, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+
+		update, err = useCase.Update(context.Background(), &model.Genre{Id: genre.Id, Name: "Horror"})
+		assert.Nil(t, err)
+		assert.Equal(t, "Horror", update.Name)
+		comedy, err := useCase.Create(context.Background(), appDto.CreateGenreUseCaseDto{Name: "Comedy"})
+		assert.Nil(t, err)
+		update, err = useCase.Update(context.Background(), &model.Genre{Id: comedy.Id, Name: "Horror"})
+		assert.Nil(t, update)
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusConflict, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+		assert.Nil(t, useCase.Delete(context.Background(), comedy.Id))
+	})
+
+	t.Run("Should add film to genre", func(t *testing.T) {
+		err := useCase.AddFilm(context.Background(), genre.Id)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusBadRequest, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+
+		err = useCase.AddFilm(context.Background(), genre.Id, "incorrectfilmid")
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusNotFound, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+
+		// id не uuid отклоняются до запроса к базе
+		for _, err := range []error{
+			useCase.AddFilm(context.Background(), "incorrect", uuid.New().String()),
+			useCase.RemoveFilm(context.Background(), genre.Id, "incorrect"),
+			useCase.Delete(context.Background(), "incorrect"),
+		} {
+			if errors.As(err, &appErr) {
+				assert.Equal(t, http.StatusNotFound, appErr.Code)
+			} else {
+				t.Fatal("incorrect error type")
+			}
+		}
+	})
+
+	t.Run("Should delete genre", func(t *testing.T) {
+		err := useCase.Delete(context.Background(), genre.Id)
+		assert.Nil(t, err)
+		genres, err := useCase.GetAll(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(genres))
+
+		err = useCase.Delete(context.Background(), genre.Id)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusNotFound, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+	})
+
+	db := inMemDb.New()
+	db.CleanUp()
+}
@@ -0,0 +1,147 @@
+package importUseCase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// planActor как planFilm: актер ищется по id, внешним идентификаторам, затем по имени и дате рождения
+func (i *importUseCase) planActor(ctx context.Context, p *plan, fields map[string]string, s *step) error {
+	birthday, err := parseDate(fields, "birthday")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	ids := externalIds(fields)
+	if err := aggregate.ValidateActorExternalIds(ids); err != nil {
+		s.fail(err)
+		return nil
+	}
+	id, err := parseId(fields, "id")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	if id == "" {
+		if id, err = externalOwner(ctx, i.ExternalIdRepository.ActorIds, ids); err != nil {
+			return err
+		}
+	}
+	existing, err := i.matchActor(ctx, id, fields["name"], birthday)
+	if err != nil {
+		return err
+	}
+	current := map[string]string{}
+	if existing != nil && len(ids) > 0 {
+		if current, err = i.ExternalIdRepository.GetActor(ctx, existing.Actor.Id); err != nil {
+			return err
+		}
+	}
+
+	actor := model.Actor{Id: uuid.New().String()}
+	if existing != nil {
+		actor = existing.Actor
+		actor.Version = 0
+	}
+	if name, ok := fields["name"]; ok {
+		actor.Name = name
+	}
+	if gender, ok := fields["gender"]; ok {
+		actor.Gender = gender
+	}
+	if birthday != nil {
+		actor.Birthday = *birthday
+	}
+
+	actorAggregate, err := aggregate.NewActorAggregate(actor)
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	if line, ok := p.claim(s.result.Line, append(actorKeys(actor), externalKeys("actor", ids)...)...); !ok {
+		s.fail(duplicateError(line))
+		return nil
+	}
+
+	s.result.Id = actor.Id
+	link := linkExternal(i.ExternalIdRepository.ActorIds, i.ExternalIdRepository.SetActor, actor.Id, ids)
+	switch {
+	case existing == nil:
+		s.plan(constants.ImportCreated, sequence(i.createActor(actorAggregate), link))
+	case !sameActor(existing.Actor, actor):
+		s.plan(constants.ImportUpdated, sequence(i.updateActor(actorAggregate), link))
+	case !sameExternalIds(current, ids):
+		// новые внешние идентификаторы тоже изменение записи
+		s.plan(constants.ImportUpdated, link)
+	default:
+		s.skip()
+	}
+	return nil
+}
+
+// matchActor актер каталога по id, иначе по имени и дате рождения. Не найден - nil без ошибки
+func (i *importUseCase) matchActor(ctx context.Context, id string, name string, birthday *time.Time) (*aggregate.ActorAggregate, error) {
+	if id != "" {
+		actor, err := i.ActorRepository.GetById(ctx, id)
+		if err != sql.ErrNoRows {
+			return actor, err
+		}
+	}
+	if name == "" || birthday == nil {
+		return nil, nil
+	}
+	actor, err := i.ActorRepository.GetByNameAndBirthday(ctx, name, *birthday)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return actor, err
+}
+
+func (i *importUseCase) createActor(actor *aggregate.ActorAggregate) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return i.AuditService.Track(ctx, constants.AuditCreate, constants.AuditActor, actor.Actor.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+			created, err := i.ActorRepository.Create(ctx, actor)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := i.RevisionService.RecordActors(ctx, constants.AuditCreate, actor.Actor.Id); err != nil {
+				return nil, nil, err
+			}
+			return nil, &aggregate.ActorAggregate{Actor: created.Actor}, nil
+		})
+	}
+}
+
+func (i *importUseCase) updateActor(actor *aggregate.ActorAggregate) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return i.AuditService.Track(ctx, constants.AuditUpdate, constants.AuditActor, actor.Actor.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+			before, err := i.ActorRepository.GetById(ctx, actor.Actor.Id)
+			if err != nil {
+				return nil, nil, err
+			}
+			updated, err := i.ActorRepository.Update(ctx, &aggregate.ActorAggregate{Actor: actor.Actor})
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := i.RevisionService.RecordActors(ctx, constants.AuditUpdate, actor.Actor.Id); err != nil {
+				return nil, nil, err
+			}
+			return &aggregate.ActorAggregate{Actor: before.Actor}, &aggregate.ActorAggregate{Actor: updated.Actor}, nil
+		})
+	}
+}
+
+func sameActor(a, b model.Actor) bool {
+	return a.Name == b.Name && a.Gender == b.Gender && sameDate(a.Birthday, b.Birthday)
+}
+
+func actorKeys(actor model.Actor) []string {
+	return []string{"actor:" + actor.Id, "actor:" + strings.ToLower(actor.Name) + "|" + actor.Birthday.Format(time.DateOnly)}
+}
@@ -0,0 +1,177 @@
+package importUseCase
+
+import (
+	"context"
+	"errors"
+
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+// planCredit фильм и актер должны уже быть в каталоге. Роль, которая у актера в фильме уже есть, обновляет
+// персонажа и позицию в титрах, без billingOrder новая роль встает в конец титров фильма
+func (i *importUseCase) planCredit(ctx context.Context, p *plan, fields map[string]string, s *step) error {
+	filmRelease, err := parseDate(fields, "filmRelease")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	actorBirthday, err := parseDate(fields, "actorBirthday")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	billingOrder, err := parseInt(fields, "billingOrder")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+
+	filmId, err := parseId(fields, "film")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	actorId, err := parseId(fields, "actor")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+
+	film, err := i.matchFilm(ctx, filmId, fields["filmName"], filmRelease)
+	if err != nil {
+		return err
+	}
+	if film == nil {
+		s.fail(errors.New("film not found"))
+		return nil
+	}
+	actor, err := i.matchActor(ctx, actorId, fields["actorName"], actorBirthday)
+	if err != nil {
+		return err
+	}
+	if actor == nil {
+		s.fail(errors.New("actor not found"))
+		return nil
+	}
+	s.result.FilmId, s.result.ActorId = film.Film.Id, actor.Actor.Id
+
+	credit := &model.Credit{
+		ActorId:   actor.Actor.Id,
+		FilmId:    film.Film.Id,
+		Role:      fields["role"],
+		Character: optionalString(fields, "character"),
+	}
+	if credit.Role == "" {
+		credit.Role = constants.CreditActor
+	}
+	current := findCredit(actor.Credits, credit.FilmId, credit.Role)
+	switch {
+	case billingOrder != nil:
+		credit.BillingOrder = *billingOrder
+	case current != nil:
+		credit.BillingOrder = current.BillingOrder
+	default:
+		credit.BillingOrder = p.nextBilling(film)
+	}
+	if current != nil && credit.Character == nil {
+		credit.Character = current.Character
+	}
+
+	if err := aggregate.ValidateCredits([]*model.Credit{credit}); err != nil {
+		s.fail(err)
+		return nil
+	}
+	if line, ok := p.claim(s.result.Line, "credit:"+credit.ActorId+"|"+credit.FilmId+"|"+credit.Role); !ok {
+		s.fail(duplicateError(line))
+		return nil
+	}
+	p.billing[film.Film.Id] = max(p.billing[film.Film.Id], credit.BillingOrder+1)
+
+	switch {
+	case current == nil:
+		s.plan(constants.ImportCreated, i.linkCredit(credit))
+	case equalPtr(current.Character, credit.Character) && current.BillingOrder == credit.BillingOrder:
+		s.skip()
+	default:
+		s.plan(constants.ImportUpdated, i.replaceCredit(credit))
+	}
+	return nil
+}
+
+// nextBilling позиция после последней в титрах фильма с учетом уже запланированных строк
+func (p *plan) nextBilling(film *aggregate.FilmAggregate) int {
+	next, ok := p.billing[film.Film.Id]
+	if !ok {
+		for _, credit := range film.Credits {
+			next = max(next, credit.BillingOrder+1)
+		}
+	}
+	return next
+}
+
+func findCredit(credits []*model.Credit, filmId string, role string) *model.Credit {
+	for _, credit := range credits {
+		if credit.FilmId == filmId && credit.Role == role {
+			return credit
+		}
+	}
+	return nil
+}
+
+func (i *importUseCase) linkCredit(credit *model.Credit) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return i.trackCredits(ctx, constants.AuditLink, credit.ActorId, func(ctx context.Context) error {
+			return i.ActorRepository.AddFilm(ctx, credit.ActorId, credit)
+		})
+	}
+}
+
+// replaceCredit меняет одну роль актера, остальные его титры сохраняются как есть
+func (i *importUseCase) replaceCredit(credit *model.Credit) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return i.trackCredits(ctx, constants.AuditReplaceLinks, credit.ActorId, func(ctx context.Context) error {
+			actor, err := i.ActorRepository.GetById(ctx, credit.ActorId)
+			if err != nil {
+				return err
+			}
+			credits := make([]*model.Credit, 0, len(actor.Credits))
+			for _, item := range actor.Credits {
+				if item.FilmId == credit.FilmId && item.Role == credit.Role {
+					item = credit
+				}
+				credits = append(credits, item)
+			}
+			return i.ActorRepository.SetFilms(ctx, credit.ActorId, credits...)
+		})
+	}
+}
+
+// trackCredits как у ActorUseCase: журнал с титрами актера до и после, ревизии актера и фильмов с изменившимися титрами
+func (i *importUseCase) trackCredits(ctx context.Context, action string, actorId string, fn func(ctx context.Context) error) error {
+	return i.AuditService.Track(ctx, action, constants.AuditActor, actorId, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := i.ActorRepository.GetById(ctx, actorId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := fn(ctx); err != nil {
+			return nil, nil, err
+		}
+		after, err := i.ActorRepository.GetById(ctx, actorId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := i.RevisionService.RecordActors(ctx, action, actorId); err != nil {
+			return nil, nil, err
+		}
+		filmIds := revisionService.ChangedLinks(before.Credits, after.Credits, func(credit *model.Credit) string {
+			return credit.FilmId
+		})
+		if err := i.RevisionService.RecordFilms(ctx, action, filmIds...); err != nil {
+			return nil, nil, err
+		}
+		return &aggregate.ActorAggregate{Credits: before.Credits}, &aggregate.ActorAggregate{Credits: after.Credits}, nil
+	})
+}
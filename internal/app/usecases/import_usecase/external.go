@@ -0,0 +1,70 @@
+package importUseCase
+
+import (
+	"context"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+)
+
+// externalIds значения колонок внешних идентификаторов строки, источник -> идентификатор
+func externalIds(fields map[string]string) map[string]string {
+	ids := make(map[string]string, len(constants.ExternalSources))
+	for _, source := range constants.ExternalSources {
+		if value, ok := fields[source]; ok {
+			ids[source] = value
+		}
+	}
+	return ids
+}
+
+// externalOwner id записи, связанной с первым из ids в порядке constants.ExternalSources. Нет такой - пустая строка.
+// Связи остальных идентификаторов с другими записями проверяет linkExternal
+func externalOwner(ctx context.Context, lookup func(ctx context.Context, source string, externalIds ...string) (map[string]string, error), ids map[string]string) (string, error) {
+	for _, source := range constants.ExternalSources {
+		externalId, ok := ids[source]
+		if !ok {
+			continue
+		}
+		owners, err := lookup(ctx, source, externalId)
+		if err != nil {
+			return "", err
+		}
+		if owner, ok := owners[externalId]; ok {
+			return owner, nil
+		}
+	}
+	return "", nil
+}
+
+// linkExternal связывает ids с записью id. Идентификатор другой записи - ошибка строки repository.ErrExternalIdConflict
+func linkExternal(lookup func(ctx context.Context, source string, externalIds ...string) (map[string]string, error), set func(ctx context.Context, id string, ids map[string]string) error, id string, ids map[string]string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := repository.CheckExternalIds(ctx, lookup, id, ids); err != nil {
+			return err
+		}
+		return set(ctx, id, ids)
+	}
+}
+
+// sameExternalIds все ids уже связаны с записью, current - ее текущие идентификаторы
+func sameExternalIds(current map[string]string, ids map[string]string) bool {
+	for source, externalId := range ids {
+		if current[source] != externalId {
+			return false
+		}
+	}
+	return true
+}
+
+// externalKeys ключи plan.claim: один идентификатор источника не может достаться двум строкам файла
+func externalKeys(kind string, ids map[string]string) []string {
+	keys := make([]string, 0, len(ids))
+	for source, externalId := range ids {
+		keys = append(keys, kind+":"+source+":"+externalId)
+	}
+	return keys
+}
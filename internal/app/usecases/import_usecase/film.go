@@ -0,0 +1,161 @@
+package importUseCase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// planFilm фильм ищется по id, затем по внешним идентификаторам, затем по названию и дате. Найденный фильм
+// обновляется полями из строки, пустые поля остаются прежними. rate учитывается только вместе с ручной оценкой
+func (i *importUseCase) planFilm(ctx context.Context, p *plan, fields map[string]string, s *step) error {
+	release, err := parseDate(fields, "release")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	rate, err := parseFloat(fields, "rate")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	ids := externalIds(fields)
+	if err := aggregate.ValidateFilmExternalIds(ids); err != nil {
+		s.fail(err)
+		return nil
+	}
+	id, err := parseId(fields, "id")
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	if id == "" {
+		if id, err = externalOwner(ctx, i.ExternalIdRepository.FilmIds, ids); err != nil {
+			return err
+		}
+	}
+	existing, err := i.matchFilm(ctx, id, fields["name"], release)
+	if err != nil {
+		return err
+	}
+	current := map[string]string{}
+	if existing != nil && len(ids) > 0 {
+		if current, err = i.ExternalIdRepository.GetFilm(ctx, existing.Film.Id); err != nil {
+			return err
+		}
+	}
+
+	film := model.Film{Id: uuid.New().String()}
+	if existing != nil {
+		film = existing.Film
+		film.Version = 0
+	}
+	if name, ok := fields["name"]; ok {
+		film.Name = name
+	}
+	if description := optionalString(fields, "description"); description != nil {
+		film.Description = description
+	}
+	if release != nil {
+		film.ReleaseDate = *release
+	}
+	if rate != nil && i.honorManualRate {
+		film.Rate, film.ManualRate = *rate, i.manualRate(*rate)
+	}
+
+	filmAggregate, err := aggregate.NewFilmAggregate(film)
+	if err != nil {
+		s.fail(err)
+		return nil
+	}
+	if line, ok := p.claim(s.result.Line, append(filmKeys(film), externalKeys("film", ids)...)...); !ok {
+		s.fail(duplicateError(line))
+		return nil
+	}
+
+	s.result.Id = film.Id
+	link := linkExternal(i.ExternalIdRepository.FilmIds, i.ExternalIdRepository.SetFilm, film.Id, ids)
+	switch {
+	case existing == nil:
+		s.plan(constants.ImportCreated, sequence(i.createFilm(filmAggregate), link))
+	case !sameFilm(existing.Film, film):
+		s.plan(constants.ImportUpdated, sequence(i.updateFilm(filmAggregate), link))
+	case !sameExternalIds(current, ids):
+		// новые внешние идентификаторы тоже изменение записи
+		s.plan(constants.ImportUpdated, link)
+	default:
+		s.skip()
+	}
+	return nil
+}
+
+// matchFilm фильм каталога по id, иначе по названию и дате выхода. Не найден - nil без ошибки
+func (i *importUseCase) matchFilm(ctx context.Context, id string, name string, release *time.Time) (*aggregate.FilmAggregate, error) {
+	if id != "" {
+		film, err := i.FilmRepository.GetById(ctx, id)
+		if err != sql.ErrNoRows {
+			return film, err
+		}
+	}
+	if name == "" || release == nil {
+		return nil, nil
+	}
+	film, err := i.FilmRepository.GetByNameAndRelease(ctx, name, *release)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return film, err
+}
+
+func (i *importUseCase) createFilm(film *aggregate.FilmAggregate) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return i.AuditService.Track(ctx, constants.AuditCreate, constants.AuditFilm, film.Film.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+			created, err := i.FilmRepository.Create(ctx, film)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := i.RevisionService.RecordFilms(ctx, constants.AuditCreate, film.Film.Id); err != nil {
+				return nil, nil, err
+			}
+			return nil, &aggregate.FilmAggregate{Film: created.Film}, nil
+		})
+	}
+}
+
+func (i *importUseCase) updateFilm(film *aggregate.FilmAggregate) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return i.AuditService.Track(ctx, constants.AuditUpdate, constants.AuditFilm, film.Film.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+			before, err := i.FilmRepository.GetById(ctx, film.Film.Id)
+			if err != nil {
+				return nil, nil, err
+			}
+			updated, err := i.FilmRepository.Update(ctx, &aggregate.FilmAggregate{Film: film.Film})
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := i.RevisionService.RecordFilms(ctx, constants.AuditUpdate, film.Film.Id); err != nil {
+				return nil, nil, err
+			}
+			return &aggregate.FilmAggregate{Film: before.Film}, &aggregate.FilmAggregate{Film: updated.Film}, nil
+		})
+	}
+}
+
+func sameFilm(a, b model.Film) bool {
+	return a.Name == b.Name && equalPtr(a.Description, b.Description) && sameDate(a.ReleaseDate, b.ReleaseDate) && equalPtr(a.ManualRate, b.ManualRate)
+}
+
+func filmKeys(film model.Film) []string {
+	return []string{"film:" + film.Id, "film:" + strings.ToLower(film.Name) + "|" + film.ReleaseDate.Format(time.DateOnly)}
+}
+
+// sameDate сравнение как у колонок DATE, время суток не учитывается
+func sameDate(a, b time.Time) bool {
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
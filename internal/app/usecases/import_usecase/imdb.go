@@ -0,0 +1,425 @@
+package importUseCase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"slices"
+	"time"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// imdbLookupSize сколько идентификаторов IMDb ищется одним запросом
+const imdbLookupSize = 1000
+
+// DefaultImdbTitleTypes titleType, которые загружаются, если типы не заданы
+var DefaultImdbTitleTypes = []string{"movie"}
+
+// imdbStages этапы импорта IMDb по порядку, каждый сохраняется пачками и продолжается с места прерывания
+var imdbStages = []string{constants.ImportFilms, constants.ImportActors, constants.ImportCredits}
+
+// imdbRun состояние одного запуска ImportImdb
+type imdbRun struct {
+	data     appDto.ImdbImportUseCaseDto
+	progress model.ImdbProgress
+	plan     *plan
+}
+
+// ImportImdb файлы датасета читаются потоком, в памяти остаются только отобранные записи. Фильмы и актеры сначала ищутся
+// по tconst и nconst, затем как в Import по названию или имени и году, без даты из IMDb используется 1 января года.
+// Дата найденной записи меняется, только если не совпал год, пол найденного актера не меняется
+func (i *importUseCase) ImportImdb(ctx context.Context, data appDto.ImdbImportUseCaseDto) (*appDto.ImdbImportReport, error) {
+	if data.BatchSize < 0 || data.MinVotes < 0 {
+		return nil, appErrors.BadRequest("", "target: ImportUseCase, method: ImportImdb ", "negative batch size or min votes")
+	}
+	if data.BatchSize == 0 {
+		data.BatchSize = DefaultBatchSize
+	}
+	if len(data.TitleTypes) == 0 {
+		data.TitleTypes = DefaultImdbTitleTypes
+	}
+	fingerprint, err := imdbFingerprint(data)
+	if err != nil {
+		return nil, appErrors.BadRequest("", "target: ImportUseCase, method: ImportImdb ", "dataset error: ", err.Error())
+	}
+
+	report := &appDto.ImdbImportReport{
+		Films:   appDto.ImportReport{Kind: constants.ImportFilms, Rows: []appDto.ImportRowResult{}},
+		Actors:  appDto.ImportReport{Kind: constants.ImportActors, Rows: []appDto.ImportRowResult{}},
+		Credits: appDto.ImportReport{Kind: constants.ImportCredits, Rows: []appDto.ImportRowResult{}},
+	}
+	run := &imdbRun{
+		data:     data,
+		progress: model.ImdbProgress{Fingerprint: fingerprint, Stage: imdbStages[0]},
+		plan:     &plan{seen: make(map[string]int), billing: make(map[string]int)},
+	}
+	saved, err := i.ImdbRepository.GetProgress(ctx)
+	switch {
+	case err == nil && !data.Restart && saved.Fingerprint == fingerprint:
+		run.progress, report.Resumed = *saved, saved
+	case err != nil && err != sql.ErrNoRows:
+		return nil, imdbError("get progress", err)
+	}
+
+	if err := i.importImdb(ctx, run, report); err != nil {
+		var pathError *fs.PathError
+		if errors.As(err, &pathError) {
+			return nil, appErrors.BadRequest("", "target: ImportUseCase, method: ImportImdb ", "dataset error: ", err.Error())
+		}
+		return nil, imdbError("import", err)
+	}
+	if err := i.ImdbRepository.DeleteProgress(ctx); err != nil {
+		return nil, imdbError("delete progress", err)
+	}
+	return report, nil
+}
+
+func (i *importUseCase) importImdb(ctx context.Context, run *imdbRun, report *appDto.ImdbImportReport) error {
+	ratings, err := readImdbRatings(ctx, run.data)
+	if err != nil {
+		return err
+	}
+	titles, err := readImdbTitles(ctx, run.data, ratings, &report.Films)
+	if err != nil {
+		return err
+	}
+	err = i.imdbStage(ctx, run, constants.ImportFilms, len(titles), &report.Films, func(ctx context.Context, from int, to int) ([]*step, error) {
+		return i.planImdbFilms(ctx, run.plan, titles[from:to])
+	})
+	if err != nil {
+		return err
+	}
+
+	credits, genders, err := readImdbCredits(ctx, run.data, titles)
+	if err != nil {
+		return err
+	}
+	names, err := readImdbNames(ctx, run.data, genders, &report.Actors)
+	if err != nil {
+		return err
+	}
+	err = i.imdbStage(ctx, run, constants.ImportActors, len(names), &report.Actors, func(ctx context.Context, from int, to int) ([]*step, error) {
+		return i.planImdbActors(ctx, run.plan, names[from:to])
+	})
+	if err != nil {
+		return err
+	}
+
+	filmIds, err := imdbLookup(ctx, i.ExternalIdRepository.FilmIds, titles, func(title *imdbTitle) string {
+		return title.tconst
+	})
+	if err != nil {
+		return err
+	}
+	actorIds, err := imdbLookup(ctx, i.ExternalIdRepository.ActorIds, names, func(name *imdbName) string {
+		return name.nconst
+	})
+	if err != nil {
+		return err
+	}
+	return i.imdbStage(ctx, run, constants.ImportCredits, len(credits), &report.Credits, func(ctx context.Context, from int, to int) ([]*step, error) {
+		return i.planImdbCredits(ctx, run.plan, credits[from:to], filmIds, actorIds)
+	})
+}
+
+// imdbStage сохраняет total записей этапа пачками и после каждой пачки запоминает прогресс. Записи, сохраненные
+// до прерывания, и этапы до сохраненного пропускаются. Повтор уже сохраненной пачки безопасен, записи найдутся по tconst и nconst
+func (i *importUseCase) imdbStage(ctx context.Context, run *imdbRun, stage string, total int, report *appDto.ImportReport, planBatch func(ctx context.Context, from int, to int) ([]*step, error)) error {
+	start := 0
+	switch saved := slices.Index(imdbStages, run.progress.Stage); {
+	case saved > slices.Index(imdbStages, stage):
+		start = total
+	case saved == slices.Index(imdbStages, stage):
+		start = min(run.progress.Done, total)
+	}
+	report.Skipped += start
+
+	for from := start; from < total; from += run.data.BatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		to := min(from+run.data.BatchSize, total)
+		steps, err := planBatch(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		i.commit(ctx, steps, run.data.BatchSize)
+		countSteps(report, steps)
+
+		run.progress.Stage, run.progress.Done = stage, to
+		if err := i.ImdbRepository.SaveProgress(ctx, &run.progress); err != nil {
+			return err
+		}
+		if run.data.Progress != nil {
+			run.data.Progress(stage, to, total)
+		}
+	}
+	return nil
+}
+
+func (i *importUseCase) planImdbFilms(ctx context.Context, p *plan, titles []*imdbTitle) ([]*step, error) {
+	ids, err := i.ExternalIdRepository.FilmIds(ctx, constants.ExternalImdb, imdbKeys(titles, func(title *imdbTitle) string {
+		return title.tconst
+	})...)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]*step, 0, len(titles))
+	for _, title := range titles {
+		s := &step{result: appDto.ImportRowResult{Line: title.line}}
+		steps = append(steps, s)
+		release := time.Date(title.year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		existing, err := i.matchImdbFilm(ctx, ids[title.tconst], title.name, release)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil && ids[title.tconst] != "" {
+			// фильм в корзине, его не создаем заново
+			s.result.Id = ids[title.tconst]
+			s.skip()
+			continue
+		}
+
+		film := model.Film{Id: uuid.New().String(), Name: title.name, ReleaseDate: release}
+		if existing != nil {
+			film = existing.Film
+			film.Version, film.Name = 0, title.name
+			if film.ReleaseDate.Year() != title.year {
+				film.ReleaseDate = release
+			}
+		}
+		if title.rate != nil && i.honorManualRate {
+			film.Rate, film.ManualRate = *title.rate, i.manualRate(*title.rate)
+		}
+
+		filmAggregate, err := aggregate.NewFilmAggregate(film)
+		if err != nil {
+			s.fail(err)
+			continue
+		}
+		if line, ok := p.claim(s.result.Line, "film:"+film.Id); !ok {
+			s.fail(duplicateError(line))
+			continue
+		}
+
+		s.result.Id = film.Id
+		link := func(ctx context.Context) error {
+			return i.ExternalIdRepository.SetFilm(ctx, film.Id, map[string]string{constants.ExternalImdb: title.tconst})
+		}
+		switch {
+		case existing == nil:
+			s.plan(constants.ImportCreated, sequence(i.createFilm(filmAggregate), link))
+		case !sameFilm(existing.Film, film):
+			s.plan(constants.ImportUpdated, sequence(i.updateFilm(filmAggregate), link))
+		case ids[title.tconst] == "":
+			s.plan(constants.ImportSkipped, link)
+		default:
+			s.skip()
+		}
+	}
+	return steps, nil
+}
+
+// matchImdbFilm фильм по связи с tconst, иначе по названию и дате. Связанный фильм в корзине - nil
+func (i *importUseCase) matchImdbFilm(ctx context.Context, id string, name string, release time.Time) (*aggregate.FilmAggregate, error) {
+	if id == "" {
+		return i.matchFilm(ctx, "", name, &release)
+	}
+	film, err := i.FilmRepository.GetById(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return film, err
+}
+
+func (i *importUseCase) planImdbActors(ctx context.Context, p *plan, names []*imdbName) ([]*step, error) {
+	ids, err := i.ExternalIdRepository.ActorIds(ctx, constants.ExternalImdb, imdbKeys(names, func(name *imdbName) string {
+		return name.nconst
+	})...)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]*step, 0, len(names))
+	for _, name := range names {
+		s := &step{result: appDto.ImportRowResult{Line: name.line}}
+		steps = append(steps, s)
+		birthday := time.Date(name.year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		existing, err := i.matchImdbActor(ctx, ids[name.nconst], name.name, birthday)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil && ids[name.nconst] != "" {
+			s.result.Id = ids[name.nconst]
+			s.skip()
+			continue
+		}
+
+		actor := model.Actor{Id: uuid.New().String(), Name: name.name, Gender: name.gender, Birthday: birthday}
+		if existing != nil {
+			actor = existing.Actor
+			actor.Version, actor.Name = 0, name.name
+			if actor.Birthday.Year() != name.year {
+				actor.Birthday = birthday
+			}
+		}
+
+		actorAggregate, err := aggregate.NewActorAggregate(actor)
+		if err != nil {
+			s.fail(err)
+			continue
+		}
+		if line, ok := p.claim(s.result.Line, "actor:"+actor.Id); !ok {
+			s.fail(duplicateError(line))
+			continue
+		}
+
+		s.result.Id = actor.Id
+		link := func(ctx context.Context) error {
+			return i.ExternalIdRepository.SetActor(ctx, actor.Id, map[string]string{constants.ExternalImdb: name.nconst})
+		}
+		switch {
+		case existing == nil:
+			s.plan(constants.ImportCreated, sequence(i.createActor(actorAggregate), link))
+		case !sameActor(existing.Actor, actor):
+			s.plan(constants.ImportUpdated, sequence(i.updateActor(actorAggregate), link))
+		case ids[name.nconst] == "":
+			s.plan(constants.ImportSkipped, link)
+		default:
+			s.skip()
+		}
+	}
+	return steps, nil
+}
+
+// matchImdbActor как matchImdbFilm для актеров
+func (i *importUseCase) matchImdbActor(ctx context.Context, id string, name string, birthday time.Time) (*aggregate.ActorAggregate, error) {
+	if id == "" {
+		return i.matchActor(ctx, "", name, &birthday)
+	}
+	actor, err := i.ActorRepository.GetById(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return actor, err
+}
+
+// planImdbCredits роль actor с позицией в титрах из ordering. Титры, фильм или актер которых не загружен, пропускаются
+func (i *importUseCase) planImdbCredits(ctx context.Context, p *plan, credits []*imdbCredit, filmIds map[string]string, actorIds map[string]string) ([]*step, error) {
+	films := make(map[string]*aggregate.FilmAggregate, len(credits))
+	steps := make([]*step, 0, len(credits))
+	for _, item := range credits {
+		s := &step{result: appDto.ImportRowResult{Line: item.line, FilmId: filmIds[item.tconst], ActorId: actorIds[item.nconst]}}
+		steps = append(steps, s)
+		if s.result.FilmId == "" || s.result.ActorId == "" {
+			s.skip()
+			continue
+		}
+		film, ok := films[s.result.FilmId]
+		if !ok {
+			var err error
+			film, err = i.FilmRepository.GetById(ctx, s.result.FilmId)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			films[s.result.FilmId] = film
+		}
+		if film == nil {
+			s.skip()
+			continue
+		}
+
+		credit := &model.Credit{
+			ActorId:      s.result.ActorId,
+			FilmId:       s.result.FilmId,
+			Role:         constants.CreditActor,
+			Character:    item.character,
+			BillingOrder: item.ordering,
+		}
+		if err := aggregate.ValidateCredits([]*model.Credit{credit}); err != nil {
+			s.fail(err)
+			continue
+		}
+		if line, ok := p.claim(s.result.Line, "credit:"+credit.ActorId+"|"+credit.FilmId+"|"+credit.Role); !ok {
+			s.fail(duplicateError(line))
+			continue
+		}
+
+		current := slices.IndexFunc(film.Credits, func(c *model.Credit) bool {
+			return c.ActorId == credit.ActorId && c.Role == credit.Role
+		})
+		switch {
+		case current < 0:
+			s.plan(constants.ImportCreated, i.linkCredit(credit))
+		case equalPtr(film.Credits[current].Character, credit.Character) && film.Credits[current].BillingOrder == credit.BillingOrder:
+			s.skip()
+		default:
+			s.plan(constants.ImportUpdated, i.replaceCredit(credit))
+		}
+	}
+	return steps, nil
+}
+
+// imdbLookup id каталога по идентификаторам IMDb записей items, запросами по imdbLookupSize
+func imdbLookup[T any](ctx context.Context, lookup func(ctx context.Context, source string, keys ...string) (map[string]string, error), items []T, key func(item T) string) (map[string]string, error) {
+	result := make(map[string]string, len(items))
+	for start := 0; start < len(items); start += imdbLookupSize {
+		ids, err := lookup(ctx, constants.ExternalImdb, imdbKeys(items[start:min(start+imdbLookupSize, len(items))], key)...)
+		if err != nil {
+			return nil, err
+		}
+		for imdbId, id := range ids {
+			result[imdbId] = id
+		}
+	}
+	return result, nil
+}
+
+func imdbKeys[T any](items []T, key func(item T) string) []string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, key(item))
+	}
+	return keys
+}
+
+// countSteps добавляет итоги пачки в отчет этапа, в строки отчета попадают только ошибки
+func countSteps(report *appDto.ImportReport, steps []*step) {
+	for _, s := range steps {
+		switch s.result.Status {
+		case constants.ImportCreated:
+			report.Created++
+		case constants.ImportUpdated:
+			report.Updated++
+		case constants.ImportSkipped:
+			report.Skipped++
+		case constants.ImportFailed:
+			report.Failed++
+			report.Rows = append(report.Rows, s.result)
+		}
+	}
+}
+
+// sequence выполняет fns по порядку до первой ошибки
+func sequence(fns ...func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, fn := range fns {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func imdbError(action string, err error) error {
+	return appErrors.InternalServerError("", "target: ImportUseCase, method: ImportImdb ", action, " error: ", err.Error())
+}
@@ -0,0 +1,245 @@
+package importUseCase
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+)
+
+// файлы датасета IMDb
+const (
+	imdbTitleFile     = "title.basics.tsv.gz"
+	imdbRatingFile    = "title.ratings.tsv.gz"
+	imdbPrincipalFile = "title.principals.tsv.gz"
+	imdbNameFile      = "name.basics.tsv.gz"
+)
+
+// imdbNull пустое значение в TSV датасета
+const imdbNull = `\N`
+
+// imdbGenders пол актера по категории в title.principals, остальные категории не загружаются
+var imdbGenders = map[string]string{
+	"actor":   "male",
+	"actress": "female",
+}
+
+type (
+	imdbTitle struct {
+		line   int
+		tconst string
+		name   string
+		year   int
+		rate   *float32
+	}
+
+	imdbName struct {
+		line   int
+		nconst string
+		name   string
+		year   int
+		gender string
+	}
+
+	imdbCredit struct {
+		line      int
+		tconst    string
+		nconst    string
+		ordering  int
+		character *string
+	}
+)
+
+// eachTsv передает в fn строки gzip TSV файла name, fields - значения колонок columns в том же порядке, \N - пустая строка.
+// Кавычки в датасете не экранируются, поэтому encoding/csv не подходит. line - номер строки файла, первая строка - заголовок
+func eachTsv(ctx context.Context, dir fs.FS, name string, columns []string, fn func(line int, fields []string) error) error {
+	file, err := dir.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return fmt.Errorf("%s: empty file", name)
+	}
+	header := strings.Split(scanner.Text(), "\t")
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		indexes[i] = slices.Index(header, column)
+		if indexes[i] < 0 {
+			return fmt.Errorf("%s: missing column %s", name, column)
+		}
+	}
+
+	fields := make([]string, len(columns))
+	for line := 2; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		values := strings.Split(scanner.Text(), "\t")
+		if len(values) != len(header) {
+			return fmt.Errorf("%s line %d: expected %d fields, got %d", name, line, len(header), len(values))
+		}
+		for i, index := range indexes {
+			fields[i] = values[index]
+			if fields[i] == imdbNull {
+				fields[i] = ""
+			}
+		}
+		if err := fn(line, fields); err != nil {
+			return fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// imdbFingerprint параметры импорта и размер и время изменения файлов. Сохраненный прогресс продолжается,
+// только если отпечаток совпал, иначе порядок записей этапов мог измениться
+func imdbFingerprint(data appDto.ImdbImportUseCaseDto) (string, error) {
+	types := slices.Clone(data.TitleTypes)
+	slices.Sort(types)
+	parts := []string{"types=" + strings.Join(types, ","), "min-votes=" + strconv.Itoa(data.MinVotes)}
+	for _, name := range []string{imdbTitleFile, imdbRatingFile, imdbPrincipalFile, imdbNameFile} {
+		info, err := fs.Stat(data.Dir, name)
+		if err != nil {
+			if name == imdbRatingFile && data.MinVotes == 0 && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d@%s", name, info.Size(), info.ModTime().UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(parts, ";"), nil
+}
+
+// readImdbRatings оценки фильмов с не меньше чем MinVotes голосов. Без файла оценок - nil
+func readImdbRatings(ctx context.Context, data appDto.ImdbImportUseCaseDto) (map[string]float32, error) {
+	if _, err := fs.Stat(data.Dir, imdbRatingFile); errors.Is(err, fs.ErrNotExist) && data.MinVotes == 0 {
+		return nil, nil
+	}
+	ratings := make(map[string]float32, 1024)
+	err := eachTsv(ctx, data.Dir, imdbRatingFile, []string{"tconst", "averageRating", "numVotes"}, func(line int, fields []string) error {
+		votes, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return fmt.Errorf("invalid numVotes %q", fields[2])
+		}
+		if votes < data.MinVotes {
+			return nil
+		}
+		rate, err := strconv.ParseFloat(fields[1], 32)
+		if err != nil {
+			return fmt.Errorf("invalid averageRating %q", fields[1])
+		}
+		ratings[fields[0]] = float32(rate)
+		return nil
+	})
+	return ratings, err
+}
+
+// readImdbTitles записи нужных типов с годом выхода, при MinVotes - только с оценкой. Порядок как в файле
+func readImdbTitles(ctx context.Context, data appDto.ImdbImportUseCaseDto, ratings map[string]float32, report *appDto.ImportReport) ([]*imdbTitle, error) {
+	titles := make([]*imdbTitle, 0, 1024)
+	err := eachTsv(ctx, data.Dir, imdbTitleFile, []string{"tconst", "titleType", "primaryTitle", "startYear"}, func(line int, fields []string) error {
+		if !slices.Contains(data.TitleTypes, fields[1]) {
+			return nil
+		}
+		rate, rated := ratings[fields[0]]
+		if data.MinVotes > 0 && !rated {
+			return nil
+		}
+		year, err := strconv.Atoi(fields[3])
+		if err != nil {
+			// без года выхода фильм не создать, такие записи только учитываются в отчете
+			report.Skipped++
+			return nil
+		}
+		title := &imdbTitle{line: line, tconst: fields[0], name: fields[2], year: year}
+		if rated {
+			title.rate = &rate
+		}
+		titles = append(titles, title)
+		return nil
+	})
+	return titles, err
+}
+
+// readImdbCredits актеры и актрисы загружаемых записей. Второе значение - пол каждого нужного nconst
+func readImdbCredits(ctx context.Context, data appDto.ImdbImportUseCaseDto, titles []*imdbTitle) ([]*imdbCredit, map[string]string, error) {
+	selected := make(map[string]bool, len(titles))
+	for _, title := range titles {
+		selected[title.tconst] = true
+	}
+	credits := make([]*imdbCredit, 0, len(titles)*4)
+	genders := make(map[string]string, len(titles)*4)
+	err := eachTsv(ctx, data.Dir, imdbPrincipalFile, []string{"tconst", "ordering", "nconst", "category", "characters"}, func(line int, fields []string) error {
+		gender, ok := imdbGenders[fields[3]]
+		if !ok || !selected[fields[0]] {
+			return nil
+		}
+		ordering, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return fmt.Errorf("invalid ordering %q", fields[1])
+		}
+		credits = append(credits, &imdbCredit{line: line, tconst: fields[0], nconst: fields[2], ordering: ordering, character: imdbCharacter(fields[4])})
+		if _, ok := genders[fields[2]]; !ok {
+			genders[fields[2]] = gender
+		}
+		return nil
+	})
+	return credits, genders, err
+}
+
+// readImdbNames люди из genders с годом рождения в порядке файла
+func readImdbNames(ctx context.Context, data appDto.ImdbImportUseCaseDto, genders map[string]string, report *appDto.ImportReport) ([]*imdbName, error) {
+	names := make([]*imdbName, 0, len(genders))
+	err := eachTsv(ctx, data.Dir, imdbNameFile, []string{"nconst", "primaryName", "birthYear"}, func(line int, fields []string) error {
+		gender, ok := genders[fields[0]]
+		if !ok {
+			return nil
+		}
+		year, err := strconv.Atoi(fields[2])
+		if err != nil {
+			// дата рождения у актера обязательна
+			report.Skipped++
+			return nil
+		}
+		names = append(names, &imdbName{line: line, nconst: fields[0], name: fields[1], year: year, gender: gender})
+		return nil
+	})
+	return names, err
+}
+
+// imdbCharacter первый персонаж из JSON массива characters
+func imdbCharacter(value string) *string {
+	var characters []string
+	if value == "" || json.Unmarshal([]byte(value), &characters) != nil || len(characters) == 0 || characters[0] == "" {
+		return nil
+	}
+	return &characters[0]
+}
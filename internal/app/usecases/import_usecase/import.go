@@ -0,0 +1,224 @@
+package importUseCase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+)
+
+// DefaultBatchSize сколько строк сохраняется одной транзакцией, если размер пачки не задан
+const DefaultBatchSize = 100
+
+type (
+	// ImportUseCase массовая загрузка каталога. Сначала все строки проверяются валидаторами агрегатов и сопоставляются
+	// с каталогом, затем без DryRun сохраняются пачками. Ошибка строки не прерывает импорт, а попадает в отчет
+	ImportUseCase interface {
+		Import(ctx context.Context, data appDto.ImportUseCaseDto) (*appDto.ImportReport, error)
+		// ImportImdb загрузка фильмов, актеров и титров из датасета IMDb со связями tconst и nconst.
+		// Прерванный импорт с теми же параметрами и файлами продолжается с последней сохраненной пачки
+		ImportImdb(ctx context.Context, data appDto.ImdbImportUseCaseDto) (*appDto.ImdbImportReport, error)
+	}
+
+	importUseCase struct {
+		repository.FilmRepository
+		repository.ActorRepository
+		repository.ImdbRepository
+		repository.ExternalIdRepository
+		TxManager       repository.TxManager
+		AuditService    auditService.Service
+		RevisionService revisionService.Service
+		honorManualRate bool
+	}
+
+	// step итог проверки строки, apply сохраняет ее внутри транзакции пачки
+	step struct {
+		result appDto.ImportRowResult
+		apply  func(ctx context.Context) error
+	}
+
+	// plan состояние проверки файла: строки, уже занявшие запись, и следующие позиции в титрах фильмов
+	plan struct {
+		seen    map[string]int
+		billing map[string]int
+	}
+)
+
+func (i *importUseCase) Import(ctx context.Context, data appDto.ImportUseCaseDto) (*appDto.ImportReport, error) {
+	columns, ok := constants.CatalogColumns[data.Kind]
+	if !ok {
+		return nil, appErrors.BadRequest("", "target: ImportUseCase, method: Import ", "unknown kind: ", data.Kind)
+	}
+	if data.Kind != constants.ImportCredits {
+		columns = append(slices.Clone(columns), constants.ExternalSources...)
+	}
+	if data.BatchSize < 0 {
+		return nil, appErrors.BadRequest("", "target: ImportUseCase, method: Import ", "negative batch size")
+	}
+	if data.BatchSize == 0 {
+		data.BatchSize = DefaultBatchSize
+	}
+	records, err := readRecords(data.Format, data.Data, columns)
+	if err != nil {
+		return nil, appErrors.BadRequest("", "target: ImportUseCase, method: Import ", "read error: ", err.Error())
+	}
+
+	p := &plan{seen: make(map[string]int), billing: make(map[string]int)}
+	steps := make([]*step, 0, len(records))
+	for _, rec := range records {
+		s := &step{result: appDto.ImportRowResult{Line: rec.line}}
+		steps = append(steps, s)
+		if rec.err != nil {
+			s.fail(rec.err)
+			continue
+		}
+
+		switch data.Kind {
+		case constants.ImportFilms:
+			err = i.planFilm(ctx, p, rec.fields, s)
+		case constants.ImportActors:
+			err = i.planActor(ctx, p, rec.fields, s)
+		case constants.ImportCredits:
+			err = i.planCredit(ctx, p, rec.fields, s)
+		}
+		if err != nil {
+			return nil, appErrors.InternalServerError("", "target: ImportUseCase, method: Import ", "plan error: ", err.Error())
+		}
+	}
+
+	if !data.DryRun {
+		i.commit(ctx, steps, data.BatchSize)
+	}
+	return report(data, steps), nil
+}
+
+func (i *importUseCase) commit(ctx context.Context, steps []*step, batchSize int) {
+	pending := make([]*step, 0, len(steps))
+	for _, s := range steps {
+		if s.apply != nil {
+			pending = append(pending, s)
+		}
+	}
+	for start := 0; start < len(pending); start += batchSize {
+		i.commitBatch(ctx, slices.Clone(pending[start:min(start+batchSize, len(pending))]))
+	}
+}
+
+// commitBatch сохраняет пачку одной транзакцией. Строка с ошибкой откатывает пачку,
+// после чего остальные строки сохраняются заново уже без нее
+func (i *importUseCase) commitBatch(ctx context.Context, batch []*step) {
+	for len(batch) > 0 {
+		var failed *step
+		err := i.TxManager.Do(ctx, func(ctx context.Context) error {
+			for _, s := range batch {
+				if err := s.apply(ctx); err != nil {
+					failed = s
+					return err
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			return
+		}
+		if failed == nil {
+			for _, s := range batch {
+				s.fail(err)
+			}
+			return
+		}
+		failed.fail(err)
+		batch = slices.DeleteFunc(batch, func(s *step) bool {
+			return s == failed
+		})
+	}
+}
+
+func (s *step) fail(err error) {
+	if s.result.Status == constants.ImportCreated {
+		s.result.Id = ""
+	}
+	s.result.Status, s.apply = constants.ImportFailed, nil
+	s.result.Reason = err.Error()
+	if err == sql.ErrNoRows {
+		s.result.Reason = "record not found"
+	}
+}
+
+func (s *step) skip() {
+	s.result.Status = constants.ImportSkipped
+}
+
+func (s *step) plan(status string, apply func(ctx context.Context) error) {
+	s.result.Status, s.apply = status, apply
+}
+
+// claim закрепляет записи с ключами keys за строкой line. Если запись уже занята другой строкой - номер той строки
+func (p *plan) claim(line int, keys ...string) (int, bool) {
+	for _, key := range keys {
+		if claimed, ok := p.seen[key]; ok {
+			return claimed, false
+		}
+	}
+	for _, key := range keys {
+		p.seen[key] = line
+	}
+	return 0, true
+}
+
+func duplicateError(line int) error {
+	return fmt.Errorf("duplicate of line %d", line)
+}
+
+func report(data appDto.ImportUseCaseDto, steps []*step) *appDto.ImportReport {
+	result := &appDto.ImportReport{Kind: data.Kind, DryRun: data.DryRun, Rows: make([]appDto.ImportRowResult, 0, len(steps))}
+	for _, s := range steps {
+		switch s.result.Status {
+		case constants.ImportCreated:
+			result.Created++
+		case constants.ImportUpdated:
+			result.Updated++
+		case constants.ImportSkipped:
+			result.Skipped++
+		case constants.ImportFailed:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, s.result)
+	}
+	return result
+}
+
+// manualRate как у FilmUseCase: оценка из файла сохраняется только если это разрешено конфигом
+func (i *importUseCase) manualRate(rate float32) *float32 {
+	if !i.honorManualRate {
+		return nil
+	}
+	return &rate
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func New(filmRepository repository.FilmRepository, actorRepository repository.ActorRepository, imdbRepository repository.ImdbRepository, externalIdRepository repository.ExternalIdRepository, txManager repository.TxManager, auditService auditService.Service, revisionService revisionService.Service, honorManualRate bool) ImportUseCase {
+	return &importUseCase{
+		FilmRepository:       filmRepository,
+		ActorRepository:      actorRepository,
+		ImdbRepository:       imdbRepository,
+		ExternalIdRepository: externalIdRepository,
+		TxManager:            txManager,
+		AuditService:         auditService,
+		RevisionService:      revisionService,
+		honorManualRate:      honorManualRate,
+	}
+}
@@ -0,0 +1,152 @@
+package import_usecase_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	importUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/import_usecase"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipFile(t *testing.T, content string) *fstest.MapFile {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &fstest.MapFile{Data: buf.Bytes()}
+}
+
+func imdbDataset(t *testing.T) fstest.MapFS {
+	return fstest.MapFS{
+		"title.basics.tsv.gz": gzipFile(t, "tconst\ttitleType\tprimaryTitle\toriginalTitle\tisAdult\tstartYear\tendYear\truntimeMinutes\tgenres\n"+
+			"tt0000001\tmovie\tThe \"Shawshank\" Redemption\tSame\t0\t1994\t\\N\t142\tDrama\n"+
+			"tt0000002\ttvSeries\tSeries\tSeries\t0\t2001\t2003\t\\N\tDrama\n"+
+			"tt0000003\tmovie\tUnpopular\tUnpopular\t0\t2010\t\\N\t90\tDrama\n"+
+			"tt0000004\tmovie\tNo year\tNo year\t0\t\\N\t\\N\t\\N\t\\N\n"+
+			"tt0000005\tmovie\tSecond movie\tSecond movie\t0\t2005\t\\N\t100\tComedy\n"),
+		"title.ratings.tsv.gz": gzipFile(t, "tconst\taverageRating\tnumVotes\n"+
+			"tt0000001\t9.3\t2000\n"+
+			"tt0000002\t8.0\t5000\n"+
+			"tt0000003\t5.1\t10\n"+
+			"tt0000005\t6.5\t1500\n"),
+		"title.principals.tsv.gz": gzipFile(t, "tconst\tordering\tnconst\tcategory\tjob\tcharacters\n"+
+			"tt0000001\t1\tnm0000001\tactor\t\\N\t[\"Andy Dufresne\"]\n"+
+			"tt0000001\t2\tnm0000002\tactress\t\\N\t\\N\n"+
+			"tt0000001\t3\tnm0000003\tdirector\t\\N\t\\N\n"+
+			"tt0000003\t1\tnm0000004\tactor\t\\N\t[\"Nobody\"]\n"+
+			"tt0000005\t1\tnm0000001\tactor\t\\N\t[\"Lead\"]\n"+
+			"tt0000005\t2\tnm0000005\tactor\t\\N\t\\N\n"),
+		"name.basics.tsv.gz": gzipFile(t, "nconst\tprimaryName\tbirthYear\tdeathYear\tprimaryProfession\tknownForTitles\n"+
+			"nm0000001\tTim Robbins\t1958\t\\N\tactor\ttt0000001\n"+
+			"nm0000002\tSome Actress\t1970\t\\N\tactress\ttt0000001\n"+
+			"nm0000003\tFrank Darabont\t1959\t\\N\tdirector\ttt0000001\n"+
+			"nm0000004\tNobody\t1980\t\\N\tactor\ttt0000003\n"+
+			"nm0000005\tNo Birth\t\\N\t\\N\tactor\ttt0000005\n"),
+	}
+}
+
+func TestImportImdb(t *testing.T) {
+	db := inMemDb.New()
+	db.CleanUp()
+	defer db.CleanUp()
+	filmRepo, actorRepo, txManager := mockRepository.NewFilmRepository(), mockRepository.NewActorRepository(), mockRepository.NewTxManager()
+	audit := auditService.New(mockRepository.NewAuditRepository(), txManager)
+	revision := revisionService.New(mockRepository.NewRevisionRepository(), filmRepo, actorRepo)
+	useCase := importUseCase.New(filmRepo, actorRepo, mockRepository.NewImdbRepository(), mockRepository.NewExternalIdRepository(), txManager, audit, revision, true)
+	dataset := imdbDataset(t)
+
+	t.Run("Should require ratings for min votes", func(t *testing.T) {
+		withoutRatings := fstest.MapFS{}
+		for name, file := range dataset {
+			if name != "title.ratings.tsv.gz" {
+				withoutRatings[name] = file
+			}
+		}
+		_, err := useCase.ImportImdb(context.Background(), appDto.ImdbImportUseCaseDto{Dir: withoutRatings, MinVotes: 100})
+		var appErr *appErrors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.Code)
+		assert.Equal(t, 0, len(db.Film))
+	})
+
+	t.Run("Should resume interrupted import", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		data := appDto.ImdbImportUseCaseDto{Dir: dataset, MinVotes: 1000, BatchSize: 1, Progress: func(stage string, done int, total int) {
+			cancel()
+		}}
+		_, err := useCase.ImportImdb(ctx, data)
+		assert.NotNil(t, err)
+		assert.Equal(t, 1, len(db.Film))
+		assert.Equal(t, "The \"Shawshank\" Redemption", db.Film[0].Name)
+		assert.Equal(t, 1, len(db.ImdbProgress))
+
+		data.Progress = nil
+		report, err := useCase.ImportImdb(context.Background(), data)
+		assert.Nil(t, err)
+		assert.Equal(t, "films", report.Resumed.Stage)
+		assert.Equal(t, 1, report.Resumed.Done)
+		assert.Equal(t, 1, report.Films.Created)
+		// первый фильм сохранен до прерывания
+		assert.Equal(t, 1, report.Films.Skipped)
+		assert.Equal(t, 2, report.Actors.Created)
+		assert.Equal(t, 1, report.Actors.Skipped)
+		assert.Equal(t, 3, report.Credits.Created)
+		assert.Equal(t, 1, report.Credits.Skipped)
+		assert.Equal(t, 0, len(db.ImdbProgress))
+
+		assert.Equal(t, 2, len(db.Film))
+		assert.Equal(t, 2, len(db.Actor))
+		assert.Equal(t, 3, len(db.ActorFilm))
+		assert.Equal(t, 2, len(db.FilmExternalId))
+		assert.Equal(t, 2, len(db.ActorExternalId))
+		for _, actor := range db.Actor {
+			if actor.Name == "Some Actress" {
+				assert.Equal(t, "female", actor.Gender)
+				assert.Equal(t, 1970, actor.Birthday.Year())
+			}
+		}
+		for _, film := range db.Film {
+			if film.Name == "Second movie" {
+				assert.Equal(t, float32(6.5), film.Rate)
+				assert.Equal(t, 2005, film.ReleaseDate.Year())
+			}
+		}
+		for _, link := range db.ActorFilm {
+			if link.BillingOrder == 1 && link.Character != nil {
+				assert.Contains(t, []string{"Andy Dufresne", "Lead"}, *link.Character)
+			}
+		}
+	})
+
+	t.Run("Should match previous import by imdb ids", func(t *testing.T) {
+		films := len(db.Film)
+		report, err := useCase.ImportImdb(context.Background(), appDto.ImdbImportUseCaseDto{Dir: dataset, MinVotes: 1000})
+		assert.Nil(t, err)
+		assert.Nil(t, report.Resumed)
+		assert.Equal(t, 0, report.Films.Created+report.Films.Updated+report.Films.Failed)
+		assert.Equal(t, 0, report.Actors.Created+report.Actors.Updated+report.Actors.Failed)
+		assert.Equal(t, 0, report.Credits.Created+report.Credits.Updated+report.Credits.Failed)
+		assert.Equal(t, films, len(db.Film))
+
+		report, err = useCase.ImportImdb(context.Background(), appDto.ImdbImportUseCaseDto{Dir: dataset, TitleTypes: []string{"movie", "tvSeries"}})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Films.Created)
+		assert.Equal(t, 1, report.Actors.Created)
+		assert.Equal(t, 4, len(db.Film))
+		assert.Equal(t, 4, len(db.FilmExternalId))
+	})
+}
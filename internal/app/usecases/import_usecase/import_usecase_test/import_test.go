@@ -0,0 +1,180 @@
+package import_usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	importUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/import_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func statuses(report *appDto.ImportReport) []string {
+	result := make([]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		result = append(result, row.Status)
+	}
+	return result
+}
+
+func TestImportUseCase(t *testing.T) {
+	db := inMemDb.New()
+	db.CleanUp()
+	filmRepo, actorRepo, txManager := mockRepository.NewFilmRepository(), mockRepository.NewActorRepository(), mockRepository.NewTxManager()
+	audit := auditService.New(mockRepository.NewAuditRepository(), txManager)
+	revision := revisionService.New(mockRepository.NewRevisionRepository(), filmRepo, actorRepo)
+	useCase := importUseCase.New(filmRepo, actorRepo, mockRepository.NewImdbRepository(), mockRepository.NewExternalIdRepository(), txManager, audit, revision, true)
+
+	films := "name,description,release,rate\n" +
+		"Import film,First,2001-02-03,7.5\n" +
+		",No name,2001-02-03,\n" +
+		"Bad date,,03.02.2001,\n" +
+		"IMPORT FILM,Again,2001-02-03,\n"
+
+	t.Run("Should validate without saving in dry run", func(t *testing.T) {
+		report, err := useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportCsv, Data: strings.NewReader(films), DryRun: true,
+		})
+		assert.Nil(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []string{constants.ImportCreated, constants.ImportFailed, constants.ImportFailed, constants.ImportFailed}, statuses(report))
+		assert.Equal(t, 2, report.Rows[0].Line)
+		assert.Contains(t, report.Rows[2].Reason, "release")
+		assert.Equal(t, "duplicate of line 2", report.Rows[3].Reason)
+		assert.Equal(t, 0, len(db.Film))
+		assert.Equal(t, 0, len(db.Audit))
+	})
+
+	t.Run("Should create, skip and update films", func(t *testing.T) {
+		report, err := useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportCsv, Data: strings.NewReader(films),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 3, report.Failed)
+		assert.Equal(t, 1, len(db.Film))
+		assert.Equal(t, report.Rows[0].Id, db.Film[0].Id)
+		assert.Equal(t, float32(7.5), db.Film[0].Rate)
+		assert.Equal(t, 1, len(db.Audit))
+		assert.Equal(t, 1, len(db.Revision))
+
+		report, err = useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportNdjson,
+			Data: strings.NewReader(`{"name": "Import film", "release": "2001-02-03", "rate": 7.5}` + "\n\n" +
+				`{"id": "` + db.Film[0].Id + `", "description": "Second"}` + "\n"),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportSkipped, constants.ImportFailed}, statuses(report))
+		assert.Equal(t, "duplicate of line 1", report.Rows[1].Reason)
+		assert.Equal(t, 3, report.Rows[1].Line)
+
+		report, err = useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportNdjson,
+			Data: strings.NewReader(`{"id": "` + db.Film[0].Id + `", "description": "Second"}`),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportUpdated}, statuses(report))
+		assert.Equal(t, "Second", *db.Film[0].Description)
+
+		report, err = useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportCsv, Data: strings.NewReader("id,name\nnot-uuid,Bad id\n"),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportFailed}, statuses(report))
+		assert.Contains(t, report.Rows[0].Reason, "invalid id")
+		assert.Equal(t, "Import film", db.Film[0].Name)
+		assert.Equal(t, 2, len(db.Audit))
+	})
+
+	t.Run("Should import actors and credits by name and date", func(t *testing.T) {
+		report, err := useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportActors, Format: constants.ImportNdjson,
+			Data: strings.NewReader(`{"name": "Import actor", "gender": "female", "birthday": "1980-01-01"}` + "\n" +
+				`{"name": "Future actor", "gender": "male", "birthday": "` + time.Now().AddDate(1, 0, 0).Format(time.DateOnly) + `"}` + "\n" +
+				`{"name": "Wrong", "gender": true}`),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportCreated, constants.ImportFailed, constants.ImportFailed}, statuses(report))
+		assert.Equal(t, 1, len(db.Actor))
+
+		credits := "filmName,filmRelease,actorName,actorBirthday,role,character\n" +
+			"Import film,2001-02-03,Import actor,1980-01-01,,Hero\n" +
+			"Import film,2001-02-03,Import actor,1980-01-01,director,\n" +
+			"Unknown film,2001-02-03,Import actor,1980-01-01,,\n"
+		report, err = useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportCredits, Format: constants.ImportCsv, Data: strings.NewReader(credits), BatchSize: 1,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportCreated, constants.ImportCreated, constants.ImportFailed}, statuses(report))
+		assert.Equal(t, "film not found", report.Rows[2].Reason)
+		film, err := filmRepo.GetById(context.Background(), db.Film[0].Id)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(film.Credits))
+		assert.Equal(t, 1, film.Credits[1].BillingOrder)
+
+		report, err = useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportCredits, Format: constants.ImportCsv,
+			Data: strings.NewReader("film,actorName,actorBirthday,character\n" + film.Film.Id + ",Import actor,1980-01-01,Villain\n"),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportUpdated}, statuses(report))
+		actor, err := actorRepo.GetById(context.Background(), db.Actor[0].Id)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(actor.Credits))
+		assert.Equal(t, "Villain", *actor.Credits[0].Character)
+	})
+
+	t.Run("Should match and link films by external ids", func(t *testing.T) {
+		report, err := useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportCsv,
+			Data: strings.NewReader("imdb,tmdb,name,release\n" +
+				"tt0000010,,Import film,2001-02-03\n" +
+				",500,External film,2010-05-05\n" +
+				"bad,,Other film,2010-01-01\n"),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportUpdated, constants.ImportCreated, constants.ImportFailed}, statuses(report))
+		assert.Equal(t, db.Film[0].Id, report.Rows[0].Id)
+		assert.Contains(t, report.Rows[2].Reason, "imdb")
+		assert.Equal(t, 2, len(db.FilmExternalId))
+
+		report, err = useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportNdjson,
+			Data: strings.NewReader(`{"imdb": "tt0000010", "name": "Import film renamed"}`),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportUpdated}, statuses(report))
+		assert.Equal(t, "Import film renamed", db.Film[0].Name)
+
+		report, err = useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportNdjson,
+			Data: strings.NewReader(`{"id": "` + db.Film[0].Id + `", "tmdb": 500}`),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{constants.ImportFailed}, statuses(report))
+		assert.Contains(t, report.Rows[0].Reason, "external id conflict")
+		assert.Equal(t, 2, len(db.FilmExternalId))
+	})
+
+	t.Run("Should reject unknown columns", func(t *testing.T) {
+		_, err := useCase.Import(context.Background(), appDto.ImportUseCaseDto{
+			Kind: constants.ImportFilms, Format: constants.ImportCsv, Data: strings.NewReader("name,year\nFilm,2001\n"),
+		})
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusBadRequest, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package importUseCase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/google/uuid"
+)
+
+// maxNdjsonLine самая длинная строка NDJSON файла
+const maxNdjsonLine = 1 << 20
+
+// record строка файла: непустые значения по именам колонок, err - строку не удалось разобрать
+type record struct {
+	line   int
+	fields map[string]string
+	err    error
+}
+
+// readRecords разбирает файл целиком. Ошибка отдельной строки попадает в ее record,
+// ошибка заголовка или чтения файла возвращается вторым значением
+func readRecords(format string, data io.Reader, columns []string) ([]record, error) {
+	switch format {
+	case constants.ImportCsv:
+		return readCsv(data, columns)
+	case constants.ImportNdjson:
+		return readNdjson(data, columns)
+	}
+	return nil, fmt.Errorf("unknown format %s", format)
+}
+
+func readCsv(data io.Reader, columns []string) ([]record, error) {
+	reader := csv.NewReader(data)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty file, header expected")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if !slices.Contains(columns, column) {
+			return nil, fmt.Errorf("unknown column %q, available: %s", column, strings.Join(columns, ", "))
+		}
+		header[i] = column
+	}
+
+	var records []record
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, err
+		}
+		if err != nil {
+			records = append(records, record{line: line, err: fmt.Errorf("expected %d columns, got %d", len(header), len(values))})
+			continue
+		}
+
+		fields := make(map[string]string, len(header))
+		for i, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				fields[header[i]] = value
+			}
+		}
+		records = append(records, record{line: line, fields: fields})
+	}
+}
+
+func readNdjson(data io.Reader, columns []string) ([]record, error) {
+	scanner := bufio.NewScanner(data)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNdjsonLine)
+
+	var records []record
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		fields, err := ndjsonFields(text, columns)
+		records = append(records, record{line: line, fields: fields, err: err})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ndjsonFields объект строки как значения колонок, числа сохраняются в исходной записи
+func ndjsonFields(text []byte, columns []string) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(text))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("invalid json: %s", err.Error())
+	}
+
+	fields := make(map[string]string, len(object))
+	for key, value := range object {
+		if !slices.Contains(columns, key) {
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+		switch value := value.(type) {
+		case nil:
+		case string:
+			if value = strings.TrimSpace(value); value != "" {
+				fields[key] = value
+			}
+		case json.Number:
+			fields[key] = value.String()
+		default:
+			return nil, fmt.Errorf("field %q must be a string or a number", key)
+		}
+	}
+	return fields, nil
+}
+
+// parseDate дата как 2006-01-02 или RFC3339
+func parseDate(fields map[string]string, key string) (*time.Time, error) {
+	value, ok := fields[key]
+	if !ok {
+		return nil, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: invalid date %q, expected YYYY-MM-DD", key, value)
+}
+
+// parseId id записи каталога. Не uuid проверяется здесь, иначе postgres вернет ошибку запроса вместо sql.ErrNoRows
+func parseId(fields map[string]string, key string) (string, error) {
+	value := fields[key]
+	if value == "" {
+		return "", nil
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		return "", fmt.Errorf("%s: invalid id %q", key, value)
+	}
+	return value, nil
+}
+
+func parseFloat(fields map[string]string, key string) (*float32, error) {
+	value, ok := fields[key]
+	if !ok {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid number %q", key, value)
+	}
+	result := float32(parsed)
+	return &result, nil
+}
+
+func parseInt(fields map[string]string, key string) (*int, error) {
+	value, ok := fields[key]
+	if !ok {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid integer %q", key, value)
+	}
+	return &parsed, nil
+}
+
+func optionalString(fields map[string]string, key string) *string {
+	value, ok := fields[key]
+	if !ok {
+		return nil
+	}
+	return &value
+}
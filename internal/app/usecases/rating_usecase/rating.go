@@ -0,0 +1,119 @@
+package ratingUseCase
+
+import (
+	"context"
+	"database/sql"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	RatingUseCase interface {
+		Rate(ctx context.Context, userId string, data appDto.RateFilmUseCaseDto) (*appDto.RatingUseCaseResult, error)
+		Change(ctx context.Context, userId string, data appDto.RateFilmUseCaseDto) (*appDto.RatingUseCaseResult, error)
+		Withdraw(ctx context.Context, userId string, filmId string) (*appDto.RatingUseCaseResult, error)
+		Get(ctx context.Context, userId string, filmId string) (*model.Rating, error)
+	}
+
+	ratingUseCase struct {
+		repository.RatingRepository
+	}
+)
+
+func (r *ratingUseCase) Rate(ctx context.Context, userId string, data appDto.RateFilmUseCaseDto) (*appDto.RatingUseCaseResult, error) {
+	rating := &model.Rating{UserId: userId, FilmId: data.FilmId, Score: data.Score}
+	if err := appValidator.New().Struct(rating); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: RatingUseCase, method: Rate ", "error: ", err.Error())
+	}
+	if !isFilmId(data.FilmId) {
+		return nil, appErrors.NotFound("")
+	}
+
+	film, err := r.RatingRepository.Create(ctx, rating)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err == repository.ErrRatingExist {
+		return nil, appErrors.Conflict(constants.RatingExist)
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: RatingUseCase, method: Rate ", "repository create error: ", err.Error())
+	}
+
+	return newResult(rating, film), nil
+}
+
+func (r *ratingUseCase) Change(ctx context.Context, userId string, data appDto.RateFilmUseCaseDto) (*appDto.RatingUseCaseResult, error) {
+	rating := &model.Rating{UserId: userId, FilmId: data.FilmId, Score: data.Score}
+	if err := appValidator.New().Struct(rating); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: RatingUseCase, method: Change ", "error: ", err.Error())
+	}
+	if !isFilmId(data.FilmId) {
+		return nil, appErrors.NotFound("")
+	}
+
+	film, err := r.RatingRepository.Update(ctx, rating)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: RatingUseCase, method: Change ", "repository update error: ", err.Error())
+	}
+
+	return newResult(rating, film), nil
+}
+
+func (r *ratingUseCase) Withdraw(ctx context.Context, userId string, filmId string) (*appDto.RatingUseCaseResult, error) {
+	if !isFilmId(filmId) {
+		return nil, appErrors.NotFound("")
+	}
+	film, err := r.RatingRepository.Delete(ctx, userId, filmId)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: RatingUseCase, method: Withdraw ", "repository delete error: ", err.Error())
+	}
+
+	return newResult(nil, film), nil
+}
+
+func (r *ratingUseCase) Get(ctx context.Context, userId string, filmId string) (*model.Rating, error) {
+	if !isFilmId(filmId) {
+		return nil, appErrors.NotFound("")
+	}
+	rating, err := r.RatingRepository.Get(ctx, userId, filmId)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: RatingUseCase, method: Get ", "repository get error: ", err.Error())
+	}
+	return rating, nil
+}
+
+// isFilmId фильм с id не uuid не может существовать, а postgres на такой id отвечает ошибкой запроса
+func isFilmId(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+func newResult(rating *model.Rating, film *model.Film) *appDto.RatingUseCaseResult {
+	return &appDto.RatingUseCaseResult{
+		Rating:    rating,
+		FilmId:    film.Id,
+		Rate:      film.Rate,
+		VoteCount: film.VoteCount,
+	}
+}
+
+func New(ratingRepository repository.RatingRepository) RatingUseCase {
+	return &ratingUseCase{
+		RatingRepository: ratingRepository,
+	}
+}
@@ -0,0 +1,108 @@
+package rating_usecase_test
+
+import (
+	"context"
+	"errors"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	filmUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/film_usecase"
+	ratingUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/rating_usecase"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func assertAppErrorCode(t *testing.T, err error, code int) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		assert.Equal(t, code, appErr.Code)
+	} else {
+		t.Fatal("incorrect error type")
+	}
+}
+
+func TestRatingUseCase(t *testing.T) {
+	useCase := ratingUseCase.New(mockRepository.NewRatingRepository())
+	films := filmUseCase.New(mockRepository.NewFilmRepository(), mockRepository.NewActorRepository(), mockRepository.NewExternalIdRepository(), auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()), revisionService.New(mockRepository.NewRevisionRepository(), mockRepository.NewFilmRepository(), mockRepository.NewActorRepository()), false)
+
+	// без политики honor_manual_rate ручная оценка при создании не сохраняется
+	manualRate := float32(10)
+	film, err := films.Create(context.Background(), appDto.CreateFilmUseCaseDto{Name: "Titanic", ReleaseDate: time.Now().AddDate(-13, 0, 0), ManualRate: &manualRate})
+	assert.Nil(t, err)
+	assert.Nil(t, film.Film.ManualRate)
+	assert.Equal(t, float32(0), film.Film.Rate)
+	filmId := film.Film.Id
+
+	t.Run("Should rate film", func(t *testing.T) {
+		result, err := useCase.Rate(context.Background(), "user1", appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 7})
+		assert.Nil(t, err)
+		assert.Equal(t, float32(7), result.Rate)
+		assert.Equal(t, 1, result.VoteCount)
+		assert.Equal(t, 7, result.Rating.Score)
+
+		result, err = useCase.Rate(context.Background(), "user2", appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, float32(8.5), result.Rate)
+		assert.Equal(t, 2, result.VoteCount)
+
+		aggr, err := films.GetById(context.Background(), filmId)
+		assert.Nil(t, err)
+		assert.Equal(t, film.Film.Version, aggr.Film.Version)
+	})
+
+	t.Run("Should rate errors", func(t *testing.T) {
+		_, err := useCase.Rate(context.Background(), "user1", appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 3})
+		assertAppErrorCode(t, err, http.StatusConflict)
+		_, err = useCase.Rate(context.Background(), "user3", appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 11})
+		assertAppErrorCode(t, err, http.StatusUnprocessableEntity)
+		_, err = useCase.Rate(context.Background(), "user3", appDto.RateFilmUseCaseDto{FilmId: uuid.New().String(), Score: 5})
+		assertAppErrorCode(t, err, http.StatusNotFound)
+		_, err = useCase.Rate(context.Background(), "user3", appDto.RateFilmUseCaseDto{FilmId: "not-uuid", Score: 5})
+		assertAppErrorCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("Should change rating", func(t *testing.T) {
+		result, err := useCase.Change(context.Background(), "user1", appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 4})
+		assert.Nil(t, err)
+		assert.Equal(t, float32(7), result.Rate)
+		rating, err := useCase.Get(context.Background(), "user1", filmId)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, rating.Score)
+
+		_, err = useCase.Change(context.Background(), "user3", appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 4})
+		assertAppErrorCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("Should ignore admin rate on update", func(t *testing.T) {
+		aggr, err := films.GetById(context.Background(), filmId)
+		assert.Nil(t, err)
+		manualRate := float32(1)
+		aggr.Film.ManualRate = &manualRate
+		updated, err := films.Update(context.Background(), aggr, true)
+		assert.Nil(t, err)
+		assert.Equal(t, float32(7), updated.Film.Rate)
+		assert.Equal(t, 2, updated.Film.VoteCount)
+	})
+
+	t.Run("Should withdraw rating", func(t *testing.T) {
+		result, err := useCase.Withdraw(context.Background(), "user1", filmId)
+		assert.Nil(t, err)
+		assert.Nil(t, result.Rating)
+		assert.Equal(t, float32(10), result.Rate)
+		assert.Equal(t, 1, result.VoteCount)
+
+		_, err = useCase.Withdraw(context.Background(), "user1", filmId)
+		assertAppErrorCode(t, err, http.StatusNotFound)
+		_, err = useCase.Get(context.Background(), "user1", filmId)
+		assertAppErrorCode(t, err, http.StatusNotFound)
+	})
+
+	db := inMemDb.New()
+	db.CleanUp()
+}
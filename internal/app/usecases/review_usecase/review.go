@@ -0,0 +1,204 @@
+package reviewUseCase
+
+import (
+	"context"
+	"database/sql"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/google/uuid"
+)
+
+type (
+	ReviewUseCase interface {
+		Create(ctx context.Context, userId string, data appDto.CreateReviewUseCaseDto) (*aggregate.ReviewAggregate, error)
+		Update(ctx context.Context, userId string, data appDto.UpdateReviewUseCaseDto) (*aggregate.ReviewAggregate, error)
+		Delete(ctx context.Context, userId string, id string) error
+		// GetByFilm только одобренные рецензии фильма
+		GetByFilm(ctx context.Context, query domainQuery.ReviewRepositoryQuery) (*appDto.ReviewGetByQueryResult, error)
+		// GetQueue очередь модерации, по умолчанию рецензии в статусе pending
+		GetQueue(ctx context.Context, query domainQuery.ReviewRepositoryQuery) (*appDto.ReviewGetByQueryResult, error)
+		Moderate(ctx context.Context, data appDto.ModerateReviewUseCaseDto) (*aggregate.ReviewAggregate, error)
+	}
+
+	reviewUseCase struct {
+		repository.ReviewRepository
+		repository.FilmRepository
+		AuditService auditService.Service
+	}
+)
+
+func (r *reviewUseCase) Create(ctx context.Context, userId string, data appDto.CreateReviewUseCaseDto) (*aggregate.ReviewAggregate, error) {
+	reviewAggregate, err := aggregate.NewReviewAggregate(model.Review{
+		Id:      uuid.New().String(),
+		FilmId:  data.FilmId,
+		UserId:  userId,
+		Title:   data.Title,
+		Body:    data.Body,
+		Spoiler: data.Spoiler,
+		Status:  constants.ReviewPending,
+	})
+	if err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: ReviewUseCase, method: Create ", "error: ", err.Error())
+	}
+
+	if err := r.filmExists(ctx, data.FilmId, "Create"); err != nil {
+		return nil, err
+	}
+
+	has, err := r.ReviewRepository.HasByUserAndFilm(ctx, userId, data.FilmId)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ReviewUseCase, method: Create ", "repository has error: ", err.Error())
+	}
+	if has {
+		return nil, appErrors.Conflict(constants.ReviewExist)
+	}
+
+	// проверка выше не защищает от параллельной рецензии, ее отклоняет уникальный индекс
+	created, err := r.ReviewRepository.Create(ctx, reviewAggregate)
+	if err == repository.ErrReviewExist {
+		return nil, appErrors.Conflict(constants.ReviewExist)
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ReviewUseCase, method: Create ", "repository create error: ", err.Error())
+	}
+
+	return created, nil
+}
+
+// Update правка рецензии автором, измененная рецензия снова уходит на модерацию
+func (r *reviewUseCase) Update(ctx context.Context, userId string, data appDto.UpdateReviewUseCaseDto) (*aggregate.ReviewAggregate, error) {
+	review, err := r.ownReview(ctx, userId, data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Review.Title = data.Title
+	review.Review.Body = data.Body
+	review.Review.Spoiler = data.Spoiler
+	review.Review.Status = constants.ReviewPending
+	review.Review.ModerationNote = nil
+	if err := review.Validation(); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: ReviewUseCase, method: Update ", "error: ", err.Error())
+	}
+
+	updated, err := r.ReviewRepository.Update(ctx, review)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ReviewUseCase, method: Update ", "repository update error: ", err.Error())
+	}
+
+	return updated, nil
+}
+
+func (r *reviewUseCase) Delete(ctx context.Context, userId string, id string) error {
+	if _, err := r.ownReview(ctx, userId, id); err != nil {
+		return err
+	}
+
+	err := r.ReviewRepository.Delete(ctx, id)
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: ReviewUseCase, method: Delete ", "error: ", err.Error())
+	}
+	return nil
+}
+
+func (r *reviewUseCase) GetByFilm(ctx context.Context, query domainQuery.ReviewRepositoryQuery) (*appDto.ReviewGetByQueryResult, error) {
+	if err := r.filmExists(ctx, query.FilmId, "GetByFilm"); err != nil {
+		return nil, err
+	}
+
+	query.Statuses = []string{constants.ReviewApproved}
+	return r.getByQuery(ctx, query)
+}
+
+func (r *reviewUseCase) GetQueue(ctx context.Context, query domainQuery.ReviewRepositoryQuery) (*appDto.ReviewGetByQueryResult, error) {
+	if len(query.Statuses) == 0 {
+		query.Statuses = []string{constants.ReviewPending}
+	}
+	return r.getByQuery(ctx, query)
+}
+
+func (r *reviewUseCase) Moderate(ctx context.Context, data appDto.ModerateReviewUseCaseDto) (*aggregate.ReviewAggregate, error) {
+	if err := appValidator.New().Struct(data); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: ReviewUseCase, method: Moderate ", "error: ", err.Error())
+	}
+
+	var review *aggregate.ReviewAggregate
+	err := r.AuditService.Track(ctx, constants.AuditModerate, constants.AuditReview, data.Id, func(ctx context.Context) (interface{}, interface{}, error) {
+		before, err := r.ReviewRepository.GetById(ctx, data.Id)
+		if err != nil {
+			return nil, nil, err
+		}
+		review, err = r.ReviewRepository.SetStatus(ctx, data.Id, constants.ReviewActions[data.Action], data.Note)
+		if err != nil {
+			return nil, nil, err
+		}
+		return before.Review, review.Review, nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ReviewUseCase, method: Moderate ", "repository set status error: ", err.Error())
+	}
+
+	return review, nil
+}
+
+func (r *reviewUseCase) getByQuery(ctx context.Context, query domainQuery.ReviewRepositoryQuery) (*appDto.ReviewGetByQueryResult, error) {
+	reviews, pageCount, err := r.ReviewRepository.GetByQuery(ctx, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ReviewUseCase, method: GetByQuery ", "error: ", err.Error())
+	}
+
+	return &appDto.ReviewGetByQueryResult{
+		Reviews:   reviews,
+		PageCount: pageCount,
+	}, nil
+}
+
+func (r *reviewUseCase) ownReview(ctx context.Context, userId string, id string) (*aggregate.ReviewAggregate, error) {
+	review, err := r.ReviewRepository.GetById(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: ReviewUseCase, method: ownReview ", "error: ", err.Error())
+	}
+	if review.Review.UserId != userId {
+		return nil, appErrors.Forbidden(constants.ReviewNotOwner)
+	}
+	return review, nil
+}
+
+// filmExists 404 только для отсутствующего фильма, ошибка базы остается 500
+func (r *reviewUseCase) filmExists(ctx context.Context, filmId string, method string) error {
+	if _, err := uuid.Parse(filmId); err != nil {
+		return appErrors.NotFound("")
+	}
+	_, err := r.FilmRepository.GetById(ctx, filmId)
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: ReviewUseCase, method: "+method+" ", "repository get film error: ", err.Error())
+	}
+	return nil
+}
+
+func New(reviewRepository repository.ReviewRepository, filmRepository repository.FilmRepository, auditService auditService.Service) ReviewUseCase {
+	return &reviewUseCase{
+		ReviewRepository: reviewRepository,
+		FilmRepository:   filmRepository,
+		AuditService:     auditService,
+	}
+}
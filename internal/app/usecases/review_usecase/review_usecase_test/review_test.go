@@ -0,0 +1,139 @@
+package review_usecase_test
+
+import (
+	"context"
+	"errors"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	reviewUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/review_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func assertAppErrorCode(t *testing.T, err error, code int) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		assert.Equal(t, code, appErr.Code)
+	} else {
+		t.Fatal("incorrect error type")
+	}
+}
+
+// brokenFilmRepository фильм не читается из-за ошибки базы
+type brokenFilmRepository struct {
+	repository.FilmRepository
+}
+
+func (b brokenFilmRepository) GetById(ctx context.Context, id string) (*aggregate.FilmAggregate, error) {
+	return nil, errors.New("connection refused")
+}
+
+// racedReviewRepository параллельная рецензия появляется между проверкой и созданием
+type racedReviewRepository struct {
+	repository.ReviewRepository
+}
+
+func (r racedReviewRepository) HasByUserAndFilm(ctx context.Context, userId string, filmId string) (bool, error) {
+	return false, nil
+}
+
+func TestReviewUseCase(t *testing.T) {
+	useCase := reviewUseCase.New(mockRepository.NewReviewRepository(), mockRepository.NewFilmRepository(), auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()))
+	db := inMemDb.New()
+	filmId := uuid.New().String()
+	db.Film = append(db.Film, &model.Film{Id: filmId, Name: "Reviewed", ReleaseDate: time.Now()})
+	db.Rating = append(db.Rating, &model.Rating{UserId: "author", FilmId: filmId, Score: 9})
+
+	var review *aggregate.ReviewAggregate
+	t.Run("Should create review", func(t *testing.T) {
+		created, err := useCase.Create(context.Background(), "author", appDto.CreateReviewUseCaseDto{FilmId: filmId, Title: "Great", Body: "Watch it"})
+		assert.Nil(t, err)
+		assert.Equal(t, constants.ReviewPending, created.Review.Status)
+		assert.Equal(t, 9, created.Rating.Score)
+		review = created
+
+		_, err = useCase.Create(context.Background(), "author", appDto.CreateReviewUseCaseDto{FilmId: filmId, Title: "Again", Body: "Again"})
+		assertAppErrorCode(t, err, http.StatusConflict)
+		raced := reviewUseCase.New(racedReviewRepository{mockRepository.NewReviewRepository()}, mockRepository.NewFilmRepository(), auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()))
+		_, err = raced.Create(context.Background(), "author", appDto.CreateReviewUseCaseDto{FilmId: filmId, Title: "Again", Body: "Again"})
+		assertAppErrorCode(t, err, http.StatusConflict)
+		_, err = useCase.Create(context.Background(), "author", appDto.CreateReviewUseCaseDto{FilmId: uuid.New().String(), Title: "Great", Body: "Watch it"})
+		assertAppErrorCode(t, err, http.StatusNotFound)
+		_, err = useCase.Create(context.Background(), "other", appDto.CreateReviewUseCaseDto{FilmId: filmId, Title: "", Body: "Watch it"})
+		assertAppErrorCode(t, err, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("Should show only approved reviews", func(t *testing.T) {
+		query := *domainQuery.NewReviewRepositoryQuery()
+		query.FilmId = filmId
+		result, err := useCase.GetByFilm(context.Background(), query)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(result.Reviews))
+
+		queue, err := useCase.GetQueue(context.Background(), *domainQuery.NewReviewRepositoryQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(queue.Reviews))
+
+		moderated, err := useCase.Moderate(context.Background(), appDto.ModerateReviewUseCaseDto{Id: review.Review.Id, Action: "approve"})
+		assert.Nil(t, err)
+		assert.Equal(t, constants.ReviewApproved, moderated.Review.Status)
+
+		result, err = useCase.GetByFilm(context.Background(), query)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(result.Reviews))
+		assert.Equal(t, 1, result.PageCount)
+
+		_, err = useCase.Moderate(context.Background(), appDto.ModerateReviewUseCaseDto{Id: review.Review.Id, Action: "publish"})
+		assertAppErrorCode(t, err, http.StatusUnprocessableEntity)
+		_, err = useCase.Moderate(context.Background(), appDto.ModerateReviewUseCaseDto{Id: "incorrect", Action: "hide"})
+		assertAppErrorCode(t, err, http.StatusUnprocessableEntity)
+		_, err = useCase.Moderate(context.Background(), appDto.ModerateReviewUseCaseDto{Id: uuid.New().String(), Action: "hide"})
+		assertAppErrorCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("Should not hide film read errors", func(t *testing.T) {
+		query := *domainQuery.NewReviewRepositoryQuery()
+		query.FilmId = "incorrect"
+		_, err := useCase.GetByFilm(context.Background(), query)
+		assertAppErrorCode(t, err, http.StatusNotFound)
+
+		broken := reviewUseCase.New(mockRepository.NewReviewRepository(), brokenFilmRepository{}, auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()))
+		query.FilmId = filmId
+		_, err = broken.GetByFilm(context.Background(), query)
+		assertAppErrorCode(t, err, http.StatusInternalServerError)
+		_, err = broken.Create(context.Background(), "author", appDto.CreateReviewUseCaseDto{FilmId: filmId, Title: "Great", Body: "Watch it"})
+		assertAppErrorCode(t, err, http.StatusInternalServerError)
+	})
+
+	t.Run("Should return review to moderation after update", func(t *testing.T) {
+		_, err := useCase.Update(context.Background(), "other", appDto.UpdateReviewUseCaseDto{Id: review.Review.Id, Title: "Bad", Body: "Bad"})
+		assertAppErrorCode(t, err, http.StatusForbidden)
+
+		updated, err := useCase.Update(context.Background(), "author", appDto.UpdateReviewUseCaseDto{Id: review.Review.Id, Title: "Great!", Body: "Watch it", Spoiler: true})
+		assert.Nil(t, err)
+		assert.Equal(t, constants.ReviewPending, updated.Review.Status)
+		assert.True(t, updated.Review.Spoiler)
+	})
+
+	t.Run("Should delete review", func(t *testing.T) {
+		err := useCase.Delete(context.Background(), "other", review.Review.Id)
+		assertAppErrorCode(t, err, http.StatusForbidden)
+		err = useCase.Delete(context.Background(), "author", review.Review.Id)
+		assert.Nil(t, err)
+		err = useCase.Delete(context.Background(), "author", review.Review.Id)
+		assertAppErrorCode(t, err, http.StatusNotFound)
+	})
+
+	db.CleanUp()
+}
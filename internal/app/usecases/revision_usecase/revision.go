@@ -0,0 +1,259 @@
+package revisionUseCase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type (
+	// RevisionUseCase история правок фильмов и актеров. entityType - constants.AuditFilm или constants.AuditActor
+	RevisionUseCase interface {
+		GetByEntity(ctx context.Context, entityType string, entityId string, query domainQuery.PageQuery) (*appDto.RevisionGetByQueryResult, error)
+		GetByNumber(ctx context.Context, entityType string, entityId string, number int) (*model.Revision, error)
+		Diff(ctx context.Context, entityType string, entityId string, from int, to int) (*appDto.RevisionDiffResult, error)
+		// RevertFilm возвращает поля и титры фильма к ревизии, это новая правка со своей ревизией.
+		// Текущая версия фильма не проверяется, откат всегда поверх последней правки. Ручная оценка
+		// возвращается только при honor_manual_rate, как при обычном обновлении
+		RevertFilm(ctx context.Context, filmId string, number int) (*aggregate.FilmAggregate, error)
+		RevertActor(ctx context.Context, actorId string, number int) (*aggregate.ActorAggregate, error)
+	}
+
+	revisionUseCase struct {
+		repository.RevisionRepository
+		repository.FilmRepository
+		repository.ActorRepository
+		AuditService    auditService.Service
+		RevisionService revisionService.Service
+		honorManualRate bool
+	}
+)
+
+func (r *revisionUseCase) GetByEntity(ctx context.Context, entityType string, entityId string, query domainQuery.PageQuery) (*appDto.RevisionGetByQueryResult, error) {
+	revisions, pageCount, err := r.RevisionRepository.GetByEntity(ctx, entityType, entityId, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: RevisionUseCase, method: GetByEntity ", "error: ", err.Error())
+	}
+	if pageCount == 0 {
+		// у сущностей до появления истории ревизий нет, это пустой список, а не 404
+		if err := r.exists(ctx, entityType, entityId); err != nil {
+			return nil, err
+		}
+	}
+
+	return &appDto.RevisionGetByQueryResult{Revisions: revisions, PageCount: pageCount}, nil
+}
+
+func (r *revisionUseCase) GetByNumber(ctx context.Context, entityType string, entityId string, number int) (*model.Revision, error) {
+	revision, err := r.RevisionRepository.GetByNumber(ctx, entityType, entityId, number)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: RevisionUseCase, method: GetByNumber ", "error: ", err.Error())
+	}
+	return revision, nil
+}
+
+func (r *revisionUseCase) Diff(ctx context.Context, entityType string, entityId string, from int, to int) (*appDto.RevisionDiffResult, error) {
+	fromRevision, err := r.GetByNumber(ctx, entityType, entityId, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := r.GetByNumber(ctx, entityType, entityId, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := auditService.Diff(fromRevision.Snapshot, toRevision.Snapshot)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: RevisionUseCase, method: Diff ", "error: ", err.Error())
+	}
+	return &appDto.RevisionDiffResult{From: from, To: to, Diff: diff}, nil
+}
+
+func (r *revisionUseCase) RevertFilm(ctx context.Context, filmId string, number int) (*aggregate.FilmAggregate, error) {
+	err := r.AuditService.Track(ctx, constants.AuditRevert, constants.AuditFilm, filmId, func(ctx context.Context) (interface{}, interface{}, error) {
+		var snapshot aggregate.FilmAggregate
+		if err := r.snapshot(ctx, constants.AuditFilm, filmId, number, &snapshot); err != nil {
+			return nil, nil, err
+		}
+		current, err := r.FilmRepository.GetById(ctx, filmId)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reverted := &aggregate.FilmAggregate{Film: current.Film}
+		reverted.Film.Name, reverted.Film.Description = snapshot.Film.Name, snapshot.Film.Description
+		reverted.Film.ReleaseDate = snapshot.Film.ReleaseDate
+		if r.honorManualRate {
+			// как FilmUseCase.Update: без политики ручная оценка не меняется
+			reverted.Film.Rate, reverted.Film.ManualRate = snapshot.Film.Rate, snapshot.Film.ManualRate
+		}
+		reverted.Film.Version = 0
+		if err := reverted.Validation(); err != nil {
+			return nil, nil, appErrors.UnprocessableEntity("", "target: RevisionUseCase, method: RevertFilm ", "error: ", err.Error())
+		}
+		for _, credit := range snapshot.Credits {
+			credit.FilmId = filmId
+		}
+		if err := aggregate.ValidateCredits(snapshot.Credits); err != nil {
+			return nil, nil, appErrors.UnprocessableEntity("", "target: RevisionUseCase, method: RevertFilm ", "credit validation error: ", err.Error())
+		}
+
+		if _, err := r.FilmRepository.Update(ctx, reverted); err != nil {
+			return nil, nil, err
+		}
+		if err := r.FilmRepository.SetCast(ctx, filmId, snapshot.Credits...); err == sql.ErrNoRows {
+			return nil, nil, appErrors.Conflict("Revision cast has removed actors")
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		after, err := r.FilmRepository.GetById(ctx, filmId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := r.RevisionService.RecordFilms(ctx, constants.AuditRevert, filmId); err != nil {
+			return nil, nil, err
+		}
+		actorIds := revisionService.ChangedLinks(current.Credits, after.Credits, func(credit *model.Credit) string {
+			return credit.ActorId
+		})
+		if err := r.RevisionService.RecordActors(ctx, constants.AuditRevert, actorIds...); err != nil {
+			return nil, nil, err
+		}
+		return revisionService.FilmSnapshot(current), revisionService.FilmSnapshot(after), nil
+	})
+	if err != nil {
+		return nil, revertError(err, "RevertFilm")
+	}
+
+	film, err := r.FilmRepository.GetById(ctx, filmId)
+	if err != nil {
+		return nil, revertError(err, "RevertFilm")
+	}
+	return film, nil
+}
+
+func (r *revisionUseCase) RevertActor(ctx context.Context, actorId string, number int) (*aggregate.ActorAggregate, error) {
+	err := r.AuditService.Track(ctx, constants.AuditRevert, constants.AuditActor, actorId, func(ctx context.Context) (interface{}, interface{}, error) {
+		var snapshot aggregate.ActorAggregate
+		if err := r.snapshot(ctx, constants.AuditActor, actorId, number, &snapshot); err != nil {
+			return nil, nil, err
+		}
+		current, err := r.ActorRepository.GetById(ctx, actorId)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reverted := &aggregate.ActorAggregate{Actor: current.Actor}
+		reverted.Actor.Name, reverted.Actor.Gender, reverted.Actor.Birthday = snapshot.Actor.Name, snapshot.Actor.Gender, snapshot.Actor.Birthday
+		reverted.Actor.Version = 0
+		if err := reverted.Validation(); err != nil {
+			return nil, nil, appErrors.UnprocessableEntity("", "target: RevisionUseCase, method: RevertActor ", "error: ", err.Error())
+		}
+		for _, credit := range snapshot.Credits {
+			credit.ActorId = actorId
+		}
+		if err := aggregate.ValidateCredits(snapshot.Credits); err != nil {
+			return nil, nil, appErrors.UnprocessableEntity("", "target: RevisionUseCase, method: RevertActor ", "credit validation error: ", err.Error())
+		}
+
+		if _, err := r.ActorRepository.Update(ctx, reverted); err != nil {
+			return nil, nil, err
+		}
+		if err := r.ActorRepository.SetFilms(ctx, actorId, snapshot.Credits...); err == sql.ErrNoRows {
+			return nil, nil, appErrors.Conflict("Revision films has removed films")
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		after, err := r.ActorRepository.GetById(ctx, actorId)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := r.RevisionService.RecordActors(ctx, constants.AuditRevert, actorId); err != nil {
+			return nil, nil, err
+		}
+		filmIds := revisionService.ChangedLinks(current.Credits, after.Credits, func(credit *model.Credit) string {
+			return credit.FilmId
+		})
+		if err := r.RevisionService.RecordFilms(ctx, constants.AuditRevert, filmIds...); err != nil {
+			return nil, nil, err
+		}
+		return revisionService.ActorSnapshot(current), revisionService.ActorSnapshot(after), nil
+	})
+	if err != nil {
+		return nil, revertError(err, "RevertActor")
+	}
+
+	actor, err := r.ActorRepository.GetById(ctx, actorId)
+	if err != nil {
+		return nil, revertError(err, "RevertActor")
+	}
+	return actor, nil
+}
+
+// snapshot состояние сущности из ревизии number
+func (r *revisionUseCase) snapshot(ctx context.Context, entityType string, entityId string, number int, target interface{}) error {
+	revision, err := r.RevisionRepository.GetByNumber(ctx, entityType, entityId, number)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(revision.Snapshot, target)
+}
+
+// exists 404 если сущности нет в каталоге
+func (r *revisionUseCase) exists(ctx context.Context, entityType string, entityId string) error {
+	var err error
+	switch entityType {
+	case constants.AuditFilm:
+		_, err = r.FilmRepository.GetById(ctx, entityId)
+	case constants.AuditActor:
+		_, err = r.ActorRepository.GetById(ctx, entityId)
+	default:
+		return appErrors.NotFound("")
+	}
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: RevisionUseCase, method: exists ", "error: ", err.Error())
+	}
+	return nil
+}
+
+// revertError ошибки отката, уже готовые ошибки приложения (422, 409) возвращаются как есть
+func revertError(err error, method string) error {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	return appErrors.InternalServerError("", "target: RevisionUseCase, method: ", method, " error: ", err.Error())
+}
+
+func New(revisionRepository repository.RevisionRepository, filmRepository repository.FilmRepository, actorRepository repository.ActorRepository, auditService auditService.Service, revisionService revisionService.Service, honorManualRate bool) RevisionUseCase {
+	return &revisionUseCase{
+		RevisionRepository: revisionRepository,
+		FilmRepository:     filmRepository,
+		ActorRepository:    actorRepository,
+		AuditService:       auditService,
+		RevisionService:    revisionService,
+		honorManualRate:    honorManualRate,
+	}
+}
@@ -0,0 +1,129 @@
+package revision_usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	actorUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/actor_usecase"
+	filmUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/film_usecase"
+	revisionUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/revision_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisionUseCase(t *testing.T) {
+	db := inMemDb.New()
+	db.CleanUp()
+	filmRepo, actorRepo, revisionRepo := mockRepository.NewFilmRepository(), mockRepository.NewActorRepository(), mockRepository.NewRevisionRepository()
+	audit := auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager())
+	revisions := revisionService.New(revisionRepo, filmRepo, actorRepo)
+	films := filmUseCase.New(filmRepo, actorRepo, mockRepository.NewExternalIdRepository(), audit, revisions, true)
+	actors := actorUseCase.New(actorRepo, filmRepo, mockRepository.NewExternalIdRepository(), audit, revisions)
+	useCase := revisionUseCase.New(revisionRepo, filmRepo, actorRepo, audit, revisions, true)
+
+	film, err := films.Create(context.Background(), appDto.CreateFilmUseCaseDto{Name: "Original", ReleaseDate: time.Now().AddDate(-2, 0, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filmId := film.Film.Id
+	actor, err := actors.Create(context.Background(), appDto.CreateActorUseCaseDto{Name: "Linked", Gender: "male", Birthday: time.Now().AddDate(-30, 0, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should record edits and links", func(t *testing.T) {
+		film.Film.Name = "Edited"
+		_, err := films.Update(context.Background(), film, false)
+		assert.Nil(t, err)
+		err = actors.AddFilm(context.Background(), actor.Actor.Id, &model.Credit{FilmId: filmId, Role: constants.CreditActor})
+		assert.Nil(t, err)
+
+		result, err := useCase.GetByEntity(context.Background(), constants.AuditFilm, filmId, *domainQuery.NewPageQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(result.Revisions))
+		assert.Equal(t, 3, result.Revisions[0].Number)
+		assert.Equal(t, constants.AuditLink, result.Revisions[0].Action)
+
+		result, err = useCase.GetByEntity(context.Background(), constants.AuditActor, actor.Actor.Id, *domainQuery.NewPageQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(result.Revisions))
+	})
+
+	t.Run("Should diff any two revisions", func(t *testing.T) {
+		result, err := useCase.Diff(context.Background(), constants.AuditFilm, filmId, 1, 3)
+		assert.Nil(t, err)
+		var diff map[string]auditService.FieldChange
+		assert.Nil(t, json.Unmarshal(result.Diff, &diff))
+		assert.Equal(t, `"Original"`, string(diff["film.name"].Before))
+		assert.Equal(t, `"Edited"`, string(diff["film.name"].After))
+		assert.Equal(t, "null", string(diff["credits"].Before))
+		assert.Equal(t, 2, len(diff))
+
+		_, err = useCase.Diff(context.Background(), constants.AuditFilm, filmId, 1, 10)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusNotFound, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+	})
+
+	t.Run("Should revert as new revision", func(t *testing.T) {
+		reverted, err := useCase.RevertFilm(context.Background(), filmId, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Original", reverted.Film.Name)
+		assert.Equal(t, 0, len(reverted.Credits))
+
+		result, err := useCase.GetByEntity(context.Background(), constants.AuditFilm, filmId, *domainQuery.NewPageQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, 4, result.Revisions[0].Number)
+		assert.Equal(t, constants.AuditRevert, result.Revisions[0].Action)
+		result, err = useCase.GetByEntity(context.Background(), constants.AuditActor, actor.Actor.Id, *domainQuery.NewPageQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(result.Revisions))
+	})
+
+	t.Run("Should keep manual rate on revert without policy", func(t *testing.T) {
+		rate := float32(9)
+		for _, item := range db.Film {
+			if item.Id == filmId {
+				item.ManualRate, item.Rate = &rate, rate
+			}
+		}
+		reverted, err := revisionUseCase.New(revisionRepo, filmRepo, actorRepo, audit, revisions, false).RevertFilm(context.Background(), filmId, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, float32(9), *reverted.Film.ManualRate)
+	})
+
+	t.Run("Should validate reverted revision", func(t *testing.T) {
+		for _, revision := range db.Revision {
+			if revision.EntityId == filmId && revision.Number == 2 {
+				revision.Snapshot = json.RawMessage(`{"film":{"name":""}}`)
+			}
+		}
+		_, err := useCase.RevertFilm(context.Background(), filmId, 2)
+		var appErr *appErrors.AppError
+		if errors.As(err, &appErr) {
+			assert.Equal(t, http.StatusUnprocessableEntity, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+		current, err := films.GetById(context.Background(), filmId)
+		assert.Nil(t, err)
+		assert.Equal(t, "Original", current.Film.Name)
+	})
+
+	db.CleanUp()
+}
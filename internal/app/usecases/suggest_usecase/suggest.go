@@ -0,0 +1,39 @@
+package suggestUseCase
+
+import (
+	"context"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type (
+	SuggestUseCase interface {
+		Suggest(ctx context.Context, query domainQuery.SuggestQuery) (*appDto.SuggestUseCaseResult, error)
+	}
+
+	suggestUseCase struct {
+		repository.SuggestRepository
+	}
+)
+
+// Suggest вызывается на каждый ввод символа, поэтому пустой результат не считается ошибкой
+func (s *suggestUseCase) Suggest(ctx context.Context, query domainQuery.SuggestQuery) (*appDto.SuggestUseCaseResult, error) {
+	suggestions, err := s.SuggestRepository.Suggest(ctx, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: SuggestUseCase, method: Suggest ", "error: ", err.Error())
+	}
+	if suggestions == nil {
+		suggestions = []*model.Suggestion{}
+	}
+
+	return &appDto.SuggestUseCaseResult{Suggestions: suggestions}, nil
+}
+
+func New(suggestRepository repository.SuggestRepository) SuggestUseCase {
+	return &suggestUseCase{
+		SuggestRepository: suggestRepository,
+	}
+}
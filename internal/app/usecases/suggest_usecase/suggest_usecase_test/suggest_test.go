@@ -0,0 +1,50 @@
+package suggest_usecase_test
+
+import (
+	"context"
+	suggestUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/suggest_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSuggestUseCase(t *testing.T) {
+	db := inMemDb.New()
+	db.Film = append(db.Film,
+		&model.Film{Id: "f1", Name: "Интерстеллар", ReleaseDate: time.Now(), VoteCount: 100},
+		&model.Film{Id: "f2", Name: "Остров проклятых", ReleaseDate: time.Now()},
+	)
+	db.Actor = append(db.Actor, &model.Actor{Id: "a1", Name: "Леонардо ДиКаприо", Birthday: time.Now()})
+	db.ActorFilm = append(db.ActorFilm, &inMemDb.ActorFilm{ActorId: "a1", FilmId: "f2", Role: constants.CreditActor})
+	useCase := suggestUseCase.New(mockRepository.NewSuggestRepository())
+
+	t.Run("Should suggest film with typo", func(t *testing.T) {
+		res, err := useCase.Suggest(context.Background(), *domainQuery.NewSuggestQuery("Интерстелар"))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res.Suggestions))
+		assert.Equal(t, constants.SuggestFilm, res.Suggestions[0].Type)
+		assert.Equal(t, "f1", res.Suggestions[0].Id)
+	})
+
+	t.Run("Should suggest actor by part of name", func(t *testing.T) {
+		res, err := useCase.Suggest(context.Background(), *domainQuery.NewSuggestQuery("Ди Каприо"))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res.Suggestions))
+		assert.Equal(t, constants.SuggestActor, res.Suggestions[0].Type)
+		assert.Equal(t, 1, res.Suggestions[0].Popularity)
+	})
+
+	t.Run("Should return empty suggestions", func(t *testing.T) {
+		res, err := useCase.Suggest(context.Background(), *domainQuery.NewSuggestQuery("Матрица"))
+		assert.Nil(t, err)
+		assert.NotNil(t, res.Suggestions)
+		assert.Equal(t, 0, len(res.Suggestions))
+	})
+
+	db.CleanUp()
+}
@@ -0,0 +1,122 @@
+package trashUseCase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type (
+	// TrashUseCase корзина фильмов и актеров, удаление в остальных usecase только переносит запись сюда
+	TrashUseCase interface {
+		GetFilms(ctx context.Context, query domainQuery.PageQuery) (*appDto.FilmGetByQueryResult, error)
+		GetActors(ctx context.Context, query domainQuery.PageQuery) (*appDto.ActorGetByQueryResult, error)
+		RestoreFilm(ctx context.Context, id string) error
+		RestoreActor(ctx context.Context, id string) error
+		PurgeFilm(ctx context.Context, id string) error
+		PurgeActor(ctx context.Context, id string) error
+		// PurgeExpired удаляет навсегда все, что лежит в корзине дольше retention
+		PurgeExpired(ctx context.Context, retention time.Duration) (*appDto.PurgeTrashResult, error)
+	}
+
+	trashUseCase struct {
+		repository.FilmRepository
+		repository.ActorRepository
+		AuditService auditService.Service
+	}
+)
+
+// errNothingPurged откатывает пустую очистку, чтобы она не попала в журнал
+var errNothingPurged = errors.New("nothing purged")
+
+func (t *trashUseCase) GetFilms(ctx context.Context, query domainQuery.PageQuery) (*appDto.FilmGetByQueryResult, error) {
+	films, pageCount, err := t.FilmRepository.GetDeleted(ctx, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: TrashUseCase, method: GetFilms ", "error: ", err.Error())
+	}
+
+	return &appDto.FilmGetByQueryResult{Films: films, PageCount: pageCount}, nil
+}
+
+func (t *trashUseCase) GetActors(ctx context.Context, query domainQuery.PageQuery) (*appDto.ActorGetByQueryResult, error) {
+	actors, pageCount, err := t.ActorRepository.GetDeleted(ctx, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: TrashUseCase, method: GetActors ", "error: ", err.Error())
+	}
+
+	return &appDto.ActorGetByQueryResult{Actors: actors, PageCount: pageCount}, nil
+}
+
+func (t *trashUseCase) RestoreFilm(ctx context.Context, id string) error {
+	return trashError(t.track(ctx, constants.AuditRestore, constants.AuditFilm, id, t.FilmRepository.Restore), "RestoreFilm")
+}
+
+func (t *trashUseCase) RestoreActor(ctx context.Context, id string) error {
+	return trashError(t.track(ctx, constants.AuditRestore, constants.AuditActor, id, t.ActorRepository.Restore), "RestoreActor")
+}
+
+func (t *trashUseCase) PurgeFilm(ctx context.Context, id string) error {
+	return trashError(t.track(ctx, constants.AuditPurge, constants.AuditFilm, id, t.FilmRepository.Purge), "PurgeFilm")
+}
+
+func (t *trashUseCase) PurgeActor(ctx context.Context, id string) error {
+	return trashError(t.track(ctx, constants.AuditPurge, constants.AuditActor, id, t.ActorRepository.Purge), "PurgeActor")
+}
+
+// PurgeExpired пишет в журнал только если что-то удалено, иначе фоновая очистка засоряла бы его каждый тик
+func (t *trashUseCase) PurgeExpired(ctx context.Context, retention time.Duration) (*appDto.PurgeTrashResult, error) {
+	before := time.Now().Add(-retention)
+	result := &appDto.PurgeTrashResult{}
+	err := t.AuditService.Track(ctx, constants.AuditPurgeExpired, constants.AuditTrash, "", func(ctx context.Context) (interface{}, interface{}, error) {
+		var err error
+		if result.Films, err = t.FilmRepository.PurgeDeleted(ctx, before); err != nil {
+			return nil, nil, err
+		}
+		if result.Actors, err = t.ActorRepository.PurgeDeleted(ctx, before); err != nil {
+			return nil, nil, err
+		}
+		if result.Films == 0 && result.Actors == 0 {
+			return nil, nil, errNothingPurged
+		}
+		return nil, result, nil
+	})
+	if err != nil && err != errNothingPurged {
+		return nil, appErrors.InternalServerError("", "target: TrashUseCase, method: PurgeExpired ", "error: ", err.Error())
+	}
+
+	return result, nil
+}
+
+// track выполняет действие корзины над одной записью вместе с записью в журнал
+func (t *trashUseCase) track(ctx context.Context, action string, entityType string, id string, fn func(ctx context.Context, id string) error) error {
+	return t.AuditService.Track(ctx, action, entityType, id, func(ctx context.Context) (interface{}, interface{}, error) {
+		return nil, nil, fn(ctx, id)
+	})
+}
+
+// trashError записи нет в корзине - 404
+func trashError(err error, method string) error {
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: TrashUseCase, method: ", method, " error: ", err.Error())
+	}
+	return nil
+}
+
+func New(filmRepository repository.FilmRepository, actorRepository repository.ActorRepository, auditService auditService.Service) TrashUseCase {
+	return &trashUseCase{
+		FilmRepository:  filmRepository,
+		ActorRepository: actorRepository,
+		AuditService:    auditService,
+	}
+}
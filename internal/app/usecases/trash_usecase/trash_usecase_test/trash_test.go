@@ -0,0 +1,84 @@
+package trash_usecase_test
+
+import (
+	"context"
+	"errors"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	"net/http"
+	"testing"
+	"time"
+
+	trashUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/trash_usecase"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashUseCase(t *testing.T) {
+	db := inMemDb.New()
+	db.CleanUp()
+	filmRepo, actorRepo := mockRepository.NewFilmRepository(), mockRepository.NewActorRepository()
+	useCase := trashUseCase.New(filmRepo, actorRepo, auditService.New(mockRepository.NewAuditRepository(), mockRepository.NewTxManager()))
+
+	filmId, actorId := uuid.New().String(), uuid.New().String()
+	db.Film = append(db.Film, &model.Film{Id: filmId, Name: "Trash film", ReleaseDate: time.Now().AddDate(-3, 0, 0), Rate: 7, Version: 1})
+	db.Actor = append(db.Actor, &model.Actor{Id: actorId, Name: "Trash actor", Gender: "male", Birthday: time.Now().AddDate(-30, 0, 0), Version: 1})
+	db.ActorFilm = append(db.ActorFilm, &inMemDb.ActorFilm{ActorId: actorId, FilmId: filmId, Role: "actor"})
+
+	t.Run("Should restore film with links", func(t *testing.T) {
+		assert.Nil(t, filmRepo.Delete(context.Background(), filmId))
+		_, err := filmRepo.GetById(context.Background(), filmId)
+		assert.NotNil(t, err)
+
+		films, err := useCase.GetFilms(context.Background(), *domainQuery.NewPageQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(films.Films))
+		assert.Equal(t, 1, films.PageCount)
+
+		assert.Nil(t, useCase.RestoreFilm(context.Background(), filmId))
+		film, err := filmRepo.GetById(context.Background(), filmId)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(film.Actors))
+		assert.Equal(t, 2, film.Film.Version)
+
+		var appErr *appErrors.AppError
+		if errors.As(useCase.RestoreFilm(context.Background(), filmId), &appErr) {
+			assert.Equal(t, http.StatusNotFound, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+	})
+
+	t.Run("Should purge only expired", func(t *testing.T) {
+		assert.Nil(t, actorRepo.Delete(context.Background(), actorId))
+		result, err := useCase.PurgeExpired(context.Background(), time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, result.Actors)
+
+		result, err = useCase.PurgeExpired(context.Background(), 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Actors)
+		assert.Equal(t, 0, result.Films)
+		assert.Equal(t, 0, len(db.Actor))
+		assert.Equal(t, 0, len(db.ActorFilm))
+	})
+
+	t.Run("Should purge film by id", func(t *testing.T) {
+		var appErr *appErrors.AppError
+		if errors.As(useCase.PurgeFilm(context.Background(), filmId), &appErr) {
+			assert.Equal(t, http.StatusNotFound, appErr.Code)
+		} else {
+			t.Fatal("incorrect error type")
+		}
+
+		assert.Nil(t, filmRepo.Delete(context.Background(), filmId))
+		assert.Nil(t, useCase.PurgeFilm(context.Background(), filmId))
+		assert.Equal(t, 0, len(db.Film))
+	})
+
+	db.CleanUp()
+}
@@ -0,0 +1,252 @@
+package userListUseCase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/google/uuid"
+)
+
+type (
+	UserListUseCase interface {
+		// GetByUser списки пользователя, системные списки создаются при первом обращении
+		GetByUser(ctx context.Context, userId string) ([]*model.UserList, error)
+		Create(ctx context.Context, userId string, data appDto.CreateUserListUseCaseDto) (*model.UserList, error)
+		Update(ctx context.Context, userId string, data appDto.UpdateUserListUseCaseDto) (*model.UserList, error)
+		Delete(ctx context.Context, userId string, id string) error
+		AddFilm(ctx context.Context, userId string, data appDto.UserListFilmUseCaseDto) error
+		RemoveFilm(ctx context.Context, userId string, data appDto.UserListFilmUseCaseDto) error
+		MoveFilm(ctx context.Context, userId string, data appDto.MoveUserListFilmUseCaseDto) error
+		GetFilms(ctx context.Context, userId string, id string, query domainQuery.PageQuery) (*appDto.UserListFilmsResult, error)
+		GetShared(ctx context.Context, slug string, query domainQuery.PageQuery) (*appDto.UserListFilmsResult, error)
+	}
+
+	userListUseCase struct {
+		repository.UserListRepository
+	}
+)
+
+func (u *userListUseCase) GetByUser(ctx context.Context, userId string) ([]*model.UserList, error) {
+	lists, err := u.UserListRepository.GetByUser(ctx, userId)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: GetByUser ", "error: ", err.Error())
+	}
+
+	created := false
+	for _, system := range constants.SystemLists {
+		has := false
+		for _, list := range lists {
+			if list.Kind == system.Kind {
+				has = true
+			}
+		}
+		if has {
+			continue
+		}
+		// параллельный первый запрос мог уже создать список, тогда он просто перечитывается
+		err := u.UserListRepository.CreateSystem(ctx, &model.UserList{Id: uuid.New().String(), UserId: userId, Kind: system.Kind, Name: system.Name})
+		if err != nil {
+			return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: GetByUser ", "repository create error: ", err.Error())
+		}
+		created = true
+	}
+
+	if created {
+		lists, err = u.UserListRepository.GetByUser(ctx, userId)
+		if err != nil {
+			return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: GetByUser ", "error: ", err.Error())
+		}
+	}
+	return lists, nil
+}
+
+func (u *userListUseCase) Create(ctx context.Context, userId string, data appDto.CreateUserListUseCaseDto) (*model.UserList, error) {
+	list := &model.UserList{Id: uuid.New().String(), UserId: userId, Kind: constants.ListCustom, Name: data.Name}
+	if data.Shared {
+		slug, err := newShareSlug()
+		if err != nil {
+			return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: Create ", "slug error: ", err.Error())
+		}
+		list.ShareSlug = &slug
+	}
+	if err := appValidator.New().Struct(list); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: UserListUseCase, method: Create ", "error: ", err.Error())
+	}
+
+	created, err := u.UserListRepository.Create(ctx, list)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: Create ", "repository create error: ", err.Error())
+	}
+	return created, nil
+}
+
+func (u *userListUseCase) Update(ctx context.Context, userId string, data appDto.UpdateUserListUseCaseDto) (*model.UserList, error) {
+	list, err := u.ownList(ctx, userId, data.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Name = data.Name
+	if !data.Shared {
+		list.ShareSlug = nil
+	} else if list.ShareSlug == nil {
+		slug, err := newShareSlug()
+		if err != nil {
+			return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: Update ", "slug error: ", err.Error())
+		}
+		list.ShareSlug = &slug
+	}
+	if err := appValidator.New().Struct(list); err != nil {
+		return nil, appErrors.UnprocessableEntity("", "target: UserListUseCase, method: Update ", "error: ", err.Error())
+	}
+
+	updated, err := u.UserListRepository.Update(ctx, list)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: Update ", "repository update error: ", err.Error())
+	}
+	return updated, nil
+}
+
+func (u *userListUseCase) Delete(ctx context.Context, userId string, id string) error {
+	list, err := u.ownList(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+	if list.Kind != constants.ListCustom {
+		return appErrors.UnprocessableEntity(constants.SystemListReadonly)
+	}
+
+	err = u.UserListRepository.Delete(ctx, id)
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: UserListUseCase, method: Delete ", "error: ", err.Error())
+	}
+	return nil
+}
+
+func (u *userListUseCase) AddFilm(ctx context.Context, userId string, data appDto.UserListFilmUseCaseDto) error {
+	if _, err := u.ownList(ctx, userId, data.ListId); err != nil {
+		return err
+	}
+
+	if _, err := uuid.Parse(data.FilmId); err != nil {
+		return appErrors.NotFound("")
+	}
+	err := u.UserListRepository.AddFilm(ctx, data.ListId, data.FilmId)
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: UserListUseCase, method: AddFilm ", "error: ", err.Error())
+	}
+	return nil
+}
+
+func (u *userListUseCase) RemoveFilm(ctx context.Context, userId string, data appDto.UserListFilmUseCaseDto) error {
+	if _, err := u.ownList(ctx, userId, data.ListId); err != nil {
+		return err
+	}
+
+	if _, err := uuid.Parse(data.FilmId); err != nil {
+		return appErrors.NotFound("")
+	}
+	err := u.UserListRepository.RemoveFilm(ctx, data.ListId, data.FilmId)
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: UserListUseCase, method: RemoveFilm ", "error: ", err.Error())
+	}
+	return nil
+}
+
+func (u *userListUseCase) MoveFilm(ctx context.Context, userId string, data appDto.MoveUserListFilmUseCaseDto) error {
+	if err := appValidator.New().Struct(data); err != nil {
+		return appErrors.UnprocessableEntity("", "target: UserListUseCase, method: MoveFilm ", "error: ", err.Error())
+	}
+	if _, err := u.ownList(ctx, userId, data.ListId); err != nil {
+		return err
+	}
+
+	err := u.UserListRepository.MoveFilm(ctx, data.ListId, data.FilmId, data.Position)
+	if err == sql.ErrNoRows {
+		return appErrors.NotFound("")
+	}
+	if err != nil {
+		return appErrors.InternalServerError("", "target: UserListUseCase, method: MoveFilm ", "error: ", err.Error())
+	}
+	return nil
+}
+
+func (u *userListUseCase) GetFilms(ctx context.Context, userId string, id string, query domainQuery.PageQuery) (*appDto.UserListFilmsResult, error) {
+	list, err := u.ownList(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.films(ctx, list, query)
+}
+
+func (u *userListUseCase) GetShared(ctx context.Context, slug string, query domainQuery.PageQuery) (*appDto.UserListFilmsResult, error) {
+	list, err := u.UserListRepository.GetBySlug(ctx, slug)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: GetShared ", "error: ", err.Error())
+	}
+	return u.films(ctx, list, query)
+}
+
+func (u *userListUseCase) films(ctx context.Context, list *model.UserList, query domainQuery.PageQuery) (*appDto.UserListFilmsResult, error) {
+	films, pageCount, err := u.UserListRepository.GetFilms(ctx, list.Id, query)
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: GetFilms ", "error: ", err.Error())
+	}
+	return &appDto.UserListFilmsResult{
+		List:      list,
+		Films:     films,
+		PageCount: pageCount,
+	}, nil
+}
+
+// ownList список пользователя, id не uuid - 404 без запроса к базе
+func (u *userListUseCase) ownList(ctx context.Context, userId string, id string) (*model.UserList, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, appErrors.NotFound("")
+	}
+	list, err := u.UserListRepository.GetById(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, appErrors.NotFound("")
+	}
+	if err != nil {
+		return nil, appErrors.InternalServerError("", "target: UserListUseCase, method: ownList ", "error: ", err.Error())
+	}
+	if list.UserId != userId {
+		return nil, appErrors.Forbidden(constants.ListNotOwner)
+	}
+	return list, nil
+}
+
+func newShareSlug() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func New(userListRepository repository.UserListRepository) UserListUseCase {
+	return &userListUseCase{
+		UserListRepository: userListRepository,
+	}
+}
@@ -0,0 +1,117 @@
+package user_list_usecase_test
+
+import (
+	"context"
+	"errors"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	userListUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/user_list_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func assertAppErrorCode(t *testing.T, err error, code int) {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		assert.Equal(t, code, appErr.Code)
+	} else {
+		t.Fatal("incorrect error type")
+	}
+}
+
+func filmIds(result *appDto.UserListFilmsResult) []string {
+	ids := make([]string, 0, len(result.Films))
+	for _, film := range result.Films {
+		ids = append(ids, film.Film.Id)
+	}
+	return ids
+}
+
+func TestUserListUseCase(t *testing.T) {
+	useCase := userListUseCase.New(mockRepository.NewUserListRepository())
+	db := inMemDb.New()
+	db.CleanUp()
+	f1, f2, f3 := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	db.Film = append(db.Film, &model.Film{Id: f1}, &model.Film{Id: f2}, &model.Film{Id: f3})
+
+	var custom *model.UserList
+	t.Run("Should create system lists on first access", func(t *testing.T) {
+		lists, err := useCase.GetByUser(context.Background(), "user")
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(lists))
+		lists, err = useCase.GetByUser(context.Background(), "user")
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(lists))
+
+		custom, err = useCase.Create(context.Background(), "user", appDto.CreateUserListUseCaseDto{Name: "Nolan"})
+		assert.Nil(t, err)
+		assert.Equal(t, constants.ListCustom, custom.Kind)
+		assert.Nil(t, custom.ShareSlug)
+	})
+
+	t.Run("Should add, move and remove films", func(t *testing.T) {
+		for _, id := range []string{f1, f2, f3, f1} {
+			err := useCase.AddFilm(context.Background(), "user", appDto.UserListFilmUseCaseDto{ListId: custom.Id, FilmId: id})
+			assert.Nil(t, err)
+		}
+		err := useCase.AddFilm(context.Background(), "user", appDto.UserListFilmUseCaseDto{ListId: custom.Id, FilmId: "incorrect"})
+		assertAppErrorCode(t, err, http.StatusNotFound)
+		err = useCase.AddFilm(context.Background(), "other", appDto.UserListFilmUseCaseDto{ListId: custom.Id, FilmId: f1})
+		assertAppErrorCode(t, err, http.StatusForbidden)
+		_, err = useCase.GetFilms(context.Background(), "user", "incorrect", *domainQuery.NewPageQuery())
+		assertAppErrorCode(t, err, http.StatusNotFound)
+
+		err = useCase.MoveFilm(context.Background(), "user", appDto.MoveUserListFilmUseCaseDto{ListId: custom.Id, FilmId: f3, Position: 0})
+		assert.Nil(t, err)
+		result, err := useCase.GetFilms(context.Background(), "user", custom.Id, *domainQuery.NewPageQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, []string{f3, f1, f2}, filmIds(result))
+		assert.Equal(t, 3, result.List.FilmCount)
+
+		err = useCase.RemoveFilm(context.Background(), "user", appDto.UserListFilmUseCaseDto{ListId: custom.Id, FilmId: f1})
+		assert.Nil(t, err)
+		err = useCase.MoveFilm(context.Background(), "user", appDto.MoveUserListFilmUseCaseDto{ListId: custom.Id, FilmId: f3, Position: 10})
+		assert.Nil(t, err)
+		query := domainQuery.PageQuery{CurrentPage: 1, PageCount: 1}
+		result, err = useCase.GetFilms(context.Background(), "user", custom.Id, query)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{f2}, filmIds(result))
+		assert.Equal(t, 2, result.PageCount)
+	})
+
+	t.Run("Should share list by slug", func(t *testing.T) {
+		updated, err := useCase.Update(context.Background(), "user", appDto.UpdateUserListUseCaseDto{Id: custom.Id, Name: "Nolan", Shared: true})
+		assert.Nil(t, err)
+		assert.NotNil(t, updated.ShareSlug)
+
+		result, err := useCase.GetShared(context.Background(), *updated.ShareSlug, *domainQuery.NewPageQuery())
+		assert.Nil(t, err)
+		assert.Equal(t, []string{f2, f3}, filmIds(result))
+
+		_, err = useCase.Update(context.Background(), "user", appDto.UpdateUserListUseCaseDto{Id: custom.Id, Name: "Nolan", Shared: false})
+		assert.Nil(t, err)
+		_, err = useCase.GetShared(context.Background(), *updated.ShareSlug, *domainQuery.NewPageQuery())
+		assertAppErrorCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("Should delete only custom lists", func(t *testing.T) {
+		lists, _ := useCase.GetByUser(context.Background(), "user")
+		for _, list := range lists {
+			err := useCase.Delete(context.Background(), "user", list.Id)
+			if list.Kind == constants.ListCustom {
+				assert.Nil(t, err)
+			} else {
+				assertAppErrorCode(t, err, http.StatusUnprocessableEntity)
+			}
+		}
+	})
+
+	db.CleanUp()
+}
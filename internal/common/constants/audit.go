@@ -0,0 +1,27 @@
+package constants
+
+// действия в журнале изменений
+const (
+	AuditCreate       = "create"
+	AuditUpdate       = "update"
+	AuditDelete       = "delete"
+	AuditLink         = "link"
+	AuditUnlink       = "unlink"
+	AuditReplaceLinks = "replace_links"
+	AuditModerate     = "moderate"
+	AuditRestore      = "restore"
+	AuditPurge        = "purge"
+	AuditPurgeExpired = "purge_expired"
+	AuditRevert       = "revert"
+)
+
+// типы сущностей в журнале изменений
+const (
+	AuditFilm   = "film"
+	AuditActor  = "actor"
+	AuditGenre  = "genre"
+	AuditReview = "review"
+	AuditTrash  = "trash"
+)
+
+var AuditEntityTypes = []string{AuditFilm, AuditActor, AuditGenre, AuditReview, AuditTrash}
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	CreditActor    = "actor"
+	CreditDirector = "director"
+	CreditWriter   = "writer"
+	CreditProducer = "producer"
+	CreditComposer = "composer"
+)
+
+var CreditRoles = []string{CreditActor, CreditDirector, CreditWriter, CreditProducer, CreditComposer}
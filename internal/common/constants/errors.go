@@ -5,4 +5,10 @@ const (
 	UserEmailExist          = "Пользователь с таким email уже существует"
 	NickOrPasswordIncorrect = "Никнейм или пароль не корректны"
 	Unauthorized            = "Вы не авторизованы"
+	GenreNameExist          = "Жанр с таким названием уже существует"
+	RatingExist             = "Вы уже оценили этот фильм"
+	ReviewExist             = "Вы уже написали рецензию на этот фильм"
+	ReviewNotOwner          = "Рецензия принадлежит другому пользователю"
+	ListNotOwner            = "Список принадлежит другому пользователю"
+	SystemListReadonly      = "Системный список нельзя удалить"
 )
@@ -0,0 +1,10 @@
+package constants
+
+// источники внешних идентификаторов фильмов и актеров
+const (
+	ExternalImdb      = "imdb"
+	ExternalTmdb      = "tmdb"
+	ExternalKinopoisk = "kinopoisk"
+)
+
+var ExternalSources = []string{ExternalImdb, ExternalTmdb, ExternalKinopoisk}
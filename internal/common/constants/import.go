@@ -0,0 +1,35 @@
+package constants
+
+// что содержит файл импорта
+const (
+	ImportFilms   = "films"
+	ImportActors  = "actors"
+	ImportCredits = "credits"
+)
+
+var ImportKinds = []string{ImportFilms, ImportActors, ImportCredits}
+
+// CatalogColumns колонки CSV и поля NDJSON импорта и выгрузки для каждого вида. id, film и actor - идентификаторы каталога,
+// при импорте без них запись ищется по названию или имени и дате. Импорт фильмов и актеров принимает еще
+// колонки ExternalSources с внешними идентификаторами, в выгрузку они не попадают
+var CatalogColumns = map[string][]string{
+	ImportFilms:   {"id", "name", "description", "release", "rate"},
+	ImportActors:  {"id", "name", "gender", "birthday"},
+	ImportCredits: {"film", "filmName", "filmRelease", "actor", "actorName", "actorBirthday", "role", "character", "billingOrder"},
+}
+
+// форматы файла импорта
+const (
+	ImportCsv    = "csv"
+	ImportNdjson = "ndjson"
+)
+
+var ImportFormats = []string{ImportCsv, ImportNdjson}
+
+// итог импорта строки
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
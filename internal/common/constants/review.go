@@ -0,0 +1,17 @@
+package constants
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+	ReviewHidden   = "hidden"
+)
+
+var ReviewStatuses = []string{ReviewPending, ReviewApproved, ReviewRejected, ReviewHidden}
+
+// ReviewActions действия модератора и статус, в который они переводят рецензию
+var ReviewActions = map[string]string{
+	"approve": ReviewApproved,
+	"reject":  ReviewRejected,
+	"hide":    ReviewHidden,
+}
@@ -0,0 +1,6 @@
+package constants
+
+const (
+	SuggestFilm  = "film"
+	SuggestActor = "actor"
+)
@@ -0,0 +1,20 @@
+package constants
+
+const (
+	ListWantToWatch = "want_to_watch"
+	ListWatched     = "watched"
+	ListFavorites   = "favorites"
+	ListCustom      = "custom"
+)
+
+var ListKinds = []string{ListWantToWatch, ListWatched, ListFavorites, ListCustom}
+
+// SystemLists списки, которые есть у каждого пользователя, и их названия по умолчанию
+var SystemLists = []struct {
+	Kind string
+	Name string
+}{
+	{Kind: ListWantToWatch, Name: "Хочу посмотреть"},
+	{Kind: ListWatched, Name: "Просмотрено"},
+	{Kind: ListFavorites, Name: "Избранное"},
+}
@@ -16,6 +16,9 @@ const (
 	DefaultConflictMessage            = "Conflict"
 	DefaultUnauthorizedMessage        = "Unauthorized"
 	DefaultUnprocessableEntity        = "UnprocessableEntity"
+	DefaultUnsupportedMediaType       = "Unsupported media type"
+	DefaultPreconditionFailed         = "Precondition failed"
+	DefaultMethodNotAllowed           = "Method not allowed"
 	DefaultInternalServerErrorJson    = "{\"code\": 500, \"message\": \"" + DefaultInternalServerErrorMessage + "\"}"
 )
 
@@ -92,3 +95,24 @@ func NotFound(message string, devMessages ...string) error {
 	}
 	return HttpAppError(message, http.StatusNotFound, devMessages...)
 }
+
+func UnsupportedMediaType(message string, devMessages ...string) error {
+	if message == "" {
+		message = DefaultUnsupportedMediaType
+	}
+	return HttpAppError(message, http.StatusUnsupportedMediaType, devMessages...)
+}
+
+func PreconditionFailed(message string, devMessages ...string) error {
+	if message == "" {
+		message = DefaultPreconditionFailed
+	}
+	return HttpAppError(message, http.StatusPreconditionFailed, devMessages...)
+}
+
+func MethodNotAllowed(message string, devMessages ...string) error {
+	if message == "" {
+		message = DefaultMethodNotAllowed
+	}
+	return HttpAppError(message, http.StatusMethodNotAllowed, devMessages...)
+}
@@ -1,26 +1,41 @@
 package appErrors
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
 
 	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/google/uuid"
 )
 
+// RequestIdHeader id запроса от клиента или прокси, без него генерируется новый. Возвращается в ответе
+const RequestIdHeader = "X-Request-Id"
+
+// RequestIdContextKey ключ, под которым LoggingMiddleware кладет id запроса в контекст
+const RequestIdContextKey = ContextKey("requestId")
+
 type AppHandlerFunc func(res http.ResponseWriter, req *http.Request) error
 
 func LoggingMiddleware(logger *slog.Logger) func(handlerFunc AppHandlerFunc) http.HandlerFunc {
 	return func(handlerFunc AppHandlerFunc) http.HandlerFunc {
 		return func(res http.ResponseWriter, req *http.Request) {
+			requestId := req.Header.Get(RequestIdHeader)
+			if requestId == "" || len(requestId) > 128 {
+				requestId = uuid.New().String()
+			}
+			res.Header().Set(RequestIdHeader, requestId)
+			req = req.WithContext(context.WithValue(req.Context(), RequestIdContextKey, requestId))
+
 			err := handlerFunc(res, req)
 			if err != nil {
 				var appErr *AppError
 				if errors.As(err, &appErr) {
 					if appErr.Code >= 500 {
-						logger.Error("ERROR", "statusCode", appErr.Code, "errorMessage", appErr.Message, "developerMessage", appErr.DevMessage)
+						logger.Error("ERROR", "statusCode", appErr.Code, "errorMessage", appErr.Message, "developerMessage", appErr.DevMessage, "requestId", requestId)
 					} else if appErr.Code >= 400 {
-						logger.Info("INFO", "statusCode", appErr.Code, "errorMessage", appErr.Message, "developerMessage", appErr.DevMessage)
+						logger.Info("INFO", "statusCode", appErr.Code, "errorMessage", appErr.Message, "developerMessage", appErr.DevMessage, "requestId", requestId)
 					}
 					httpUtils.SendJson(res, appErr.Code, ResponseError{Code: appErr.Code, Message: appErr.Message})
 				}
@@ -28,3 +43,9 @@ func LoggingMiddleware(logger *slog.Logger) func(handlerFunc AppHandlerFunc) htt
 		}
 	}
 }
+
+// RequestIdFromContext id запроса из LoggingMiddleware, вне http запроса пустая строка
+func RequestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(RequestIdContextKey).(string)
+	return requestId
+}
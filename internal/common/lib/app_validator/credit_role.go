@@ -0,0 +1,11 @@
+package appValidator
+
+import (
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/go-playground/validator/v10"
+	"slices"
+)
+
+func creditRole(fl validator.FieldLevel) bool {
+	return slices.Contains(constants.CreditRoles, fl.Field().String())
+}
@@ -0,0 +1,11 @@
+package appValidator
+
+import (
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/go-playground/validator/v10"
+	"slices"
+)
+
+func listKind(fl validator.FieldLevel) bool {
+	return slices.Contains(constants.ListKinds, fl.Field().String())
+}
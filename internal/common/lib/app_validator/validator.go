@@ -10,6 +10,9 @@ func New() *validator.Validate {
 	_ = validate.RegisterValidation("dateIsLessNow", dateIsLessNow)
 	_ = validate.RegisterValidation("gender", isGender)
 	_ = validate.RegisterValidation("isValidPassword", isValidPassword)
+	_ = validate.RegisterValidation("creditRole", creditRole)
+	_ = validate.RegisterValidation("reviewStatus", reviewStatus)
+	_ = validate.RegisterValidation("listKind", listKind)
 
 	return validate
 }
@@ -1,13 +1,19 @@
 package aggregate
 
 import (
+	"cmp"
+	"slices"
+
 	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 )
 
 type ActorAggregate struct {
-	Actor model.Actor   `json:"actor"`
-	Films []*model.Film `json:"films,omitempty"`
+	Actor   model.Actor     `json:"actor"`
+	Films   []*model.Film   `json:"films,omitempty"`
+	Credits []*model.Credit `json:"credits,omitempty"`
+	// ExternalIds идентификаторы во внешних системах (источник -> идентификатор), заполняются при чтении по id
+	ExternalIds map[string]string `json:"externalIds,omitempty"`
 }
 
 func (a *ActorAggregate) Validation() error {
@@ -19,6 +25,18 @@ func (a *ActorAggregate) Validation() error {
 	return nil
 }
 
+// SetCredits сортирует титры по billing order и выстраивает Films в том же порядке
+func (a *ActorAggregate) SetCredits(credits []*model.Credit) {
+	sortCredits(credits)
+	a.Credits = credits
+	positions := billingPositions(credits, func(credit *model.Credit) string {
+		return credit.FilmId
+	})
+	slices.SortStableFunc(a.Films, func(x, y *model.Film) int {
+		return cmp.Compare(positions[x.Id], positions[y.Id])
+	})
+}
+
 func NewActorAggregate(actor model.Actor) (*ActorAggregate, error) {
 	result := &ActorAggregate{Actor: actor}
 	if err := result.Validation(); err != nil {
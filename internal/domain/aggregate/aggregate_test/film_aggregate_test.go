@@ -80,3 +80,24 @@ func TestFilmAggregate(t *testing.T) {
 		})
 	}
 }
+
+func TestFilmAggregateSetCredits(t *testing.T) {
+	film := &aggregate.FilmAggregate{
+		Film:   model.Film{Id: "film"},
+		Actors: []*model.Actor{{Id: "a1"}, {Id: "a2"}, {Id: "a3"}},
+	}
+	film.SetCredits([]*model.Credit{
+		{ActorId: "a1", FilmId: "film", Role: "actor", BillingOrder: 3},
+		{ActorId: "a3", FilmId: "film", Role: "director", BillingOrder: 0},
+		{ActorId: "a2", FilmId: "film", Role: "actor", BillingOrder: 1},
+		{ActorId: "a3", FilmId: "film", Role: "actor", BillingOrder: 2},
+	})
+
+	assert.Equal(t, 4, len(film.Credits))
+	for i := 1; i < len(film.Credits); i++ {
+		assert.LessOrEqual(t, film.Credits[i-1].BillingOrder, film.Credits[i].BillingOrder)
+	}
+	assert.Equal(t, "a3", film.Actors[0].Id)
+	assert.Equal(t, "a2", film.Actors[1].Id)
+	assert.Equal(t, "a1", film.Actors[2].Id)
+}
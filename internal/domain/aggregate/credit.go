@@ -0,0 +1,41 @@
+package aggregate
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+// ValidateCredits проверяет титры перед сохранением, пустая роль считается ролью actor
+func ValidateCredits(credits []*model.Credit) error {
+	validator := appValidator.New()
+	for _, credit := range credits {
+		if credit.Role == "" {
+			credit.Role = constants.CreditActor
+		}
+		if err := validator.Struct(credit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortCredits(credits []*model.Credit) {
+	slices.SortStableFunc(credits, func(a, b *model.Credit) int {
+		return cmp.Compare(a.BillingOrder, b.BillingOrder)
+	})
+}
+
+// billingPositions первая позиция в титрах для каждого ключа, credits должны быть отсортированы
+func billingPositions(credits []*model.Credit, key func(credit *model.Credit) string) map[string]int {
+	positions := make(map[string]int, len(credits))
+	for i, credit := range credits {
+		if _, ok := positions[key(credit)]; !ok {
+			positions[key(credit)] = i
+		}
+	}
+	return positions
+}
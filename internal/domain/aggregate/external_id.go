@@ -0,0 +1,47 @@
+package aggregate
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+)
+
+var (
+	externalNumeric = regexp.MustCompile(`^[1-9][0-9]{0,19}$`)
+
+	// filmExternalIds и actorExternalIds формат идентификатора по источникам
+	filmExternalIds = map[string]*regexp.Regexp{
+		constants.ExternalImdb:      regexp.MustCompile(`^tt[0-9]{7,10}$`),
+		constants.ExternalTmdb:      externalNumeric,
+		constants.ExternalKinopoisk: externalNumeric,
+	}
+	actorExternalIds = map[string]*regexp.Regexp{
+		constants.ExternalImdb:      regexp.MustCompile(`^nm[0-9]{7,10}$`),
+		constants.ExternalTmdb:      externalNumeric,
+		constants.ExternalKinopoisk: externalNumeric,
+	}
+)
+
+// ValidateFilmExternalIds проверяет источники и формат идентификаторов фильма, например imdb tt0111161
+func ValidateFilmExternalIds(ids map[string]string) error {
+	return validateExternalIds(filmExternalIds, ids)
+}
+
+// ValidateActorExternalIds как ValidateFilmExternalIds, у IMDb идентификатор актера вида nm0000209
+func ValidateActorExternalIds(ids map[string]string) error {
+	return validateExternalIds(actorExternalIds, ids)
+}
+
+func validateExternalIds(patterns map[string]*regexp.Regexp, ids map[string]string) error {
+	for source, externalId := range ids {
+		pattern, ok := patterns[source]
+		if !ok {
+			return fmt.Errorf("unknown external source %q", source)
+		}
+		if !pattern.MatchString(externalId) {
+			return fmt.Errorf("invalid %s id %q", source, externalId)
+		}
+	}
+	return nil
+}
@@ -1,13 +1,22 @@
 package aggregate
 
 import (
+	"cmp"
+	"slices"
+
 	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 )
 
 type FilmAggregate struct {
-	Film   model.Film     `json:"film"`
-	Actors []*model.Actor `json:"actors,omitempty"`
+	Film    model.Film      `json:"film"`
+	Actors  []*model.Actor  `json:"actors,omitempty"`
+	Genres  []*model.Genre  `json:"genres,omitempty"`
+	Credits []*model.Credit `json:"credits,omitempty"`
+	// ExternalIds идентификаторы во внешних системах (источник -> идентификатор), заполняются при чтении по id
+	ExternalIds map[string]string `json:"externalIds,omitempty"`
+	// Search заполняется только в результатах поиска
+	Search *model.FilmSearchMatch `json:"search,omitempty"`
 }
 
 func (f *FilmAggregate) Validation() error {
@@ -19,6 +28,18 @@ func (f *FilmAggregate) Validation() error {
 	return nil
 }
 
+// SetCredits сортирует титры по billing order и выстраивает Actors в том же порядке
+func (f *FilmAggregate) SetCredits(credits []*model.Credit) {
+	sortCredits(credits)
+	f.Credits = credits
+	positions := billingPositions(credits, func(credit *model.Credit) string {
+		return credit.ActorId
+	})
+	slices.SortStableFunc(f.Actors, func(a, b *model.Actor) int {
+		return cmp.Compare(positions[a.Id], positions[b.Id])
+	})
+}
+
 func NewFilmAggregate(film model.Film) (*FilmAggregate, error) {
 	result := &FilmAggregate{Film: film}
 	if err := result.Validation(); err != nil {
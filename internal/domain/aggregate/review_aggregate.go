@@ -0,0 +1,29 @@
+package aggregate
+
+import (
+	appValidator "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_validator"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+// ReviewAggregate рецензия вместе с оценкой, которую автор поставил фильму (если есть)
+type ReviewAggregate struct {
+	Review model.Review  `json:"review"`
+	Rating *model.Rating `json:"rating,omitempty"`
+}
+
+func (r *ReviewAggregate) Validation() error {
+	validator := appValidator.New()
+	err := validator.Struct(r.Review)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewReviewAggregate(review model.Review) (*ReviewAggregate, error) {
+	result := &ReviewAggregate{Review: review}
+	if err := result.Validation(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
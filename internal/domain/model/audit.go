@@ -0,0 +1,18 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry запись журнала изменений. UserId пустой у фоновых задач, Diff - изменившиеся поля {"path": {"before", "after"}}
+type AuditEntry struct {
+	Id         string          `json:"id"`
+	UserId     string          `json:"userId,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityId   string          `json:"entityId,omitempty"`
+	Diff       json.RawMessage `json:"diff" swaggertype:"object"`
+	RequestId  string          `json:"requestId,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Credit участие человека в фильме (строка actor_film)
+type Credit struct {
+	ActorId      string  `json:"actorId" validate:"required"`
+	FilmId       string  `json:"filmId" validate:"required"`
+	Role         string  `json:"role" validate:"required,creditRole"`
+	Character    *string `json:"character,omitempty" validate:"omitempty,max=150"`
+	BillingOrder int     `json:"billingOrder" validate:"min=0"`
+}
+
+// CreditDetails титр вместе с названием и датой выхода фильма, именем и датой рождения актера
+type CreditDetails struct {
+	Credit
+	FilmName      string
+	FilmRelease   time.Time
+	ActorName     string
+	ActorBirthday time.Time
+}
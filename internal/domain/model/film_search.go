@@ -0,0 +1,9 @@
+package model
+
+// FilmSearchMatch результат полнотекстового поиска по фильму: совпадения выделены тегами <b></b>
+type FilmSearchMatch struct {
+	Rank        float32 `json:"rank"`
+	TitleMatch  bool    `json:"titleMatch"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
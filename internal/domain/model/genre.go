@@ -0,0 +1,6 @@
+package model
+
+type Genre struct {
+	Id   string `json:"id" validate:"required,uuidv4"`
+	Name string `json:"name" validate:"required,min=1,max=50"`
+}
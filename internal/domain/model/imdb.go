@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// ImdbProgress сохраненный прогресс импорта IMDb: этап и кол-во уже обработанных записей этапа.
+// Fingerprint - параметры импорта и файлы датасета, продолжить можно только с теми же
+type ImdbProgress struct {
+	Fingerprint string    `json:"fingerprint"`
+	Stage       string    `json:"stage"`
+	Done        int       `json:"done"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type Rating struct {
+	UserId    string    `json:"userId" validate:"required"`
+	FilmId    string    `json:"filmId" validate:"required"`
+	Score     int       `json:"score" validate:"required,min=1,max=10"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
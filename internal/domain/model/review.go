@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type Review struct {
+	Id             string    `json:"id" validate:"required,uuidv4"`
+	FilmId         string    `json:"filmId" validate:"required"`
+	UserId         string    `json:"userId" validate:"required"`
+	Title          string    `json:"title" validate:"required,min=1,max=150"`
+	Body           string    `json:"body" validate:"required,min=1,max=10000"`
+	Spoiler        bool      `json:"spoiler"`
+	Status         string    `json:"status" validate:"required,reviewStatus"`
+	ModerationNote *string   `json:"moderationNote,omitempty" validate:"omitempty,max=500"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Revision состояние фильма или актера после правки. Number идет подряд с 1 внутри сущности,
+// Snapshot - агрегат сущности с титрами
+type Revision struct {
+	Id         string          `json:"id"`
+	EntityType string          `json:"entityType"`
+	EntityId   string          `json:"entityId"`
+	Number     int             `json:"number"`
+	Action     string          `json:"action"`
+	UserId     string          `json:"userId,omitempty"`
+	Snapshot   json.RawMessage `json:"snapshot" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
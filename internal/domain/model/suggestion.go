@@ -0,0 +1,10 @@
+package model
+
+// Suggestion подсказка для автодополнения: фильм или актер. Popularity - кол-во оценок фильма или фильмов актера
+type Suggestion struct {
+	Type       string  `json:"type"`
+	Id         string  `json:"id"`
+	Name       string  `json:"name"`
+	Similarity float32 `json:"similarity"`
+	Popularity int     `json:"popularity"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// UserList список фильмов пользователя. ShareSlug задан, только если список открыт по ссылке
+type UserList struct {
+	Id        string    `json:"id" validate:"required,uuidv4"`
+	UserId    string    `json:"userId" validate:"required"`
+	Kind      string    `json:"kind" validate:"required,listKind"`
+	Name      string    `json:"name" validate:"required,min=1,max=100"`
+	ShareSlug *string   `json:"shareSlug,omitempty" validate:"omitempty,min=8,max=32,alphanum"`
+	FilmCount int       `json:"filmCount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -2,16 +2,37 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 )
 
 type ActorRepository interface {
 	Create(ctx context.Context, aggregate *aggregate.ActorAggregate) (*aggregate.ActorAggregate, error)
+	// Update увеличивает Version, при устаревшей Version - ErrVersionConflict
 	Update(ctx context.Context, aggregate *aggregate.ActorAggregate) (*aggregate.ActorAggregate, error)
+	// Delete переносит актера в корзину, связи сохраняются. Актер в корзине не виден остальным методам, кроме Restore и Purge*
 	Delete(ctx context.Context, id string) error
-	AddFilm(ctx context.Context, actorId string, filmIds ...string) error
+	// GetDeleted корзина, сначала удаленные последними. Второе значение - кол-во страниц
+	GetDeleted(ctx context.Context, query domainQuery.PageQuery) ([]*aggregate.ActorAggregate, int, error)
+	// Restore возвращает актера из корзины вместе со связями
+	Restore(ctx context.Context, id string) error
+	// Purge удаляет актера из корзины навсегда
+	Purge(ctx context.Context, id string) error
+	// PurgeDeleted удаляет навсегда актеров, попавших в корзину раньше before. Возвращает кол-во удаленных
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+	// AddFilm повторная связь с той же ролью пропускается
+	AddFilm(ctx context.Context, actorId string, credits ...*model.Credit) error
+	// SetFilms заменяет все участия актера в фильмах одной транзакцией
+	SetFilms(ctx context.Context, actorId string, credits ...*model.Credit) error
+	// RemoveFilm убирает все роли актера в фильмах filmIds
+	RemoveFilm(ctx context.Context, actorId string, filmIds ...string) error
 	GetById(ctx context.Context, id string) (*aggregate.ActorAggregate, error)
+	// GetByNameAndBirthday актер с тем же именем без учета регистра и той же датой рождения, нет такого - sql.ErrNoRows
+	GetByNameAndBirthday(ctx context.Context, name string, birthday time.Time) (*aggregate.ActorAggregate, error)
 	GetByQuery(ctx context.Context, query domainQuery.ActorRepositoryQuery) ([]*aggregate.ActorAggregate, int, error)
+	// Each как FilmRepository.Each для актеров
+	Each(ctx context.Context, query domainQuery.ActorRepositoryQuery, fn func(actor *model.Actor) error) error
 }
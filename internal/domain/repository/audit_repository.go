@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+// AuditRepository журнал только дополняется, записи не меняются и не удаляются
+type AuditRepository interface {
+	Create(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error)
+	// GetByQuery записи от новых к старым, второе значение - кол-во страниц
+	GetByQuery(ctx context.Context, query domainQuery.AuditRepositoryQuery) ([]*model.AuditEntry, int, error)
+}
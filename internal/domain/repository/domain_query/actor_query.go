@@ -1,13 +1,26 @@
 package domainQuery
 
+// ActorRepositoryQuery пустые фильтры (nil, "") не применяются. SortField: name, birthday, пустое значение - по id
 type ActorRepositoryQuery struct {
+	SortField      string
+	OrderBy        OrderDirection
 	CurrentPage    int
 	PageCount      int
 	WithConnection []string
+	Name           string
+	Gender         string
+	BirthYearFrom  *int
+	BirthYearTo    *int
+	AgeFrom        *int
+	AgeTo          *int
+	FilmId         string
+	// Cursor задает keyset пагинацию вместо CurrentPage, как в FilmRepositoryQuery
+	Cursor *Cursor
 }
 
 func NewActorRepositoryQuery() *ActorRepositoryQuery {
 	return &ActorRepositoryQuery{
+		OrderBy:        Asc,
 		CurrentPage:    1,
 		PageCount:      10,
 		WithConnection: make([]string, 0, 4),
@@ -0,0 +1,21 @@
+package domainQuery
+
+import "time"
+
+// AuditRepositoryQuery пустые фильтры не применяются, From и To включительно
+type AuditRepositoryQuery struct {
+	UserId      string
+	EntityType  string
+	EntityId    string
+	From        *time.Time
+	To          *time.Time
+	CurrentPage int
+	PageCount   int
+}
+
+func NewAuditRepositoryQuery() *AuditRepositoryQuery {
+	return &AuditRepositoryQuery{
+		CurrentPage: 1,
+		PageCount:   10,
+	}
+}
@@ -6,3 +6,11 @@ const (
 	Asc  OrderDirection = "ASC"
 	Desc OrderDirection = "DESC"
 )
+
+// MatchMode задает семантику фильтра по нескольким значениям
+type MatchMode string
+
+const (
+	MatchAny MatchMode = "any"
+	MatchAll MatchMode = "all"
+)
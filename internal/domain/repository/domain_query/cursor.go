@@ -0,0 +1,121 @@
+package domainQuery
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor позиция для keyset пагинации: значение поля сортировки и id крайнего элемента страницы.
+// Before - страница перед курсором, иначе после него
+type Cursor struct {
+	SortField string         `json:"f"`
+	OrderBy   OrderDirection `json:"o"`
+	Value     string         `json:"v"`
+	Id        string         `json:"i"`
+	Before    bool           `json:"b,omitempty"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode непрозрачный токен для клиента
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Id == "" {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.OrderBy != Asc && cursor.OrderBy != Desc {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Greater true если нужны элементы с ключом больше курсора
+func (c Cursor) Greater() bool {
+	return (c.OrderBy == Desc) == c.Before
+}
+
+// Valid id курсора - uuid, а значение подходит по типу полю сортировки. Токен приходит от клиента,
+// поддельное значение иначе дошло бы до SQL
+func (c Cursor) Valid() bool {
+	if _, err := uuid.Parse(c.Id); err != nil {
+		return false
+	}
+	switch c.SortField {
+	case "release_date", "birthday":
+		_, err := c.Time()
+		return err == nil
+	case "rate":
+		value, err := c.Float()
+		return err == nil && !math.IsNaN(float64(value)) && !math.IsInf(float64(value), 0)
+	}
+	return true
+}
+
+func (c Cursor) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, c.Value)
+}
+
+func (c Cursor) Float() (float32, error) {
+	value, err := strconv.ParseFloat(c.Value, 32)
+	return float32(value), err
+}
+
+func TimeCursorValue(value time.Time) string {
+	return value.Format(time.RFC3339Nano)
+}
+
+func FloatCursorValue(value float32) string {
+	return strconv.FormatFloat(float64(value), 'f', -1, 32)
+}
+
+// CursorPage обрезает результат репозитория до pageCount и строит токены соседних страниц.
+// В режиме курсора репозиторий возвращает до pageCount+1 элементов, лишний означает, что страница не последняя.
+// В постраничном режиме (cursor == nil) соседние страницы определяются по currentPage и totalPages
+func CursorPage[T any](items []T, cursor *Cursor, pageCount int, currentPage int, totalPages int, cursorOf func(item T, before bool) Cursor) (page []T, next string, prev string) {
+	hasMore := len(items) > pageCount
+	switch {
+	case cursor == nil:
+		page = items
+	case cursor.Before && hasMore:
+		page = items[len(items)-pageCount:]
+	case hasMore:
+		page = items[:pageCount]
+	default:
+		page = items
+	}
+	if len(page) == 0 {
+		return page, "", ""
+	}
+
+	var hasNext, hasPrev bool
+	switch {
+	case cursor == nil:
+		hasNext, hasPrev = currentPage < totalPages, currentPage > 1
+	case cursor.Before:
+		hasNext, hasPrev = true, hasMore
+	default:
+		hasNext, hasPrev = hasMore, true
+	}
+	if hasNext {
+		next = cursorOf(page[len(page)-1], false).Encode()
+	}
+	if hasPrev {
+		prev = cursorOf(page[0], true).Encode()
+	}
+	return page, next, prev
+}
@@ -1,11 +1,30 @@
 package domainQuery
 
+import "time"
+
 type FilmRepositoryQuery struct {
 	SortField      string
 	OrderBy        OrderDirection
 	CurrentPage    int
 	PageCount      int
 	WithConnection []string
+	// Cursor задает keyset пагинацию вместо CurrentPage: SortField и OrderBy берутся из курсора,
+	// а репозиторий возвращает до PageCount+1 элементов (см. CursorPage)
+	Cursor *Cursor
+	FilmFilter
+}
+
+// FilmFilter спецификация фильтра фильмов: все заданные условия объединяются через AND, пустые поля не применяются.
+// Границы диапазонов включаются
+type FilmFilter struct {
+	Genres          []string
+	GenreMatch      MatchMode
+	ReleaseDateFrom *time.Time
+	ReleaseDateTo   *time.Time
+	RateFrom        *float32
+	RateTo          *float32
+	Actors          []string
+	ActorMatch      MatchMode
 }
 
 func NewFilmRepositoryQuery() *FilmRepositoryQuery {
@@ -15,5 +34,11 @@ func NewFilmRepositoryQuery() *FilmRepositoryQuery {
 		CurrentPage:    1,
 		PageCount:      10,
 		WithConnection: make([]string, 0, 4),
+		FilmFilter: FilmFilter{
+			Genres:     make([]string, 0, 4),
+			GenreMatch: MatchAny,
+			Actors:     make([]string, 0, 4),
+			ActorMatch: MatchAny,
+		},
 	}
 }
@@ -0,0 +1,15 @@
+package domainQuery
+
+type FilmSearchQuery struct {
+	Value       string
+	CurrentPage int
+	PageCount   int
+}
+
+func NewFilmSearchQuery(value string) *FilmSearchQuery {
+	return &FilmSearchQuery{
+		Value:       value,
+		CurrentPage: 1,
+		PageCount:   10,
+	}
+}
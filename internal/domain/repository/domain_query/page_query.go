@@ -0,0 +1,14 @@
+package domainQuery
+
+// PageQuery постраничный запрос без фильтров и сортировки
+type PageQuery struct {
+	CurrentPage int
+	PageCount   int
+}
+
+func NewPageQuery() *PageQuery {
+	return &PageQuery{
+		CurrentPage: 1,
+		PageCount:   10,
+	}
+}
@@ -0,0 +1,16 @@
+package domainQuery
+
+type ReviewRepositoryQuery struct {
+	FilmId      string
+	Statuses    []string
+	CurrentPage int
+	PageCount   int
+}
+
+func NewReviewRepositoryQuery() *ReviewRepositoryQuery {
+	return &ReviewRepositoryQuery{
+		CurrentPage: 1,
+		PageCount:   10,
+		Statuses:    make([]string, 0, 4),
+	}
+}
@@ -0,0 +1,13 @@
+package domainQuery
+
+type SuggestQuery struct {
+	Value string
+	Limit int
+}
+
+func NewSuggestQuery(value string) *SuggestQuery {
+	return &SuggestQuery{
+		Value: value,
+		Limit: 10,
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrExternalIdConflict идентификатор источника уже принадлежит другой записи
+var ErrExternalIdConflict = errors.New("external id conflict")
+
+// ExternalIdRepository идентификаторы фильмов и актеров во внешних системах, source - constants.ExternalSources.
+// У источника идентификатор принадлежит одной записи, у записи один идентификатор источника.
+// Связи удаляются вместе с записью. У записи в корзине связи сохраняются, но идентификатор не занят:
+// поиск ее не находит, а новая запись с тем же идентификатором забирает его себе, и после восстановления
+// у старой записи этого идентификатора уже нет
+type ExternalIdRepository interface {
+	// FilmIds id фильмов по идентификаторам источника, идентификатор без фильма в результат не попадает
+	FilmIds(ctx context.Context, source string, externalIds ...string) (map[string]string, error)
+	// GetFilm идентификаторы фильма по источникам, пустой map если их нет
+	GetFilm(ctx context.Context, filmId string) (map[string]string, error)
+	// SetFilm связывает идентификаторы ids (источник -> идентификатор) с фильмом.
+	// Идентификаторы фильма в тех же источниках заменяются, идентификатор другой записи - ErrExternalIdConflict
+	SetFilm(ctx context.Context, filmId string, ids map[string]string) error
+	// ActorIds как FilmIds для актеров
+	ActorIds(ctx context.Context, source string, externalIds ...string) (map[string]string, error)
+	GetActor(ctx context.Context, actorId string) (map[string]string, error)
+	SetActor(ctx context.Context, actorId string, ids map[string]string) error
+}
+
+// CheckExternalIds ErrExternalIdConflict, если какой-то из ids связан не с записью id. lookup - FilmIds или ActorIds
+func CheckExternalIds(ctx context.Context, lookup func(ctx context.Context, source string, externalIds ...string) (map[string]string, error), id string, ids map[string]string) error {
+	for source, externalId := range ids {
+		owners, err := lookup(ctx, source, externalId)
+		if err != nil {
+			return err
+		}
+		if owner, ok := owners[externalId]; ok && owner != id {
+			return fmt.Errorf("%w: %s %s", ErrExternalIdConflict, source, externalId)
+		}
+	}
+	return nil
+}
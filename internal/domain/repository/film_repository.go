@@ -2,16 +2,42 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 )
 
 type FilmRepository interface {
+	// Create сохраняет фильм, новых актеров из Actors и титры Credits одной транзакцией.
+	// Актер из титров, которого нет ни в базе ни в Actors - sql.ErrNoRows
 	Create(ctx context.Context, aggregate *aggregate.FilmAggregate) (*aggregate.FilmAggregate, error)
+	// Update увеличивает Version, при устаревшей Version - ErrVersionConflict
 	Update(ctx context.Context, aggregate *aggregate.FilmAggregate) (*aggregate.FilmAggregate, error)
+	// Delete переносит фильм в корзину, связи сохраняются. Фильм в корзине не виден остальным методам, кроме Restore и Purge*
 	Delete(ctx context.Context, id string) error
+	// GetDeleted корзина, сначала удаленные последними. Второе значение - кол-во страниц
+	GetDeleted(ctx context.Context, query domainQuery.PageQuery) ([]*aggregate.FilmAggregate, int, error)
+	// Restore возвращает фильм из корзины вместе со связями
+	Restore(ctx context.Context, id string) error
+	// Purge удаляет фильм из корзины навсегда
+	Purge(ctx context.Context, id string) error
+	// PurgeDeleted удаляет навсегда фильмы, попавшие в корзину раньше before. Возвращает кол-во удаленных
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
 	GetById(ctx context.Context, id string) (*aggregate.FilmAggregate, error)
+	// GetByNameAndRelease фильм с тем же названием без учета регистра и той же датой выхода, нет такого - sql.ErrNoRows
+	GetByNameAndRelease(ctx context.Context, name string, releaseDate time.Time) (*aggregate.FilmAggregate, error)
+	// SetCast заменяет все титры фильма одной транзакцией
+	SetCast(ctx context.Context, filmId string, credits ...*model.Credit) error
+	// RemoveActor убирает все роли актеров actorIds в фильме
+	RemoveActor(ctx context.Context, filmId string, actorIds ...string) error
 	GetByQuery(ctx context.Context, query domainQuery.FilmRepositoryQuery) ([]*aggregate.FilmAggregate, int, error)
-	SearchByNameAndActorName(ctx context.Context, searchValue string) ([]*aggregate.FilmAggregate, int, error)
+	// Each передает в fn по одному все фильмы с фильтрами и сортировкой query, без страниц и связей.
+	// Строки читаются из открытого курсора, поэтому fn не должна обращаться к базе. Ошибка fn прекращает обход
+	Each(ctx context.Context, query domainQuery.FilmRepositoryQuery, fn func(film *model.Film) error) error
+	// EachCredit как Each, но титры отобранных фильмов в порядке фильмов и billing order
+	EachCredit(ctx context.Context, query domainQuery.FilmRepositoryQuery, fn func(credit *model.CreditDetails) error) error
+	// SearchByNameAndActorName фильмы по релевантности, сначала совпадения в названии. Второе значение - общее кол-во найденных фильмов
+	SearchByNameAndActorName(ctx context.Context, query domainQuery.FilmSearchQuery) ([]*aggregate.FilmAggregate, int, error)
 }
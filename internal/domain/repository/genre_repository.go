@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+// ErrGenreNameExist название занято другим жанром
+var ErrGenreNameExist = errors.New("genre name exist")
+
+type GenreRepository interface {
+	// Create и Update при занятом названии возвращают ErrGenreNameExist
+	Create(ctx context.Context, model *model.Genre) (*model.Genre, error)
+	Update(ctx context.Context, model *model.Genre) (*model.Genre, error)
+	Delete(ctx context.Context, id string) error
+	GetById(ctx context.Context, id string) (*model.Genre, error)
+	GetAll(ctx context.Context) ([]*model.Genre, error)
+	HasByName(ctx context.Context, name string) (bool, error)
+	AddFilm(ctx context.Context, genreId string, filmIds ...string) error
+	RemoveFilm(ctx context.Context, genreId string, filmIds ...string) error
+}
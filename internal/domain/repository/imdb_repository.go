@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+// ImdbRepository прогресс импорта датасета IMDb, сами tconst и nconst хранятся в ExternalIdRepository
+type ImdbRepository interface {
+	// GetProgress прогресс незавершенного импорта, нет такого - sql.ErrNoRows
+	GetProgress(ctx context.Context) (*model.ImdbProgress, error)
+	// SaveProgress заменяет сохраненный прогресс
+	SaveProgress(ctx context.Context, progress *model.ImdbProgress) error
+	// DeleteProgress после завершения импорта, отсутствие прогресса не ошибка
+	DeleteProgress(ctx context.Context) error
+}
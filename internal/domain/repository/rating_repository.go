@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+)
+
+// ErrRatingExist пользователь уже оценил фильм
+var ErrRatingExist = errors.New("rating exist")
+
+// RatingRepository каждая запись пересчитывает rate и vote_count фильма в той же транзакции
+// и возвращает фильм с новыми значениями
+type RatingRepository interface {
+	// Create повторная оценка того же фильма - ErrRatingExist
+	Create(ctx context.Context, rating *model.Rating) (*model.Film, error)
+	Update(ctx context.Context, rating *model.Rating) (*model.Film, error)
+	Delete(ctx context.Context, userId string, filmId string) (*model.Film, error)
+	Get(ctx context.Context, userId string, filmId string) (*model.Rating, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+// ErrReviewExist пользователь уже написал рецензию на фильм
+var ErrReviewExist = errors.New("review exist")
+
+type ReviewRepository interface {
+	// Create вторая рецензия пользователя на тот же фильм - ErrReviewExist
+	Create(ctx context.Context, aggregate *aggregate.ReviewAggregate) (*aggregate.ReviewAggregate, error)
+	Update(ctx context.Context, aggregate *aggregate.ReviewAggregate) (*aggregate.ReviewAggregate, error)
+	Delete(ctx context.Context, id string) error
+	GetById(ctx context.Context, id string) (*aggregate.ReviewAggregate, error)
+	// GetByQuery рецензии от новых к старым, второе значение - кол-во страниц
+	GetByQuery(ctx context.Context, query domainQuery.ReviewRepositoryQuery) ([]*aggregate.ReviewAggregate, int, error)
+	HasByUserAndFilm(ctx context.Context, userId string, filmId string) (bool, error)
+	SetStatus(ctx context.Context, id string, status string, note *string) (*aggregate.ReviewAggregate, error)
+}
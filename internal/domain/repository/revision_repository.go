@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type RevisionRepository interface {
+	// Create сохраняет ревизию со следующим номером для сущности, Number в аргументе игнорируется
+	Create(ctx context.Context, revision *model.Revision) (*model.Revision, error)
+	// GetByEntity ревизии от новых к старым, второе значение - кол-во страниц
+	GetByEntity(ctx context.Context, entityType string, entityId string, query domainQuery.PageQuery) ([]*model.Revision, int, error)
+	GetByNumber(ctx context.Context, entityType string, entityId string, number int) (*model.Revision, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type SuggestRepository interface {
+	// Suggest фильмы и актеры вперемешку, похожие по триграммам на введенное значение. Сортировка по схожести и популярности
+	Suggest(ctx context.Context, query domainQuery.SuggestQuery) ([]*model.Suggestion, error)
+}
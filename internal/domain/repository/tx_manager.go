@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// TxManager выполняет несколько вызовов репозиториев атомарно.
+// Транзакция передается репозиториям через ctx, поэтому внутри fn нужно использовать полученный ctx.
+// Вложенный Do присоединяется к уже открытой транзакции
+type TxManager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type UserListRepository interface {
+	Create(ctx context.Context, list *model.UserList) (*model.UserList, error)
+	// CreateSystem создает системный список, если у пользователя еще нет списка этого вида. Уже созданный
+	// параллельным запросом список не ошибка
+	CreateSystem(ctx context.Context, list *model.UserList) error
+	Update(ctx context.Context, list *model.UserList) (*model.UserList, error)
+	Delete(ctx context.Context, id string) error
+	GetById(ctx context.Context, id string) (*model.UserList, error)
+	GetBySlug(ctx context.Context, slug string) (*model.UserList, error)
+	GetByUser(ctx context.Context, userId string) ([]*model.UserList, error)
+	// AddFilm добавляет фильм в конец списка, повторное добавление ничего не меняет
+	AddFilm(ctx context.Context, listId string, filmId string) error
+	RemoveFilm(ctx context.Context, listId string, filmId string) error
+	// MoveFilm ставит фильм на позицию position (с нуля), остальные сдвигаются
+	MoveFilm(ctx context.Context, listId string, filmId string, position int) error
+	// GetFilms фильмы списка в порядке позиций, второе значение - кол-во страниц
+	GetFilms(ctx context.Context, listId string, query domainQuery.PageQuery) ([]*aggregate.FilmAggregate, int, error)
+}
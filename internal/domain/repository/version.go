@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrVersionConflict Update с Version отличной от сохраненной: запись уже изменил кто-то другой.
+// Version 0 обновляет запись без проверки
+var ErrVersionConflict = errors.New("version conflict")
@@ -8,14 +8,26 @@ import (
 )
 
 type Config struct {
-	Env              string     `yaml:"env" env-default:"dev"`
-	ApiKey           string     `yaml:"api_key" env-defaul:"super-puper-secret-key"`
-	AdminName        string     `yaml:"admin_name"`
-	AdminPassword    string     `yaml:"admin_password"`
-	AccessTokenTime  string     `yaml:"access_token_time" env-default:"10m"`
-	RefreshTokenTime string     `yaml:"refresh_token_time" env-default:"1h"`
-	Server           HTTPServer `yaml:"http_server"`
-	Postgres         PostgreSQL `yaml:"postgres"`
+	Env              string `yaml:"env" env-default:"dev"`
+	ApiKey           string `yaml:"api_key" env-defaul:"super-puper-secret-key"`
+	AdminName        string `yaml:"admin_name"`
+	AdminPassword    string `yaml:"admin_password"`
+	AccessTokenTime  string `yaml:"access_token_time" env-default:"10m"`
+	RefreshTokenTime string `yaml:"refresh_token_time" env-default:"1h"`
+	AutoMigrate      bool   `yaml:"auto_migrate" env-default:"true"`
+	// HonorManualRate если false, rate фильма считается только по оценкам пользователей. Сохраненные ручные
+	// оценки админов при этом не удаляются и снова учитываются после включения
+	HonorManualRate bool       `yaml:"honor_manual_rate" env-default:"false"`
+	Server          HTTPServer `yaml:"http_server"`
+	Postgres        PostgreSQL `yaml:"postgres"`
+	Trash           Trash      `yaml:"trash"`
+}
+
+// Trash удаленные фильмы и актеры лежат в корзине Retention, фоновая очистка запускается раз в PurgeInterval.
+// Нулевой Retention отключает очистку
+type Trash struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
 }
 
 type PostgreSQL struct {
@@ -1,22 +1,69 @@
 package inMemDb
 
 import (
+	"math"
+	"time"
+
 	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/valuesobject"
 )
 
 type ActorFilm struct {
-	ActorId string
+	ActorId      string
+	FilmId       string
+	Role         string
+	Character    *string
+	BillingOrder int
+}
+
+func (a *ActorFilm) ToCredit() *model.Credit {
+	return &model.Credit{
+		ActorId:      a.ActorId,
+		FilmId:       a.FilmId,
+		Role:         a.Role,
+		Character:    a.Character,
+		BillingOrder: a.BillingOrder,
+	}
+}
+
+type FilmGenre struct {
 	FilmId  string
+	GenreId string
+}
+
+// ExternalId идентификатор фильма или актера EntityId во внешней системе Source
+type ExternalId struct {
+	Source     string
+	ExternalId string
+	EntityId   string
+}
+
+// UserListFilm позиции внутри списка идут подряд с нуля
+type UserListFilm struct {
+	ListId   string
+	FilmId   string
+	Position int
+	AddedAt  time.Time
 }
 
 type InMemDb struct {
-	Users     []*model.User
-	Tokens    []*model.Token
-	Actor     []*model.Actor
-	Film      []*model.Film
-	ActorFilm []*ActorFilm
+	Users           []*model.User
+	Tokens          []*model.Token
+	Actor           []*model.Actor
+	Film            []*model.Film
+	ActorFilm       []*ActorFilm
+	Genre           []*model.Genre
+	FilmGenre       []*FilmGenre
+	Rating          []*model.Rating
+	Review          []*model.Review
+	UserList        []*model.UserList
+	UserListFilm    []*UserListFilm
+	Audit           []*model.AuditEntry
+	Revision        []*model.Revision
+	FilmExternalId  []*ExternalId
+	ActorExternalId []*ExternalId
+	ImdbProgress    []*model.ImdbProgress
 }
 
 func (i *InMemDb) CleanUp() {
@@ -25,6 +72,73 @@ func (i *InMemDb) CleanUp() {
 	i.Actor = []*model.Actor{}
 	i.Film = []*model.Film{}
 	i.ActorFilm = []*ActorFilm{}
+	i.Genre = []*model.Genre{}
+	i.FilmGenre = []*FilmGenre{}
+	i.Rating = []*model.Rating{}
+	i.Review = []*model.Review{}
+	i.UserList = []*model.UserList{}
+	i.UserListFilm = []*UserListFilm{}
+	i.Audit = []*model.AuditEntry{}
+	i.Revision = []*model.Revision{}
+	i.FilmExternalId = []*ExternalId{}
+	i.ActorExternalId = []*ExternalId{}
+	i.ImdbProgress = []*model.ImdbProgress{}
+}
+
+// Snapshot копия всех таблиц, записи копируются чтобы откат не зависел от изменений на месте
+func (i *InMemDb) Snapshot() *InMemDb {
+	return &InMemDb{
+		Users:           cloneTable(i.Users),
+		Tokens:          cloneTable(i.Tokens),
+		Actor:           cloneTable(i.Actor),
+		Film:            cloneTable(i.Film),
+		ActorFilm:       cloneTable(i.ActorFilm),
+		Genre:           cloneTable(i.Genre),
+		FilmGenre:       cloneTable(i.FilmGenre),
+		Rating:          cloneTable(i.Rating),
+		Review:          cloneTable(i.Review),
+		UserList:        cloneTable(i.UserList),
+		UserListFilm:    cloneTable(i.UserListFilm),
+		Audit:           cloneTable(i.Audit),
+		Revision:        cloneTable(i.Revision),
+		FilmExternalId:  cloneTable(i.FilmExternalId),
+		ActorExternalId: cloneTable(i.ActorExternalId),
+		ImdbProgress:    cloneTable(i.ImdbProgress),
+	}
+}
+
+// Restore возвращает таблицы к снимку, так мок откатывает транзакцию
+func (i *InMemDb) Restore(snapshot *InMemDb) {
+	*i = *snapshot
+}
+
+func cloneTable[T any](items []*T) []*T {
+	result := make([]*T, len(items))
+	for index, item := range items {
+		cpy := *item
+		result[index] = &cpy
+	}
+	return result
+}
+
+// RecomputeRate пересчитывает VoteCount и Rate фильма по оценкам пользователей, как это делает postgres
+func (i *InMemDb) RecomputeRate(film *model.Film) {
+	sum, count := 0, 0
+	for _, rating := range i.Rating {
+		if rating.FilmId == film.Id {
+			sum += rating.Score
+			count++
+		}
+	}
+	film.VoteCount = count
+	switch {
+	case film.ManualRate != nil:
+		film.Rate = *film.ManualRate
+	case count == 0:
+		film.Rate = 0
+	default:
+		film.Rate = float32(math.Round(float64(sum)/float64(count)*10) / 10)
+	}
 }
 
 var instance *InMemDb = nil
@@ -35,11 +149,22 @@ func New() *InMemDb {
 	}
 
 	instance = &InMemDb{
-		Users:     []*model.User{},
-		Tokens:    []*model.Token{},
-		Actor:     []*model.Actor{},
-		Film:      []*model.Film{},
-		ActorFilm: []*ActorFilm{},
+		Users:           []*model.User{},
+		Tokens:          []*model.Token{},
+		Actor:           []*model.Actor{},
+		Film:            []*model.Film{},
+		ActorFilm:       []*ActorFilm{},
+		Genre:           []*model.Genre{},
+		FilmGenre:       []*FilmGenre{},
+		Rating:          []*model.Rating{},
+		Review:          []*model.Review{},
+		UserList:        []*model.UserList{},
+		UserListFilm:    []*UserListFilm{},
+		Audit:           []*model.AuditEntry{},
+		Revision:        []*model.Revision{},
+		FilmExternalId:  []*ExternalId{},
+		ActorExternalId: []*ExternalId{},
+		ImdbProgress:    []*model.ImdbProgress{},
 	}
 
 	password, _ := valuesobject.NewPassword("Adminadmin41")
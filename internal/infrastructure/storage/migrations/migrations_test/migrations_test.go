@@ -0,0 +1,60 @@
+package migrations_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/migrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("Should load embedded migrations", func(t *testing.T) {
+		result, err := migrations.Load()
+		assert.Nil(t, err)
+		assert.NotEmpty(t, result)
+		assert.Equal(t, int64(1), result[0].Version)
+		assert.Equal(t, "init", result[0].Name)
+		for i := 1; i < len(result); i++ {
+			assert.Less(t, result[i-1].Version, result[i].Version)
+		}
+	})
+
+	t.Run("Should sort by version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"000002_second.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+			"000002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+			"000001_first.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+			"000001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+		}
+		result, err := migrations.LoadFS(fsys)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(result))
+		assert.Equal(t, "first", result[0].Name)
+		assert.Equal(t, "DROP TABLE b;", result[1].Down)
+	})
+
+	t.Run("Should error without down file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"000001_first.up.sql": {Data: []byte("CREATE TABLE a ();")},
+		}
+		_, err := migrations.LoadFS(fsys)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Should error on incorrect file name", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"first.sql": {Data: []byte("CREATE TABLE a ();")},
+		}
+		_, err := migrations.LoadFS(fsys)
+		assert.NotNil(t, err)
+
+		fsys = fstest.MapFS{
+			"000001_first.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+			"000001_first.down.sql": {Data: []byte("DROP TABLE a;")},
+			"000001_other.down.sql": {Data: []byte("DROP TABLE a;")},
+		}
+		_, err = migrations.LoadFS(fsys)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tokens;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS actor_film;
+DROP TABLE IF EXISTS films;
+DROP TABLE IF EXISTS actors;
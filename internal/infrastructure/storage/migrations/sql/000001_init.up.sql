@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS actors (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    gender VARCHAR(10) NOT NULL,
+    birthday DATE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS films (
+    id UUID PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,
+    description TEXT,
+    release_date DATE NOT NULL,
+    rate NUMERIC(4,1) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS actor_film (
+    actor_id UUID REFERENCES actors(id),
+    film_id UUID REFERENCES films(id),
+    PRIMARY KEY (actor_id, film_id)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tokens (
+    id UUID PRIMARY KEY REFERENCES users(id),
+    value VARCHAR(255) NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS film_genre;
+DROP TABLE IF EXISTS genres;
@@ -0,0 +1,12 @@
+CREATE TABLE genres (
+    id UUID PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE film_genre (
+    film_id UUID REFERENCES films(id) ON DELETE CASCADE,
+    genre_id UUID REFERENCES genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (film_id, genre_id)
+);
+
+CREATE INDEX film_genre_genre_id_idx ON film_genre (genre_id);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS actor_film_film_id_idx;
+
+DELETE FROM actor_film WHERE role <> 'actor';
+
+ALTER TABLE actor_film DROP CONSTRAINT actor_film_role_check;
+ALTER TABLE actor_film DROP CONSTRAINT actor_film_pkey;
+ALTER TABLE actor_film ADD PRIMARY KEY (actor_id, film_id);
+
+ALTER TABLE actor_film
+    DROP COLUMN billing_order,
+    DROP COLUMN character,
+    DROP COLUMN role;
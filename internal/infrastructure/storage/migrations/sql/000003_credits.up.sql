@@ -0,0 +1,11 @@
+ALTER TABLE actor_film
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'actor',
+    ADD COLUMN character VARCHAR(150),
+    ADD COLUMN billing_order INT NOT NULL DEFAULT 0;
+
+ALTER TABLE actor_film DROP CONSTRAINT actor_film_pkey;
+ALTER TABLE actor_film ADD PRIMARY KEY (actor_id, film_id, role);
+ALTER TABLE actor_film ADD CONSTRAINT actor_film_role_check
+    CHECK (role IN ('actor', 'director', 'writer', 'producer', 'composer'));
+
+CREATE INDEX actor_film_film_id_idx ON actor_film (film_id, billing_order);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS ratings;
+
+UPDATE films SET rate = manual_rate WHERE manual_rate IS NOT NULL;
+
+ALTER TABLE films
+    DROP COLUMN vote_count,
+    DROP COLUMN manual_rate;
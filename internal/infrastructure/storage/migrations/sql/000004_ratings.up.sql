@@ -0,0 +1,16 @@
+ALTER TABLE films
+    ADD COLUMN manual_rate NUMERIC(4,1),
+    ADD COLUMN vote_count INT NOT NULL DEFAULT 0;
+
+UPDATE films SET manual_rate = rate;
+
+CREATE TABLE ratings (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    film_id UUID REFERENCES films(id) ON DELETE CASCADE,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 10),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, film_id)
+);
+
+CREATE INDEX ratings_film_id_idx ON ratings (film_id);
@@ -0,0 +1,17 @@
+CREATE TABLE reviews (
+    id UUID PRIMARY KEY,
+    film_id UUID NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(150) NOT NULL,
+    body TEXT NOT NULL,
+    spoiler BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'hidden')),
+    moderation_note VARCHAR(500),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, film_id)
+);
+
+CREATE INDEX reviews_film_status_idx ON reviews (film_id, status, created_at DESC);
+CREATE INDEX reviews_status_idx ON reviews (status, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_list_films;
+DROP TABLE IF EXISTS user_lists;
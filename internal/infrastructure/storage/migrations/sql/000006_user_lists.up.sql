@@ -0,0 +1,22 @@
+CREATE TABLE user_lists (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL
+        CHECK (kind IN ('want_to_watch', 'watched', 'favorites', 'custom')),
+    name VARCHAR(100) NOT NULL,
+    share_slug VARCHAR(32) UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX user_lists_user_id_idx ON user_lists (user_id);
+CREATE UNIQUE INDEX user_lists_system_kind_idx ON user_lists (user_id, kind) WHERE kind <> 'custom';
+
+CREATE TABLE user_list_films (
+    list_id UUID REFERENCES user_lists(id) ON DELETE CASCADE,
+    film_id UUID REFERENCES films(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (list_id, film_id)
+);
+
+CREATE INDEX user_list_films_position_idx ON user_list_films (list_id, position);
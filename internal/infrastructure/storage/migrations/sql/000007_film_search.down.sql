@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS actors_search_vector_update ON actors;
+DROP TRIGGER IF EXISTS actor_film_search_vector_update ON actor_film;
+DROP TRIGGER IF EXISTS films_search_vector_update ON films;
+DROP FUNCTION IF EXISTS actors_search_vector_trigger();
+DROP FUNCTION IF EXISTS actor_film_search_vector_trigger();
+DROP FUNCTION IF EXISTS films_search_vector_trigger();
+DROP FUNCTION IF EXISTS film_search_vector(UUID, TEXT, TEXT);
+DROP INDEX IF EXISTS films_search_vector_idx;
+ALTER TABLE films DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,63 @@
+ALTER TABLE films ADD COLUMN search_vector tsvector NOT NULL DEFAULT ''::tsvector;
+
+-- вес A - название, B - имена актеров, C - описание. Каждое поле индексируется русской и английской конфигурацией
+CREATE FUNCTION film_search_vector(p_id UUID, p_name TEXT, p_description TEXT) RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('russian', p_name), 'A') ||
+        setweight(to_tsvector('english', p_name), 'A') ||
+        setweight(to_tsvector('russian', actors.names), 'B') ||
+        setweight(to_tsvector('english', actors.names), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(p_description, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE(p_description, '')), 'C')
+    FROM (
+        SELECT COALESCE(string_agg(DISTINCT a.name, ' '), '') AS names
+        FROM actor_film af
+        JOIN actors a ON a.id = af.actor_id
+        WHERE af.film_id = p_id
+    ) actors
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION films_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := film_search_vector(NEW.id, NEW.name, NEW.description);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER films_search_vector_update
+    BEFORE INSERT OR UPDATE OF name, description ON films
+    FOR EACH ROW EXECUTE FUNCTION films_search_vector_trigger();
+
+CREATE FUNCTION actor_film_search_vector_trigger() RETURNS trigger AS $$
+DECLARE
+    target UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        target := OLD.film_id;
+    ELSE
+        target := NEW.film_id;
+    END IF;
+    UPDATE films SET search_vector = film_search_vector(id, name, description) WHERE id = target;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER actor_film_search_vector_update
+    AFTER INSERT OR UPDATE OR DELETE ON actor_film
+    FOR EACH ROW EXECUTE FUNCTION actor_film_search_vector_trigger();
+
+CREATE FUNCTION actors_search_vector_trigger() RETURNS trigger AS $$
+BEGIN
+    UPDATE films SET search_vector = film_search_vector(id, name, description)
+    WHERE id IN (SELECT film_id FROM actor_film WHERE actor_id = NEW.id);
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER actors_search_vector_update
+    AFTER UPDATE OF name ON actors
+    FOR EACH ROW EXECUTE FUNCTION actors_search_vector_trigger();
+
+UPDATE films SET search_vector = film_search_vector(id, name, description);
+
+CREATE INDEX films_search_vector_idx ON films USING GIN (search_vector);
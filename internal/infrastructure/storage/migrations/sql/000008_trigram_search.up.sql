@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- индексы для нечеткого поиска и подсказок (операторы %, <%, word_similarity)
+CREATE INDEX films_name_trgm_idx ON films USING GIN (name gin_trgm_ops);
+CREATE INDEX actors_name_trgm_idx ON actors USING GIN (name gin_trgm_ops);
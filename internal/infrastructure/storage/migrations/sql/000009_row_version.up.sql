@@ -0,0 +1,3 @@
+-- версия строки для оптимистичной блокировки, растет при каждом изменении записи
+ALTER TABLE films ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE actors ADD COLUMN version integer NOT NULL DEFAULT 1;
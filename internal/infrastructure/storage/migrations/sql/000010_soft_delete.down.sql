@@ -0,0 +1,9 @@
+DELETE FROM actor_film WHERE film_id IN (SELECT id FROM films WHERE deleted_at IS NOT NULL)
+    OR actor_id IN (SELECT id FROM actors WHERE deleted_at IS NOT NULL);
+DELETE FROM films WHERE deleted_at IS NOT NULL;
+DELETE FROM actors WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS actors_deleted_at_idx;
+DROP INDEX IF EXISTS films_deleted_at_idx;
+ALTER TABLE actors DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE films DROP COLUMN IF EXISTS deleted_at;
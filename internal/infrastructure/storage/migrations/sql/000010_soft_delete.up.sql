@@ -0,0 +1,6 @@
+-- удаленные записи остаются в корзине вместе со связями до восстановления или очистки
+ALTER TABLE films ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE actors ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX films_deleted_at_idx ON films (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX actors_deleted_at_idx ON actors (deleted_at) WHERE deleted_at IS NOT NULL;
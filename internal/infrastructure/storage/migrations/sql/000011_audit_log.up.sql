@@ -0,0 +1,15 @@
+-- журнал изменений админов, без внешних ключей чтобы записи переживали удаление пользователей и сущностей
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    user_id TEXT,
+    action VARCHAR(30) NOT NULL,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id TEXT NOT NULL DEFAULT '',
+    diff JSONB NOT NULL DEFAULT '{}',
+    request_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at DESC, id);
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id, created_at DESC);
+CREATE INDEX audit_log_user_idx ON audit_log (user_id, created_at DESC);
@@ -0,0 +1,12 @@
+-- история правок фильмов и актеров, snapshot - состояние сущности вместе с титрами после правки
+CREATE TABLE revisions (
+    id UUID PRIMARY KEY,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id UUID NOT NULL,
+    number INTEGER NOT NULL CHECK (number > 0),
+    action VARCHAR(30) NOT NULL,
+    user_id TEXT,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (entity_type, entity_id, number)
+);
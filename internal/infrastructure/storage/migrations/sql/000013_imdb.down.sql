@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS imdb_import_progress;
+DROP TABLE IF EXISTS imdb_names;
+DROP TABLE IF EXISTS imdb_titles;
@@ -0,0 +1,19 @@
+-- идентификаторы IMDb фильмов (tconst) и актеров (nconst), загруженных из датасета IMDb
+CREATE TABLE imdb_titles (
+    tconst VARCHAR(20) PRIMARY KEY,
+    film_id UUID NOT NULL UNIQUE REFERENCES films(id) ON DELETE CASCADE
+);
+
+CREATE TABLE imdb_names (
+    nconst VARCHAR(20) PRIMARY KEY,
+    actor_id UUID NOT NULL UNIQUE REFERENCES actors(id) ON DELETE CASCADE
+);
+
+-- прогресс незавершенного импорта IMDb, одна строка. fingerprint - параметры импорта и файлы, с которыми он начат
+CREATE TABLE imdb_import_progress (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    fingerprint TEXT NOT NULL,
+    stage VARCHAR(20) NOT NULL,
+    done INTEGER NOT NULL CHECK (done >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
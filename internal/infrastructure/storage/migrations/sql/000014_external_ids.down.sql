@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS imdb_titles (
+    tconst VARCHAR(20) PRIMARY KEY,
+    film_id UUID NOT NULL UNIQUE REFERENCES films(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS imdb_names (
+    nconst VARCHAR(20) PRIMARY KEY,
+    actor_id UUID NOT NULL UNIQUE REFERENCES actors(id) ON DELETE CASCADE
+);
+
+INSERT INTO imdb_titles (tconst, film_id) SELECT external_id, film_id FROM film_external_ids WHERE source = 'imdb';
+INSERT INTO imdb_names (nconst, actor_id) SELECT external_id, actor_id FROM actor_external_ids WHERE source = 'imdb';
+
+DROP TABLE IF EXISTS actor_external_ids;
+DROP TABLE IF EXISTS film_external_ids;
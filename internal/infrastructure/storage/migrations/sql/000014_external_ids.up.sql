@@ -0,0 +1,23 @@
+-- идентификаторы фильмов и актеров во внешних системах: у источника идентификатор принадлежит одной записи,
+-- у записи не больше одного идентификатора каждого источника. Связи IMDb из импорта датасета переносятся сюда
+CREATE TABLE film_external_ids (
+    source VARCHAR(20) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    film_id UUID NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    PRIMARY KEY (source, external_id),
+    UNIQUE (film_id, source)
+);
+
+CREATE TABLE actor_external_ids (
+    source VARCHAR(20) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    actor_id UUID NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    PRIMARY KEY (source, external_id),
+    UNIQUE (actor_id, source)
+);
+
+INSERT INTO film_external_ids (source, external_id, film_id) SELECT 'imdb', tconst, film_id FROM imdb_titles;
+INSERT INTO actor_external_ids (source, external_id, actor_id) SELECT 'imdb', nconst, actor_id FROM imdb_names;
+
+DROP TABLE imdb_names;
+DROP TABLE imdb_titles;
@@ -0,0 +1,5 @@
+-- оценка только по оценкам пользователей рядом с rate, которая учитывает ручную оценку админа.
+-- Какую из них отдавать, решает honor_manual_rate при чтении, ручные оценки не сбрасываются
+ALTER TABLE films ADD COLUMN user_rate NUMERIC(4,1) NOT NULL DEFAULT 0;
+
+UPDATE films f SET user_rate = COALESCE((SELECT ROUND(AVG(r.score)::numeric, 1) FROM ratings r WHERE r.film_id = f.id), 0);
@@ -0,0 +1,25 @@
+-- актеры из корзины не попадают в полнотекстовый поиск, как и в поиск по триграммам.
+-- Перенос в корзину и восстановление актера пересобирают вектор его фильмов
+CREATE OR REPLACE FUNCTION film_search_vector(p_id UUID, p_name TEXT, p_description TEXT) RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('russian', p_name), 'A') ||
+        setweight(to_tsvector('english', p_name), 'A') ||
+        setweight(to_tsvector('russian', actors.names), 'B') ||
+        setweight(to_tsvector('english', actors.names), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(p_description, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE(p_description, '')), 'C')
+    FROM (
+        SELECT COALESCE(string_agg(DISTINCT a.name, ' '), '') AS names
+        FROM actor_film af
+        JOIN actors a ON a.id = af.actor_id
+        WHERE af.film_id = p_id AND a.deleted_at IS NULL
+    ) actors
+$$ LANGUAGE sql STABLE;
+
+DROP TRIGGER IF EXISTS actors_search_vector_update ON actors;
+
+CREATE TRIGGER actors_search_vector_update
+    AFTER UPDATE OF name, deleted_at ON actors
+    FOR EACH ROW EXECUTE FUNCTION actors_search_vector_trigger();
+
+UPDATE films SET search_vector = film_search_vector(id, name, description);
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
@@ -29,27 +31,26 @@ func (a actorRepository) Create(ctx context.Context, aggregate *aggregate.ActorA
 		return nil, errors.New("conflict fields")
 	}
 
-	actor := model.Actor{Id: aggregate.Actor.Id, Name: aggregate.Actor.Name, Gender: aggregate.Actor.Gender, Birthday: aggregate.Actor.Birthday}
+	actor := model.Actor{Id: aggregate.Actor.Id, Name: aggregate.Actor.Name, Gender: aggregate.Actor.Gender, Birthday: aggregate.Actor.Birthday, Version: 1}
 	a.db.Actor = append(a.db.Actor, &actor)
+	aggregate.Actor = actor
 	return aggregate, nil
 }
 
 func (a actorRepository) Update(ctx context.Context, aggregate *aggregate.ActorAggregate) (*aggregate.ActorAggregate, error) {
-	has := slices.ContainsFunc(a.db.Actor, func(item *model.Actor) bool {
-		if aggregate.Actor.Id == item.Id {
-			return true
-		}
-		return false
-	})
-
-	if !has {
+	if !a.hasActor(aggregate.Actor.Id) {
 		return nil, sql.ErrNoRows
 	}
 
 	for i, item := range a.db.Actor {
 		if aggregate.Actor.Id == item.Id {
+			if aggregate.Actor.Version != 0 && aggregate.Actor.Version != item.Version {
+				return nil, repository.ErrVersionConflict
+			}
 			actor := aggregate.Actor
+			actor.Version, actor.DeletedAt = item.Version+1, nil
 			a.db.Actor[i] = &actor
+			aggregate.Actor = actor
 		}
 	}
 
@@ -57,87 +58,180 @@ func (a actorRepository) Update(ctx context.Context, aggregate *aggregate.ActorA
 }
 
 func (a actorRepository) Delete(ctx context.Context, id string) error {
-	has := false
-
-	var filteredActors []*model.Actor
+	for _, actor := range a.db.Actor {
+		if actor.Id == id && actor.DeletedAt == nil {
+			now := time.Now()
+			actor.DeletedAt = &now
+			bumpLinked(a.db, id)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
 
+func (a actorRepository) GetDeleted(ctx context.Context, query domainQuery.PageQuery) ([]*aggregate.ActorAggregate, int, error) {
+	deleted := make([]*model.Actor, 0, 8)
 	for _, actor := range a.db.Actor {
-		if actor.Id != id {
-			filteredActors = append(filteredActors, actor)
-		} else {
-			has = true
+		if actor.DeletedAt != nil {
+			deleted = append(deleted, actor)
 		}
 	}
+	page, pageCount := trashPage(deleted, func(actor *model.Actor) int64 {
+		return actor.DeletedAt.UnixNano()
+	}, query)
 
-	a.db.Actor = filteredActors
+	actors := make([]*aggregate.ActorAggregate, 0, len(page))
+	for _, actor := range page {
+		actors = append(actors, &aggregate.ActorAggregate{Actor: *actor})
+	}
+	return actors, pageCount, nil
+}
 
-	if has {
-		return nil
+func (a actorRepository) Restore(ctx context.Context, id string) error {
+	for _, actor := range a.db.Actor {
+		if actor.Id == id && actor.DeletedAt != nil {
+			actor.DeletedAt = nil
+			actor.Version++
+			bumpLinked(a.db, id)
+			return nil
+		}
 	}
 	return sql.ErrNoRows
 }
 
-func (a actorRepository) AddFilm(ctx context.Context, actorId string, filmIds ...string) error {
-	added := make([]*inMemDb.ActorFilm, 0, len(filmIds))
-	for _, id := range filmIds {
-		var searchedFilm *model.Film
-		for _, film := range a.db.Film {
-			if film.Id == id {
-				searchedFilm = film
-			}
-		}
+func (a actorRepository) Purge(ctx context.Context, id string) error {
+	purged := purgeActors(a.db, func(actor *model.Actor) bool {
+		return actor.Id == id
+	})
+	if purged == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-		if searchedFilm == nil {
-			return sql.ErrNoRows
-		}
+func (a actorRepository) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	return purgeActors(a.db, func(actor *model.Actor) bool {
+		return actor.DeletedAt.Before(before)
+	}), nil
+}
 
-		var searchedActor *model.Actor
-		for _, actor := range a.db.Actor {
-			if actor.Id == actorId {
-				searchedActor = actor
-			}
-		}
+func (a actorRepository) AddFilm(ctx context.Context, actorId string, credits ...*model.Credit) error {
+	if !a.hasActor(actorId) {
+		return sql.ErrNoRows
+	}
+	for _, credit := range credits {
+		credit.ActorId = actorId
+	}
+	if err := checkCredits(a.db, credits); err != nil {
+		return err
+	}
 
-		if searchedActor == nil {
-			return sql.ErrNoRows
-		}
+	changeCredits(a.db, func() {
+		insertCredits(a.db, credits)
+	})
+	return nil
+}
+
+func (a actorRepository) SetFilms(ctx context.Context, actorId string, credits ...*model.Credit) error {
+	if !a.hasActor(actorId) {
+		return sql.ErrNoRows
+	}
+	for _, credit := range credits {
+		credit.ActorId = actorId
+	}
+	if err := checkCredits(a.db, credits); err != nil {
+		return err
+	}
 
-		added = append(added, &inMemDb.ActorFilm{
-			ActorId: searchedActor.Id,
-			FilmId:  searchedFilm.Id,
+	changeCredits(a.db, func() {
+		a.db.ActorFilm = slices.DeleteFunc(a.db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.ActorId == actorId
 		})
+		insertCredits(a.db, credits)
+	})
+	return nil
+}
+
+func (a actorRepository) RemoveFilm(ctx context.Context, actorId string, filmIds ...string) error {
+	if !a.hasActor(actorId) {
+		return sql.ErrNoRows
 	}
 
-	a.db.ActorFilm = append(a.db.ActorFilm, added...)
+	changeCredits(a.db, func() {
+		a.db.ActorFilm = slices.DeleteFunc(a.db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.ActorId == actorId && slices.Contains(filmIds, item.FilmId)
+		})
+	})
 	return nil
 }
 
+func (a actorRepository) hasActor(id string) bool {
+	return slices.ContainsFunc(liveActors(a.db), func(item *model.Actor) bool {
+		return item.Id == id
+	})
+}
+
+// credits титры актера из in memory связей
+func (a actorRepository) credits(actorId string) []*model.Credit {
+	var credits []*model.Credit = nil
+	for _, item := range liveLinks(a.db) {
+		if item.ActorId == actorId {
+			credits = append(credits, item.ToCredit())
+		}
+	}
+	return credits
+}
+
 func (a actorRepository) GetById(ctx context.Context, id string) (*aggregate.ActorAggregate, error) {
 	var searched *model.Actor
-	for _, actor := range a.db.Actor {
+	for _, actor := range liveActors(a.db) {
 		if actor.Id == id {
 			searched = actor
 		}
 	}
 	if searched != nil {
-		return &aggregate.ActorAggregate{Actor: *searched}, nil
+		result := &aggregate.ActorAggregate{Actor: *searched}
+		result.SetCredits(a.credits(id))
+		return result, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (a actorRepository) GetByNameAndBirthday(ctx context.Context, name string, birthday time.Time) (*aggregate.ActorAggregate, error) {
+	for _, actor := range liveActors(a.db) {
+		if strings.EqualFold(actor.Name, name) && sameDate(actor.Birthday, birthday) {
+			return a.GetById(ctx, actor.Id)
+		}
 	}
 	return nil, sql.ErrNoRows
 }
 
 func (a actorRepository) GetByQuery(ctx context.Context, query domainQuery.ActorRepositoryQuery) ([]*aggregate.ActorAggregate, int, error) {
-	if len(a.db.Actor) == 0 {
+	filtered := a.filtered(query)
+
+	if len(filtered) == 0 {
 		return []*aggregate.ActorAggregate{}, 0, nil
 	}
 
-	start := query.PageCount * (query.CurrentPage - 1)
-	if start >= len(a.db.Actor) {
-		return nil, 0, errors.New("current page incorrect")
+	remainder := len(filtered) % query.PageCount
+	totalPageCount := len(filtered) / query.PageCount
+	if remainder != 0 {
+		totalPageCount++
+	}
+
+	// с курсором выборка ограничивается соседними с ним актерами, берется на одного больше
+	start, fetch := query.PageCount*(query.CurrentPage-1), query.PageCount
+	if query.Cursor != nil {
+		filtered, start, fetch = keysetWindow(filtered, query.Cursor, query.PageCount+1, actorOrder(query.SortField, query.OrderBy), cursorActor(query.Cursor)), 0, query.PageCount+1
+	}
+	// страница за пределами выборки пустая, как и в postgres
+	if start >= len(filtered) {
+		return []*aggregate.ActorAggregate{}, totalPageCount, nil
 	}
 
-	getted := make([]*aggregate.ActorAggregate, 0, query.PageCount)
+	getted := make([]*aggregate.ActorAggregate, 0, fetch)
 
-	for i, j := 0, start; j < len(a.db.Actor) && i < query.PageCount; i++ {
+	for i, j := 0, start; j < len(filtered) && i < fetch; i++ {
 		var films []*model.Film = nil
 		isFilmConnection := slices.ContainsFunc(query.WithConnection, func(item string) bool {
 			if item == "film" {
@@ -148,12 +242,12 @@ func (a actorRepository) GetByQuery(ctx context.Context, query domainQuery.Actor
 		if isFilmConnection {
 			filmIds := make([]string, 0, 50)
 			for _, item := range a.db.ActorFilm {
-				if item.ActorId == a.db.Actor[j].Id {
+				if item.ActorId == filtered[j].Id {
 					filmIds = append(filmIds, item.FilmId)
 				}
 			}
 
-			for _, film := range a.db.Film {
+			for _, film := range liveFilms(a.db) {
 				has := slices.ContainsFunc(filmIds, func(id string) bool {
 					if id == film.Id {
 						return true
@@ -167,20 +261,109 @@ func (a actorRepository) GetByQuery(ctx context.Context, query domainQuery.Actor
 			}
 		}
 		aggr := aggregate.ActorAggregate{
-			Actor: *a.db.Actor[j],
+			Actor: *filtered[j],
 			Films: films,
 		}
+		if isFilmConnection {
+			aggr.SetCredits(a.credits(filtered[j].Id))
+		}
 		getted = append(getted, &aggr)
 		j++
 	}
 
-	remainder := len(a.db.Actor) % query.PageCount
-	totalPageCount := len(a.db.Actor) / query.PageCount
-	if remainder != 0 {
-		totalPageCount++
+	return getted, totalPageCount, nil
+}
+
+func (a actorRepository) Each(ctx context.Context, query domainQuery.ActorRepositoryQuery, fn func(actor *model.Actor) error) error {
+	for _, actor := range a.filtered(query) {
+		cpy := *actor
+		if err := fn(&cpy); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return getted, totalPageCount, nil
+// filtered актеры каталога под фильтр query в его сортировке
+func (a actorRepository) filtered(query domainQuery.ActorRepositoryQuery) []*model.Actor {
+	filtered := make([]*model.Actor, 0, len(a.db.Actor))
+	for _, actor := range liveActors(a.db) {
+		if a.matchQuery(actor, query) {
+			filtered = append(filtered, actor)
+		}
+	}
+	sortActors(filtered, query)
+	return filtered
+}
+
+// matchQuery фильтры как в postgres репозитории, имя ищется по вхождению или по триграммам
+func (a actorRepository) matchQuery(actor *model.Actor, query domainQuery.ActorRepositoryQuery) bool {
+	if query.Name != "" && !strings.Contains(strings.ToLower(actor.Name), strings.ToLower(query.Name)) &&
+		wordSimilarity(query.Name, actor.Name) < similarityThreshold {
+		return false
+	}
+	if query.Gender != "" && actor.Gender != query.Gender {
+		return false
+	}
+	year := actor.Birthday.Year()
+	if (query.BirthYearFrom != nil && year < *query.BirthYearFrom) || (query.BirthYearTo != nil && year > *query.BirthYearTo) {
+		return false
+	}
+	age := actorAge(actor.Birthday, time.Now())
+	if (query.AgeFrom != nil && age < *query.AgeFrom) || (query.AgeTo != nil && age > *query.AgeTo) {
+		return false
+	}
+	if query.FilmId != "" {
+		return slices.ContainsFunc(a.db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.ActorId == actor.Id && item.FilmId == query.FilmId
+		})
+	}
+	return true
+}
+
+// actorAge полных лет на дату now
+func actorAge(birthday time.Time, now time.Time) int {
+	age := now.Year() - birthday.Year()
+	if now.Month() < birthday.Month() || (now.Month() == birthday.Month() && now.Day() < birthday.Day()) {
+		age--
+	}
+	return age
+}
+
+// sortActors без известного поля сортирует по id, как и postgres
+func sortActors(actors []*model.Actor, query domainQuery.ActorRepositoryQuery) {
+	slices.SortStableFunc(actors, actorOrder(query.SortField, query.OrderBy))
+}
+
+func actorOrder(sortField string, orderBy domainQuery.OrderDirection) func(a, b *model.Actor) int {
+	return func(a, b *model.Actor) int {
+		var result int
+		switch sortField {
+		case "name":
+			result = strings.Compare(a.Name, b.Name)
+		case "birthday":
+			result = a.Birthday.Compare(b.Birthday)
+		}
+		if result == 0 {
+			result = strings.Compare(a.Id, b.Id)
+		}
+		if orderBy == domainQuery.Desc {
+			return -result
+		}
+		return result
+	}
+}
+
+// cursorActor актер с ключом сортировки из курсора для сравнения через actorOrder
+func cursorActor(cursor *domainQuery.Cursor) *model.Actor {
+	actor := &model.Actor{Id: cursor.Id}
+	switch cursor.SortField {
+	case "name":
+		actor.Name = cursor.Value
+	case "birthday":
+		actor.Birthday, _ = cursor.Time()
+	}
+	return actor
 }
 
 func NewActorRepository() repository.ActorRepository {
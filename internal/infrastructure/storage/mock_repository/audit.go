@@ -0,0 +1,66 @@
+package mockRepository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type auditRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (a auditRepository) Create(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	created := *entry
+	created.CreatedAt = time.Now()
+	a.db.Audit = append(a.db.Audit, &created)
+	result := created
+	return &result, nil
+}
+
+func (a auditRepository) GetByQuery(ctx context.Context, query domainQuery.AuditRepositoryQuery) ([]*model.AuditEntry, int, error) {
+	filtered := make([]*model.AuditEntry, 0, len(a.db.Audit))
+	for _, item := range a.db.Audit {
+		if query.UserId != "" && item.UserId != query.UserId {
+			continue
+		}
+		if query.EntityType != "" && item.EntityType != query.EntityType {
+			continue
+		}
+		if query.EntityId != "" && item.EntityId != query.EntityId {
+			continue
+		}
+		if query.From != nil && item.CreatedAt.Before(*query.From) {
+			continue
+		}
+		if query.To != nil && item.CreatedAt.After(*query.To) {
+			continue
+		}
+		copied := *item
+		filtered = append(filtered, &copied)
+	}
+	// записи добавляются по времени, поэтому обратный порядок вставки - от новых к старым даже при равном времени
+	for i, j := 0, len(filtered)-1; i < j; i, j = i+1, j-1 {
+		filtered[i], filtered[j] = filtered[j], filtered[i]
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+
+	pageCount := len(filtered) / query.PageCount
+	if len(filtered)%query.PageCount != 0 {
+		pageCount++
+	}
+	start := min(query.PageCount*(query.CurrentPage-1), len(filtered))
+	end := min(start+query.PageCount, len(filtered))
+	return filtered[start:end], pageCount, nil
+}
+
+func NewAuditRepository() repository.AuditRepository {
+	return &auditRepository{db: inMemDb.New()}
+}
@@ -0,0 +1,117 @@
+package mockRepository
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+// checkCredits как транзакция postgres: сначала проверяются все фильмы и актеры, потом меняются связи.
+// Записи из корзины считаются отсутствующими
+func checkCredits(db *inMemDb.InMemDb, credits []*model.Credit) error {
+	for _, credit := range credits {
+		hasFilm := slices.ContainsFunc(liveFilms(db), func(item *model.Film) bool {
+			return item.Id == credit.FilmId
+		})
+		hasActor := slices.ContainsFunc(liveActors(db), func(item *model.Actor) bool {
+			return item.Id == credit.ActorId
+		})
+		if !hasFilm || !hasActor {
+			return sql.ErrNoRows
+		}
+	}
+	return nil
+}
+
+// insertCredits повторная связь с той же ролью пропускается как ON CONFLICT DO NOTHING
+func insertCredits(db *inMemDb.InMemDb, credits []*model.Credit) {
+	for _, credit := range credits {
+		linked := slices.ContainsFunc(db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.ActorId == credit.ActorId && item.FilmId == credit.FilmId && item.Role == credit.Role
+		})
+		if linked {
+			continue
+		}
+		db.ActorFilm = append(db.ActorFilm, &inMemDb.ActorFilm{
+			ActorId:      credit.ActorId,
+			FilmId:       credit.FilmId,
+			Role:         credit.Role,
+			Character:    credit.Character,
+			BillingOrder: credit.BillingOrder,
+		})
+	}
+}
+
+// changeCredits выполняет change и как postgres увеличивает версию фильмов и актеров, чьи титры изменились.
+// Записи skip только что созданы и версию не меняют
+func changeCredits(db *inMemDb.InMemDb, change func(), skip ...string) {
+	before := creditRows(db)
+	change()
+	after := creditRows(db)
+
+	changed := make(map[string]bool)
+	for key, rows := range after {
+		if before[key] != rows {
+			changed[key[0]], changed[key[1]] = true, true
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changed[key[0]], changed[key[1]] = true, true
+		}
+	}
+	for id := range changed {
+		if !slices.Contains(skip, id) {
+			bumpVersion(db, id)
+		}
+	}
+}
+
+// creditRows связи по паре фильм-актер
+func creditRows(db *inMemDb.InMemDb) map[[2]string]string {
+	rows := make(map[[2]string][]string)
+	for _, item := range db.ActorFilm {
+		character := ""
+		if item.Character != nil {
+			character = *item.Character
+		}
+		key := [2]string{item.FilmId, item.ActorId}
+		rows[key] = append(rows[key], fmt.Sprintf("%s|%s|%d", item.Role, character, item.BillingOrder))
+	}
+
+	result := make(map[[2]string]string, len(rows))
+	for key, items := range rows {
+		slices.Sort(items)
+		result[key] = strings.Join(items, ";")
+	}
+	return result
+}
+
+// bumpLinked записи из корзины не попадают в титры, поэтому удаление и восстановление меняет агрегаты связанных записей
+func bumpLinked(db *inMemDb.InMemDb, id string) {
+	for _, item := range db.ActorFilm {
+		switch id {
+		case item.FilmId:
+			bumpVersion(db, item.ActorId)
+		case item.ActorId:
+			bumpVersion(db, item.FilmId)
+		}
+	}
+}
+
+func bumpVersion(db *inMemDb.InMemDb, id string) {
+	for _, film := range db.Film {
+		if film.Id == id {
+			film.Version++
+		}
+	}
+	for _, actor := range db.Actor {
+		if actor.Id == id {
+			actor.Version++
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package mockRepository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type externalIdRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (e externalIdRepository) FilmIds(ctx context.Context, source string, externalIds ...string) (map[string]string, error) {
+	return externalEntityIds(e.db.FilmExternalId, e.liveFilm, source, externalIds), nil
+}
+
+func (e externalIdRepository) GetFilm(ctx context.Context, filmId string) (map[string]string, error) {
+	return entityExternalIds(e.db.FilmExternalId, filmId), nil
+}
+
+func (e externalIdRepository) SetFilm(ctx context.Context, filmId string, ids map[string]string) (err error) {
+	e.db.FilmExternalId, err = setExternalIds(e.db.FilmExternalId, e.liveFilm, filmId, ids)
+	return err
+}
+
+func (e externalIdRepository) ActorIds(ctx context.Context, source string, externalIds ...string) (map[string]string, error) {
+	return externalEntityIds(e.db.ActorExternalId, e.liveActor, source, externalIds), nil
+}
+
+func (e externalIdRepository) GetActor(ctx context.Context, actorId string) (map[string]string, error) {
+	return entityExternalIds(e.db.ActorExternalId, actorId), nil
+}
+
+func (e externalIdRepository) SetActor(ctx context.Context, actorId string, ids map[string]string) (err error) {
+	e.db.ActorExternalId, err = setExternalIds(e.db.ActorExternalId, e.liveActor, actorId, ids)
+	return err
+}
+
+func (e externalIdRepository) liveFilm(id string) bool {
+	return slices.ContainsFunc(liveFilms(e.db), func(film *model.Film) bool {
+		return film.Id == id
+	})
+}
+
+func (e externalIdRepository) liveActor(id string) bool {
+	return slices.ContainsFunc(liveActors(e.db), func(actor *model.Actor) bool {
+		return actor.Id == id
+	})
+}
+
+// externalEntityIds как в postgres записи из корзины идентификаторы не занимают
+func externalEntityIds(table []*inMemDb.ExternalId, live func(id string) bool, source string, externalIds []string) map[string]string {
+	result := make(map[string]string, len(externalIds))
+	for _, item := range table {
+		if item.Source == source && slices.Contains(externalIds, item.ExternalId) && live(item.EntityId) {
+			result[item.ExternalId] = item.EntityId
+		}
+	}
+	return result
+}
+
+func entityExternalIds(table []*inMemDb.ExternalId, id string) map[string]string {
+	result := make(map[string]string, 4)
+	for _, item := range table {
+		if item.EntityId == id {
+			result[item.Source] = item.ExternalId
+		}
+	}
+	return result
+}
+
+// setExternalIds как в postgres заменяются идентификаторы записи id и записей из корзины, идентификатор
+// живой записи - конфликт и таблица остается прежней, как при откате транзакции
+func setExternalIds(table []*inMemDb.ExternalId, live func(id string) bool, id string, ids map[string]string) ([]*inMemDb.ExternalId, error) {
+	result := slices.Clone(table)
+	for source, externalId := range ids {
+		result = slices.DeleteFunc(result, func(item *inMemDb.ExternalId) bool {
+			return item.Source == source && (item.EntityId == id || item.ExternalId == externalId && !live(item.EntityId))
+		})
+		if slices.ContainsFunc(result, func(item *inMemDb.ExternalId) bool {
+			return item.Source == source && item.ExternalId == externalId
+		}) {
+			return table, fmt.Errorf("%w: %s %s", repository.ErrExternalIdConflict, source, externalId)
+		}
+		result = append(result, &inMemDb.ExternalId{Source: source, ExternalId: externalId, EntityId: id})
+	}
+	return result, nil
+}
+
+func NewExternalIdRepository() repository.ExternalIdRepository {
+	return &externalIdRepository{db: inMemDb.New()}
+}
@@ -1,11 +1,13 @@
 package mockRepository
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
@@ -30,27 +32,51 @@ func (f filmRepository) Create(ctx context.Context, aggregate *aggregate.FilmAgg
 		return nil, errors.New("conflict fields")
 	}
 
-	film := model.Film{Id: aggregate.Film.Id, Name: aggregate.Film.Name, ReleaseDate: aggregate.Film.ReleaseDate, Rate: aggregate.Film.Rate, Description: aggregate.Film.Description}
+	film := model.Film{Id: aggregate.Film.Id, Name: aggregate.Film.Name, ReleaseDate: aggregate.Film.ReleaseDate, ManualRate: aggregate.Film.ManualRate, Description: aggregate.Film.Description, Version: 1}
+	f.db.RecomputeRate(&film)
+
+	// как откат транзакции: при неизвестном актере в титрах база остается прежней
+	films, actors := f.db.Film, f.db.Actor
 	f.db.Film = append(f.db.Film, &film)
+	for _, actor := range aggregate.Actors {
+		created := *actor
+		created.Version = 1
+		f.db.Actor = append(f.db.Actor, &created)
+	}
+	for _, credit := range aggregate.Credits {
+		credit.FilmId = film.Id
+	}
+	if err := checkCredits(f.db, aggregate.Credits); err != nil {
+		f.db.Film, f.db.Actor = films, actors
+		return nil, err
+	}
+	created := []string{film.Id}
+	for _, actor := range aggregate.Actors {
+		created = append(created, actor.Id)
+	}
+	changeCredits(f.db, func() {
+		insertCredits(f.db, aggregate.Credits)
+	}, created...)
+
+	aggregate.Film = film
 	return aggregate, nil
 }
 
 func (f filmRepository) Update(ctx context.Context, aggregate *aggregate.FilmAggregate) (*aggregate.FilmAggregate, error) {
-	has := slices.ContainsFunc(f.db.Film, func(item *model.Film) bool {
-		if aggregate.Film.Id == item.Id {
-			return true
-		}
-		return false
-	})
-
-	if !has {
+	if !f.hasFilm(aggregate.Film.Id) {
 		return nil, sql.ErrNoRows
 	}
 
 	for i, item := range f.db.Film {
 		if aggregate.Film.Id == item.Id {
+			if aggregate.Film.Version != 0 && aggregate.Film.Version != item.Version {
+				return nil, repository.ErrVersionConflict
+			}
 			film := aggregate.Film
+			film.Version, film.DeletedAt = item.Version+1, nil
+			f.db.RecomputeRate(&film)
 			f.db.Film[i] = &film
+			aggregate.Film = film
 		}
 	}
 
@@ -58,115 +84,455 @@ func (f filmRepository) Update(ctx context.Context, aggregate *aggregate.FilmAgg
 }
 
 func (f filmRepository) Delete(ctx context.Context, id string) error {
-	has := false
-
-	var filteredFilms []*model.Film
+	for _, film := range f.db.Film {
+		if film.Id == id && film.DeletedAt == nil {
+			now := time.Now()
+			film.DeletedAt = &now
+			bumpLinked(f.db, id)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
 
+func (f filmRepository) GetDeleted(ctx context.Context, query domainQuery.PageQuery) ([]*aggregate.FilmAggregate, int, error) {
+	deleted := make([]*model.Film, 0, 8)
 	for _, film := range f.db.Film {
-		if film.Id != id {
-			filteredFilms = append(filteredFilms, film)
-		} else {
-			has = true
+		if film.DeletedAt != nil {
+			deleted = append(deleted, film)
 		}
 	}
+	page, pageCount := trashPage(deleted, func(film *model.Film) int64 {
+		return film.DeletedAt.UnixNano()
+	}, query)
 
-	f.db.Film = filteredFilms
+	films := make([]*aggregate.FilmAggregate, 0, len(page))
+	for _, film := range page {
+		films = append(films, &aggregate.FilmAggregate{Film: *film})
+	}
+	return films, pageCount, nil
+}
 
-	if has {
-		return nil
+func (f filmRepository) Restore(ctx context.Context, id string) error {
+	for _, film := range f.db.Film {
+		if film.Id == id && film.DeletedAt != nil {
+			film.DeletedAt = nil
+			film.Version++
+			bumpLinked(f.db, id)
+			return nil
+		}
 	}
 	return sql.ErrNoRows
 }
 
+func (f filmRepository) Purge(ctx context.Context, id string) error {
+	purged := purgeFilms(f.db, func(film *model.Film) bool {
+		return film.Id == id
+	})
+	if purged == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (f filmRepository) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	return purgeFilms(f.db, func(film *model.Film) bool {
+		return film.DeletedAt.Before(before)
+	}), nil
+}
+
+func (f filmRepository) SetCast(ctx context.Context, filmId string, credits ...*model.Credit) error {
+	if !f.hasFilm(filmId) {
+		return sql.ErrNoRows
+	}
+	for _, credit := range credits {
+		credit.FilmId = filmId
+	}
+	if err := checkCredits(f.db, credits); err != nil {
+		return err
+	}
+
+	changeCredits(f.db, func() {
+		f.db.ActorFilm = slices.DeleteFunc(f.db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.FilmId == filmId
+		})
+		insertCredits(f.db, credits)
+	})
+	return nil
+}
+
+func (f filmRepository) RemoveActor(ctx context.Context, filmId string, actorIds ...string) error {
+	if !f.hasFilm(filmId) {
+		return sql.ErrNoRows
+	}
+
+	changeCredits(f.db, func() {
+		f.db.ActorFilm = slices.DeleteFunc(f.db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.FilmId == filmId && slices.Contains(actorIds, item.ActorId)
+		})
+	})
+	return nil
+}
+
+func (f filmRepository) hasFilm(id string) bool {
+	return slices.ContainsFunc(liveFilms(f.db), func(item *model.Film) bool {
+		return item.Id == id
+	})
+}
+
 func (f filmRepository) GetById(ctx context.Context, id string) (*aggregate.FilmAggregate, error) {
 	var searched *model.Film
-	for _, film := range f.db.Film {
+	for _, film := range liveFilms(f.db) {
 		if film.Id == id {
 			searched = film
 		}
 	}
 	if searched != nil {
-		return &aggregate.FilmAggregate{Film: *searched}, nil
+		result := &aggregate.FilmAggregate{Film: *searched, Actors: f.filmActors(id)}
+		result.SetCredits(f.credits(id))
+		return result, nil
 	}
 	return nil, sql.ErrNoRows
 }
 
-// TODO - Нету обработки на сортировку по полю
+func (f filmRepository) GetByNameAndRelease(ctx context.Context, name string, releaseDate time.Time) (*aggregate.FilmAggregate, error) {
+	for _, film := range liveFilms(f.db) {
+		if strings.EqualFold(film.Name, name) && sameDate(film.ReleaseDate, releaseDate) {
+			return f.GetById(ctx, film.Id)
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// sameDate сравнение как у колонок DATE, время суток не учитывается
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
 func (f filmRepository) GetByQuery(ctx context.Context, query domainQuery.FilmRepositoryQuery) ([]*aggregate.FilmAggregate, int, error) {
-	if len(f.db.Film) == 0 {
+	filtered := f.filtered(query)
+
+	if len(filtered) == 0 {
 		return []*aggregate.FilmAggregate{}, 0, nil
 	}
 
-	start := query.PageCount * (query.CurrentPage - 1)
-	if start >= len(f.db.Film) {
-		return nil, 0, errors.New("current page incorrect")
+	remainder := len(filtered) % query.PageCount
+	totalPageCount := len(filtered) / query.PageCount
+	if remainder != 0 {
+		totalPageCount++
 	}
 
-	getted := make([]*aggregate.FilmAggregate, 0, query.PageCount)
+	// с курсором выборка ограничивается соседними с ним фильмами, берется на один больше
+	start, fetch := query.PageCount*(query.CurrentPage-1), query.PageCount
+	if query.Cursor != nil {
+		filtered, start, fetch = keysetWindow(filtered, query.Cursor, query.PageCount+1, filmOrder(query.SortField, query.OrderBy), cursorFilm(query.Cursor)), 0, query.PageCount+1
+	}
+	// страница за пределами выборки пустая, как и в postgres
+	if start >= len(filtered) {
+		return []*aggregate.FilmAggregate{}, totalPageCount, nil
+	}
 
-	for i, j := 0, start; j < len(f.db.Film) && i < query.PageCount; i++ {
+	getted := make([]*aggregate.FilmAggregate, 0, fetch)
+
+	for i, j := 0, start; j < len(filtered) && i < fetch; i++ {
 		var actors []*model.Actor = nil
-		isActorConnection := slices.ContainsFunc(query.WithConnection, func(item string) bool {
-			if item == "actor" {
-				return true
-			}
-			return false
-		})
+		isActorConnection := slices.Contains(query.WithConnection, "actor")
 		if isActorConnection {
-			actorIds := make([]string, 0, 50)
-			for _, item := range f.db.ActorFilm {
-				if item.FilmId == f.db.Film[j].Id {
-					actorIds = append(actorIds, item.ActorId)
-				}
-			}
-
-			for _, actor := range f.db.Actor {
-				has := slices.ContainsFunc(actorIds, func(id string) bool {
-					if id == actor.Id {
-						return true
-					}
-					return false
-				})
-				if has {
-					cpy := *actor
-					actors = append(actors, &cpy)
-				}
-			}
+			actors = f.filmActors(filtered[j].Id)
+		}
+		var genres []*model.Genre = nil
+		if slices.Contains(query.WithConnection, "genre") {
+			genres = f.filmGenres(filtered[j].Id)
 		}
 		aggr := aggregate.FilmAggregate{
-			Film:   *f.db.Film[j],
+			Film:   *filtered[j],
 			Actors: actors,
+			Genres: genres,
+		}
+		if isActorConnection {
+			aggr.SetCredits(f.credits(filtered[j].Id))
 		}
 		getted = append(getted, &aggr)
 		j++
 	}
 
-	remainder := len(f.db.Film) % query.PageCount
-	totalPageCount := len(f.db.Film) / query.PageCount
-	if remainder != 0 {
-		totalPageCount++
+	return getted, totalPageCount, nil
+}
+
+func (f filmRepository) Each(ctx context.Context, query domainQuery.FilmRepositoryQuery, fn func(film *model.Film) error) error {
+	for _, film := range f.filtered(query) {
+		cpy := *film
+		if err := fn(&cpy); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return getted, totalPageCount, nil
+func (f filmRepository) EachCredit(ctx context.Context, query domainQuery.FilmRepositoryQuery, fn func(credit *model.CreditDetails) error) error {
+	for _, film := range f.filtered(query) {
+		credits := f.credits(film.Id)
+		slices.SortStableFunc(credits, func(a, b *model.Credit) int {
+			return cmp.Or(cmp.Compare(a.BillingOrder, b.BillingOrder), cmp.Compare(a.Role, b.Role))
+		})
+		for _, credit := range credits {
+			for _, actor := range liveActors(f.db) {
+				if actor.Id != credit.ActorId {
+					continue
+				}
+				details := &model.CreditDetails{Credit: *credit, FilmName: film.Name, FilmRelease: film.ReleaseDate, ActorName: actor.Name, ActorBirthday: actor.Birthday}
+				if err := fn(details); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
 }
 
-func (f filmRepository) SearchByNameAndActorName(ctx context.Context, searchValue string) ([]*aggregate.FilmAggregate, int, error) {
+// filtered фильмы каталога под фильтр query в его сортировке
+func (f filmRepository) filtered(query domainQuery.FilmRepositoryQuery) []*model.Film {
+	filtered := make([]*model.Film, 0, len(f.db.Film))
+	for _, film := range liveFilms(f.db) {
+		if f.matchFilter(film, query.FilmFilter) {
+			filtered = append(filtered, film)
+		}
+	}
+	sortFilms(filtered, query)
+	return filtered
+}
+
+// filmActors копии актеров фильма, каждый один раз даже при нескольких ролях
+func (f filmRepository) filmActors(filmId string) []*model.Actor {
+	var actors []*model.Actor = nil
+	for _, actor := range liveActors(f.db) {
+		linked := slices.ContainsFunc(f.db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.FilmId == filmId && item.ActorId == actor.Id
+		})
+		if linked {
+			cpy := *actor
+			actors = append(actors, &cpy)
+		}
+	}
+	return actors
+}
+
+// credits титры фильма из in memory связей
+func (f filmRepository) credits(filmId string) []*model.Credit {
+	var credits []*model.Credit = nil
+	for _, item := range liveLinks(f.db) {
+		if item.FilmId == filmId {
+			credits = append(credits, item.ToCredit())
+		}
+	}
+	return credits
+}
+
+// matchFilter все заданные условия FilmFilter, как в postgres репозитории
+func (f filmRepository) matchFilter(film *model.Film, filter domainQuery.FilmFilter) bool {
+	if filter.ReleaseDateFrom != nil && film.ReleaseDate.Before(*filter.ReleaseDateFrom) {
+		return false
+	}
+	if filter.ReleaseDateTo != nil && film.ReleaseDate.After(*filter.ReleaseDateTo) {
+		return false
+	}
+	if (filter.RateFrom != nil && film.Rate < *filter.RateFrom) || (filter.RateTo != nil && film.Rate > *filter.RateTo) {
+		return false
+	}
+	genresMatched := matchLinks(filter.Genres, filter.GenreMatch, func(genreId string) bool {
+		return slices.ContainsFunc(f.db.FilmGenre, func(item *inMemDb.FilmGenre) bool {
+			return item.FilmId == film.Id && item.GenreId == genreId
+		})
+	})
+	actorsMatched := matchLinks(filter.Actors, filter.ActorMatch, func(actorId string) bool {
+		return slices.ContainsFunc(f.db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+			return item.FilmId == film.Id && item.ActorId == actorId
+		})
+	})
+	return genresMatched && actorsMatched
+}
+
+// matchLinks проверяет связи с ids: хотя бы одна для MatchAny, все для MatchAll
+func matchLinks(ids []string, mode domainQuery.MatchMode, linked func(id string) bool) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	matched := 0
+	for _, id := range ids {
+		if linked(id) {
+			matched++
+		}
+	}
+	if mode == domainQuery.MatchAll {
+		return matched == len(ids)
+	}
+	return matched > 0
+}
+
+// sortFilms неизвестное поле сортирует по rate, при равенстве порядок по id в том же направлении
+func sortFilms(films []*model.Film, query domainQuery.FilmRepositoryQuery) {
+	slices.SortStableFunc(films, filmOrder(query.SortField, query.OrderBy))
+}
+
+func filmOrder(sortField string, orderBy domainQuery.OrderDirection) func(a, b *model.Film) int {
+	return func(a, b *model.Film) int {
+		var result int
+		switch sortField {
+		case "name":
+			result = strings.Compare(a.Name, b.Name)
+		case "release_date":
+			result = a.ReleaseDate.Compare(b.ReleaseDate)
+		default:
+			result = cmp.Compare(a.Rate, b.Rate)
+		}
+		if result == 0 {
+			result = strings.Compare(a.Id, b.Id)
+		}
+		if orderBy == domainQuery.Desc {
+			return -result
+		}
+		return result
+	}
+}
+
+// cursorFilm фильм с ключом сортировки из курсора для сравнения через filmOrder
+func cursorFilm(cursor *domainQuery.Cursor) *model.Film {
+	film := &model.Film{Id: cursor.Id}
+	switch cursor.SortField {
+	case "name":
+		film.Name = cursor.Value
+	case "release_date":
+		film.ReleaseDate, _ = cursor.Time()
+	default:
+		film.Rate, _ = cursor.Float()
+	}
+	return film
+}
+
+// keysetWindow до limit элементов отсортированной выборки сразу после курсора либо прямо перед ним
+func keysetWindow[T any](sorted []T, cursor *domainQuery.Cursor, limit int, order func(a, b T) int, key T) []T {
+	if cursor.Before {
+		end := 0
+		for end < len(sorted) && order(sorted[end], key) < 0 {
+			end++
+		}
+		return sorted[max(0, end-limit):end]
+	}
+	start := 0
+	for start < len(sorted) && order(sorted[start], key) <= 0 {
+		start++
+	}
+	return sorted[start:min(len(sorted), start+limit)]
+}
+
+func (f filmRepository) filmGenres(filmId string) []*model.Genre {
+	var genres []*model.Genre = nil
+	for _, genre := range f.db.Genre {
+		linked := slices.ContainsFunc(f.db.FilmGenre, func(item *inMemDb.FilmGenre) bool {
+			return item.FilmId == filmId && item.GenreId == genre.Id
+		})
+		if linked {
+			cpy := *genre
+			genres = append(genres, &cpy)
+		}
+	}
+	return genres
+}
+
+// SearchByNameAndActorName упрощенный аналог полнотекстового поиска: регистронезависимое вхождение
+// в название (вес 1), имя актера (0.4) или описание (0.2). Опечатки в названии и именах находятся по триграммам
+func (f filmRepository) SearchByNameAndActorName(ctx context.Context, query domainQuery.FilmSearchQuery) ([]*aggregate.FilmAggregate, int, error) {
+	value := strings.ToLower(query.Value)
 	foundItems := make([]*aggregate.FilmAggregate, 0, 100)
 
-	for _, film := range f.db.Film {
-		if strings.Contains(film.Name, searchValue) {
-			aggr := aggregate.FilmAggregate{Film: *film}
-			foundItems = append(foundItems, &aggr)
+	for _, film := range liveFilms(f.db) {
+		match := &model.FilmSearchMatch{Name: highlight(film.Name, value)}
+		if strings.Contains(strings.ToLower(film.Name), value) {
+			match.TitleMatch = true
+			match.Rank += 1
+		} else if sml := wordSimilarity(value, film.Name); sml >= similarityThreshold {
+			match.TitleMatch = true
+			match.Rank += sml
+		}
+		if slices.ContainsFunc(f.filmActorNames(film.Id), func(name string) bool {
+			return strings.Contains(strings.ToLower(name), value) || wordSimilarity(value, name) >= similarityThreshold
+		}) {
+			match.Rank += 0.4
+		}
+		if film.Description != nil && strings.Contains(strings.ToLower(*film.Description), value) {
+			description := highlight(*film.Description, value)
+			match.Description = &description
+			match.Rank += 0.2
+		}
+		if match.Rank == 0 {
+			continue
 		}
+		foundItems = append(foundItems, &aggregate.FilmAggregate{Film: *film, Search: match})
 	}
 
-	remainder := len(f.db.Film) % 15
-	totalPageCount := len(f.db.Film) / 15
-	if remainder != 0 {
-		totalPageCount++
+	slices.SortStableFunc(foundItems, func(a, b *aggregate.FilmAggregate) int {
+		if a.Search.TitleMatch != b.Search.TitleMatch {
+			if a.Search.TitleMatch {
+				return -1
+			}
+			return 1
+		}
+		if a.Search.Rank != b.Search.Rank {
+			if a.Search.Rank > b.Search.Rank {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Film.Name, b.Film.Name)
+	})
+
+	total := len(foundItems)
+	start := query.PageCount * (query.CurrentPage - 1)
+	if start >= total {
+		return []*aggregate.FilmAggregate{}, total, nil
+	}
+	end := min(start+query.PageCount, total)
+
+	return foundItems[start:end], total, nil
+}
+
+func (f filmRepository) filmActorNames(filmId string) []string {
+	var names []string = nil
+	for _, item := range f.db.ActorFilm {
+		if item.FilmId != filmId {
+			continue
+		}
+		for _, actor := range liveActors(f.db) {
+			if actor.Id == item.ActorId {
+				names = append(names, actor.Name)
+			}
+		}
 	}
+	return names
+}
 
-	return foundItems, totalPageCount, nil
+// highlight оборачивает все вхождения value (в нижнем регистре) тегом <b>, как ts_headline
+func highlight(text string, value string) string {
+	lower := strings.ToLower(text)
+	if value == "" || len(lower) != len(text) {
+		return text
+	}
+	var builder strings.Builder
+	for {
+		index := strings.Index(lower, value)
+		if index < 0 {
+			builder.WriteString(text)
+			return builder.String()
+		}
+		builder.WriteString(text[:index])
+		builder.WriteString("<b>")
+		builder.WriteString(text[index : index+len(value)])
+		builder.WriteString("</b>")
+		text = text[index+len(value):]
+		lower = lower[index+len(value):]
+	}
 }
 
 func NewFilmRepository() repository.FilmRepository {
@@ -0,0 +1,143 @@
+package mockRepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type genreRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (g genreRepository) Create(ctx context.Context, data *model.Genre) (*model.Genre, error) {
+	if slices.ContainsFunc(g.db.Genre, func(item *model.Genre) bool {
+		return item.Name == data.Name
+	}) {
+		return nil, repository.ErrGenreNameExist
+	}
+	has := slices.ContainsFunc(g.db.Genre, func(item *model.Genre) bool {
+		return item.Id == data.Id
+	})
+
+	if has {
+		return nil, errors.New("conflict fields")
+	}
+
+	genre := model.Genre{Id: data.Id, Name: data.Name}
+	g.db.Genre = append(g.db.Genre, &genre)
+	return data, nil
+}
+
+func (g genreRepository) Update(ctx context.Context, data *model.Genre) (*model.Genre, error) {
+	if slices.ContainsFunc(g.db.Genre, func(item *model.Genre) bool {
+		return item.Name == data.Name && item.Id != data.Id
+	}) {
+		return nil, repository.ErrGenreNameExist
+	}
+	for i, item := range g.db.Genre {
+		if item.Id == data.Id {
+			genre := *data
+			g.db.Genre[i] = &genre
+			return data, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (g genreRepository) Delete(ctx context.Context, id string) error {
+	has := false
+
+	var filteredGenres []*model.Genre
+	for _, genre := range g.db.Genre {
+		if genre.Id != id {
+			filteredGenres = append(filteredGenres, genre)
+		} else {
+			has = true
+		}
+	}
+
+	if !has {
+		return sql.ErrNoRows
+	}
+
+	g.db.Genre = filteredGenres
+	g.db.FilmGenre = slices.DeleteFunc(g.db.FilmGenre, func(item *inMemDb.FilmGenre) bool {
+		return item.GenreId == id
+	})
+	return nil
+}
+
+func (g genreRepository) GetById(ctx context.Context, id string) (*model.Genre, error) {
+	for _, genre := range g.db.Genre {
+		if genre.Id == id {
+			cpy := *genre
+			return &cpy, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (g genreRepository) GetAll(ctx context.Context) ([]*model.Genre, error) {
+	genres := make([]*model.Genre, 0, len(g.db.Genre))
+	for _, genre := range g.db.Genre {
+		cpy := *genre
+		genres = append(genres, &cpy)
+	}
+	sort.Slice(genres, func(i, j int) bool {
+		return genres[i].Name < genres[j].Name
+	})
+	return genres, nil
+}
+
+func (g genreRepository) HasByName(ctx context.Context, name string) (bool, error) {
+	return slices.ContainsFunc(g.db.Genre, func(item *model.Genre) bool {
+		return item.Name == name
+	}), nil
+}
+
+func (g genreRepository) AddFilm(ctx context.Context, genreId string, filmIds ...string) error {
+	hasGenre := slices.ContainsFunc(g.db.Genre, func(item *model.Genre) bool {
+		return item.Id == genreId
+	})
+	if !hasGenre {
+		return sql.ErrNoRows
+	}
+
+	added := make([]*inMemDb.FilmGenre, 0, len(filmIds))
+	for _, id := range filmIds {
+		hasFilm := slices.ContainsFunc(liveFilms(g.db), func(item *model.Film) bool {
+			return item.Id == id
+		})
+		if !hasFilm {
+			return sql.ErrNoRows
+		}
+
+		linked := slices.ContainsFunc(g.db.FilmGenre, func(item *inMemDb.FilmGenre) bool {
+			return item.FilmId == id && item.GenreId == genreId
+		})
+		if !linked {
+			added = append(added, &inMemDb.FilmGenre{FilmId: id, GenreId: genreId})
+		}
+	}
+
+	g.db.FilmGenre = append(g.db.FilmGenre, added...)
+	return nil
+}
+
+func (g genreRepository) RemoveFilm(ctx context.Context, genreId string, filmIds ...string) error {
+	g.db.FilmGenre = slices.DeleteFunc(g.db.FilmGenre, func(item *inMemDb.FilmGenre) bool {
+		return item.GenreId == genreId && slices.Contains(filmIds, item.FilmId)
+	})
+	return nil
+}
+
+func NewGenreRepository() repository.GenreRepository {
+	return &genreRepository{inMemDb.New()}
+}
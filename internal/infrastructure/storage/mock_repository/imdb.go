@@ -0,0 +1,39 @@
+package mockRepository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type imdbRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (i imdbRepository) GetProgress(ctx context.Context) (*model.ImdbProgress, error) {
+	if len(i.db.ImdbProgress) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	progress := *i.db.ImdbProgress[0]
+	return &progress, nil
+}
+
+func (i imdbRepository) SaveProgress(ctx context.Context, progress *model.ImdbProgress) error {
+	saved := *progress
+	saved.UpdatedAt = time.Now()
+	i.db.ImdbProgress = []*model.ImdbProgress{&saved}
+	return nil
+}
+
+func (i imdbRepository) DeleteProgress(ctx context.Context) error {
+	i.db.ImdbProgress = []*model.ImdbProgress{}
+	return nil
+}
+
+func NewImdbRepository() repository.ImdbRepository {
+	return &imdbRepository{db: inMemDb.New()}
+}
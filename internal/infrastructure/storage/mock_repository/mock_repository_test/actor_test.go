@@ -2,11 +2,13 @@ package mock_repository_test
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
 	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
@@ -54,6 +56,18 @@ func TestActorRepository(t *testing.T) {
 	if updatedActor == nil || updatedActor.Actor.Name != "updated_actor" {
 		t.Errorf("Некорректно обновленный актер")
 	}
+	if updatedActor.Actor.Version != 2 {
+		t.Errorf("Версия должна расти при обновлении, получено %d", updatedActor.Actor.Version)
+	}
+
+	actorToUpdate.Name, actorToUpdate.Version = "stale_actor", 1
+	if _, err = repo.Update(context.Background(), &aggregate.ActorAggregate{Actor: *actorToUpdate}); err != repository.ErrVersionConflict {
+		t.Errorf("Ожидался конфликт версий, получено %v", err)
+	}
+	actorToUpdate.Version = 2
+	if _, err = repo.Update(context.Background(), &aggregate.ActorAggregate{Actor: *actorToUpdate}); err != nil {
+		t.Errorf("Ошибка при обновлении актера текущей версии: %v", err)
+	}
 
 	err = repo.Delete(context.Background(), "1")
 	if err != nil {
@@ -70,7 +84,7 @@ func TestActorRepository(t *testing.T) {
 
 	db.Film = append(db.Film, film)
 	db.Actor = append(db.Actor, actorToAddFilm)
-	err = repo.AddFilm(context.Background(), "1", "1")
+	err = repo.AddFilm(context.Background(), "1", &model.Credit{FilmId: "1", Role: "actor"})
 	if err != nil {
 		t.Errorf("Ошибка при добавлении фильма актеру: %v", err)
 	}
@@ -89,4 +103,99 @@ func TestActorRepository(t *testing.T) {
 	if len(actors) != 1 || actors[0].Actor.Id != "1" {
 		t.Errorf("Некорректный результат запроса")
 	}
+
+	// Тест фильтров и сортировки GetByQuery
+	now := time.Now()
+	db.Actor = append(db.Actor,
+		&model.Actor{Id: "2", Name: "Леонардо ДиКаприо", Gender: "male", Birthday: now.AddDate(-49, 0, -1)},
+		&model.Actor{Id: "3", Name: "Кейт Уинслет", Gender: "female", Birthday: now.AddDate(-48, 0, -1)},
+		&model.Actor{Id: "4", Name: "Билли Зейн", Gender: "male", Birthday: now.AddDate(-57, 0, -1)},
+	)
+	db.ActorFilm = append(db.ActorFilm, &inMemDb.ActorFilm{ActorId: "3", FilmId: "1", Role: "actor"})
+
+	nameQuery := *domainQuery.NewActorRepositoryQuery()
+	nameQuery.Name = "Ди Каприо"
+	actors, _, err = repo.GetByQuery(context.Background(), nameQuery)
+	if err != nil || len(actors) != 1 || actors[0].Actor.Id != "2" {
+		t.Errorf("Некорректный поиск актера по имени")
+	}
+
+	nameQuery.Name = "%"
+	actors, _, err = repo.GetByQuery(context.Background(), nameQuery)
+	if err != nil || len(actors) != 0 {
+		t.Errorf("Символы шаблона в имени должны искаться буквально")
+	}
+
+	ageFrom, ageTo := 48, 50
+	filterQuery := *domainQuery.NewActorRepositoryQuery()
+	filterQuery.Gender = "male"
+	filterQuery.AgeFrom, filterQuery.AgeTo = &ageFrom, &ageTo
+	actors, _, err = repo.GetByQuery(context.Background(), filterQuery)
+	if err != nil || len(actors) != 1 || actors[0].Actor.Id != "2" {
+		t.Errorf("Некорректный фильтр по полу и возрасту")
+	}
+
+	yearFrom := now.Year() - 50
+	filmQuery := *domainQuery.NewActorRepositoryQuery()
+	filmQuery.FilmId = "1"
+	filmQuery.BirthYearFrom = &yearFrom
+	actors, _, err = repo.GetByQuery(context.Background(), filmQuery)
+	if err != nil || len(actors) != 1 || actors[0].Actor.Id != "3" {
+		t.Errorf("Некорректный фильтр по фильму и году рождения")
+	}
+
+	sortQuery := *domainQuery.NewActorRepositoryQuery()
+	sortQuery.SortField, sortQuery.OrderBy = "birthday", domainQuery.Desc
+	actors, _, err = repo.GetByQuery(context.Background(), sortQuery)
+	if err != nil || len(actors) < 3 || actors[0].Actor.Id != "3" {
+		t.Errorf("Некорректная сортировка по дате рождения")
+	}
+
+	// Страница за пределами выборки пустая, кол-во страниц считается по отфильтрованным актерам
+	pageQuery := *domainQuery.NewActorRepositoryQuery()
+	pageQuery.Gender, pageQuery.PageCount, pageQuery.CurrentPage = "male", 1, 10
+	actors, pageCount, err := repo.GetByQuery(context.Background(), pageQuery)
+	if err != nil || len(actors) != 0 || pageCount != 2 {
+		t.Errorf("Некорректная пагинация актеров")
+	}
+
+	pageQuery.CurrentPage, pageQuery.WithConnection = 1, []string{"film"}
+	pageQuery.FilmId = "1"
+	pageQuery.Gender = ""
+	actors, pageCount, err = repo.GetByQuery(context.Background(), pageQuery)
+	if err != nil || len(actors) != 1 || pageCount != 2 || len(actors[0].Films) == 0 || len(actors[0].Credits) == 0 {
+		t.Errorf("Некорректная загрузка фильмов актера")
+	}
+
+	// Повторная связь пропускается, замена и удаление связей
+	links := func(actorId string) int {
+		count := 0
+		for _, item := range db.ActorFilm {
+			if item.ActorId == actorId {
+				count++
+			}
+		}
+		return count
+	}
+	err = repo.AddFilm(context.Background(), "1", &model.Credit{FilmId: "1", Role: "actor"})
+	if err != nil || links("1") != 1 {
+		t.Errorf("Повторная связь актера и фильма должна пропускаться")
+	}
+
+	err = repo.SetFilms(context.Background(), "1", &model.Credit{FilmId: "1", Role: "director"}, &model.Credit{FilmId: "unknown", Role: "actor"})
+	if err != sql.ErrNoRows || links("1") != 1 {
+		t.Errorf("Замена с неизвестным фильмом не должна менять связи")
+	}
+	err = repo.SetFilms(context.Background(), "1", &model.Credit{FilmId: "1", Role: "director"}, &model.Credit{FilmId: "1", Role: "writer"})
+	if err != nil || links("1") != 2 {
+		t.Errorf("Некорректная замена фильмов актера")
+	}
+
+	err = repo.RemoveFilm(context.Background(), "1", "1")
+	if err != nil || links("1") != 0 || links("3") != 1 {
+		t.Errorf("Некорректное удаление фильма у актера")
+	}
+	if err = repo.RemoveFilm(context.Background(), "unknown", "1"); err != sql.ErrNoRows {
+		t.Errorf("Удаление у неизвестного актера должно возвращать sql.ErrNoRows")
+	}
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
 	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
 )
@@ -84,13 +85,24 @@ func TestFilmRepository(t *testing.T) {
 	db.Film = append(db.Film, film1, film2, film3)
 
 	searchValue := "superpuper"
-	foundFilms, _, err := repo.SearchByNameAndActorName(context.Background(), searchValue)
+	foundFilms, total, err := repo.SearchByNameAndActorName(context.Background(), *domainQuery.NewFilmSearchQuery(searchValue))
 	if err != nil {
 		t.Errorf("Ошибка при поиске фильмов: %v", err)
 	}
-	if len(foundFilms) != 3 {
+	if len(foundFilms) != 3 || total != 3 {
 		t.Errorf("Некорректное количество найденных фильмов")
 	}
+	if foundFilms[0].Film.Name != "superpuper1" || foundFilms[0].Search.Name != "<b>superpuper</b>1" {
+		t.Errorf("Некорректная сортировка или подсветка найденных фильмов")
+	}
+
+	// Проверяем пагинацию и регистронезависимый поиск
+	searchQuery := domainQuery.NewFilmSearchQuery("SUPERPUPER")
+	searchQuery.CurrentPage, searchQuery.PageCount = 2, 2
+	pagedFilms, total, err := repo.SearchByNameAndActorName(context.Background(), *searchQuery)
+	if err != nil || total != 3 || len(pagedFilms) != 1 || pagedFilms[0].Film.Name != "superpuper3" {
+		t.Errorf("Некорректная пагинация найденных фильмов")
+	}
 
 	// Проверяем, что все найденные фильмы содержат искомое значение в имени
 	for _, f := range foundFilms {
@@ -98,4 +110,32 @@ func TestFilmRepository(t *testing.T) {
 			t.Errorf("Найденный фильм не содержит искомое значение в имени")
 		}
 	}
+
+	// Тест комбинированного фильтра GetByQuery: годы 1990-2000, рейтинг от 7, с актером
+	date := func(year int) time.Time {
+		return time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC)
+	}
+	db.Film = append(db.Film,
+		&model.Film{Id: "filter1", Name: "filter1", ReleaseDate: date(1994), Rate: 9},
+		&model.Film{Id: "filter2", Name: "filter2", ReleaseDate: date(1997), Rate: 6},
+		&model.Film{Id: "filter3", Name: "filter3", ReleaseDate: date(2005), Rate: 8},
+		&model.Film{Id: "filter4", Name: "filter4", ReleaseDate: date(1999), Rate: 7.5},
+	)
+	for _, filmId := range []string{"filter1", "filter2", "filter3"} {
+		db.ActorFilm = append(db.ActorFilm, &inMemDb.ActorFilm{ActorId: "filter-actor", FilmId: filmId, Role: "actor"})
+	}
+
+	from, to, rateFrom := date(1990), date(2000), float32(7)
+	filterQuery := *domainQuery.NewFilmRepositoryQuery()
+	filterQuery.ReleaseDateFrom, filterQuery.ReleaseDateTo, filterQuery.RateFrom = &from, &to, &rateFrom
+	filtered, _, err := repo.GetByQuery(context.Background(), filterQuery)
+	if err != nil || len(filtered) != 2 || filtered[0].Film.Id != "filter4" || filtered[1].Film.Id != "filter1" {
+		t.Errorf("Некорректный фильтр по дате выхода и рейтингу")
+	}
+
+	filterQuery.Actors = []string{"filter-actor"}
+	filtered, _, err = repo.GetByQuery(context.Background(), filterQuery)
+	if err != nil || len(filtered) != 1 || filtered[0].Film.Id != "filter1" {
+		t.Errorf("Некорректный фильтр по актеру")
+	}
 }
@@ -0,0 +1,76 @@
+package mock_repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenreRepository(t *testing.T) {
+	repo := mockRepository.NewGenreRepository()
+	filmRepo := mockRepository.NewFilmRepository()
+	db := inMemDb.New()
+	db.CleanUp()
+
+	_, err := repo.Create(context.Background(), &model.Genre{Id: "g1", Name: "drama"})
+	assert.Nil(t, err)
+	_, err = repo.Create(context.Background(), &model.Genre{Id: "g2", Name: "comedy"})
+	assert.Nil(t, err)
+	_, err = repo.Create(context.Background(), &model.Genre{Id: "g3", Name: "drama"})
+	assert.NotNil(t, err)
+
+	has, _ := repo.HasByName(context.Background(), "comedy")
+	assert.True(t, has)
+
+	all, err := repo.GetAll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(all))
+	assert.Equal(t, "comedy", all[0].Name)
+
+	db.Film = append(db.Film, &model.Film{Id: "f1", Name: "first"}, &model.Film{Id: "f2", Name: "second"})
+
+	err = repo.AddFilm(context.Background(), "g1", "f1", "f2")
+	assert.Nil(t, err)
+	err = repo.AddFilm(context.Background(), "g1", "f1")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(db.FilmGenre))
+	err = repo.AddFilm(context.Background(), "g2", "f1")
+	assert.Nil(t, err)
+	err = repo.AddFilm(context.Background(), "g2", "incorrect")
+	assert.NotNil(t, err)
+	err = repo.AddFilm(context.Background(), "incorrect", "f1")
+	assert.NotNil(t, err)
+
+	query := *domainQuery.NewFilmRepositoryQuery()
+	query.Genres = []string{"g1", "g2"}
+	query.WithConnection = []string{"genre"}
+	films, pageCount, err := filmRepo.GetByQuery(context.Background(), query)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(films))
+	assert.Equal(t, 1, pageCount)
+
+	query.GenreMatch = domainQuery.MatchAll
+	films, _, err = filmRepo.GetByQuery(context.Background(), query)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(films))
+	assert.Equal(t, "f1", films[0].Film.Id)
+	assert.Equal(t, 2, len(films[0].Genres))
+
+	err = repo.RemoveFilm(context.Background(), "g2", "f1")
+	assert.Nil(t, err)
+	films, _, _ = filmRepo.GetByQuery(context.Background(), query)
+	assert.Equal(t, 0, len(films))
+
+	err = repo.Delete(context.Background(), "g1")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(db.FilmGenre))
+	err = repo.Delete(context.Background(), "g1")
+	assert.NotNil(t, err)
+
+	db.CleanUp()
+}
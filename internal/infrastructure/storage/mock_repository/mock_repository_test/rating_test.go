@@ -0,0 +1,63 @@
+package mock_repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatingRepository(t *testing.T) {
+	repo := mockRepository.NewRatingRepository()
+	db := inMemDb.New()
+	db.CleanUp()
+
+	manual := float32(9)
+	db.Film = append(db.Film, &model.Film{Id: "f1", Name: "first"}, &model.Film{Id: "f2", Name: "second", Rate: 9, ManualRate: &manual})
+
+	film, err := repo.Create(context.Background(), &model.Rating{UserId: "u1", FilmId: "f1", Score: 8})
+	assert.Nil(t, err)
+	assert.Equal(t, float32(8), film.Rate)
+	assert.Equal(t, 1, film.VoteCount)
+
+	film, err = repo.Create(context.Background(), &model.Rating{UserId: "u2", FilmId: "f1", Score: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, float32(6.5), film.Rate)
+	assert.Equal(t, 2, film.VoteCount)
+
+	_, err = repo.Create(context.Background(), &model.Rating{UserId: "u2", FilmId: "f1", Score: 1})
+	assert.NotNil(t, err)
+	_, err = repo.Create(context.Background(), &model.Rating{UserId: "u1", FilmId: "incorrect", Score: 1})
+	assert.NotNil(t, err)
+
+	film, err = repo.Update(context.Background(), &model.Rating{UserId: "u2", FilmId: "f1", Score: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, float32(9), film.Rate)
+	_, err = repo.Update(context.Background(), &model.Rating{UserId: "u3", FilmId: "f1", Score: 10})
+	assert.NotNil(t, err)
+
+	rating, err := repo.Get(context.Background(), "u2", "f1")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, rating.Score)
+
+	film, err = repo.Create(context.Background(), &model.Rating{UserId: "u1", FilmId: "f2", Score: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, float32(9), film.Rate)
+	assert.Equal(t, 1, film.VoteCount)
+
+	film, err = repo.Delete(context.Background(), "u1", "f1")
+	assert.Nil(t, err)
+	assert.Equal(t, float32(10), film.Rate)
+	assert.Equal(t, 1, film.VoteCount)
+	film, err = repo.Delete(context.Background(), "u2", "f1")
+	assert.Nil(t, err)
+	assert.Equal(t, float32(0), film.Rate)
+	assert.Equal(t, 0, film.VoteCount)
+	_, err = repo.Delete(context.Background(), "u2", "f1")
+	assert.NotNil(t, err)
+
+	db.CleanUp()
+}
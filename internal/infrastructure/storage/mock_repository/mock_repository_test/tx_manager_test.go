@@ -0,0 +1,56 @@
+package mock_repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
+)
+
+func TestTxManager(t *testing.T) {
+	db := inMemDb.New()
+	txManager := mockRepository.NewTxManager()
+	filmRepo := mockRepository.NewFilmRepository()
+	tokenRepo := mockRepository.NewTokenRepository()
+	films, tokens := len(db.Film), len(db.Tokens)
+
+	film := &aggregate.FilmAggregate{Film: model.Film{Id: "tx-film", Name: "Tx film", ReleaseDate: time.Now()}}
+	errRollback := errors.New("rollback")
+	err := txManager.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := filmRepo.Create(ctx, film); err != nil {
+			return err
+		}
+		// вложенный Do присоединяется к внешней транзакции и откатывается вместе с ней
+		return txManager.Do(ctx, func(ctx context.Context) error {
+			if _, err := tokenRepo.Create(ctx, &model.Token{Id: "tx-token", Value: "tx"}); err != nil {
+				return err
+			}
+			return errRollback
+		})
+	})
+	if !errors.Is(err, errRollback) {
+		t.Errorf("Do должен вернуть ошибку fn: %v", err)
+	}
+	if len(db.Film) != films || len(db.Tokens) != tokens {
+		t.Errorf("Изменения должны откатиться при ошибке")
+	}
+
+	err = txManager.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := filmRepo.Create(ctx, film); err != nil {
+			return err
+		}
+		_, err := tokenRepo.Create(ctx, &model.Token{Id: "tx-token", Value: "tx"})
+		return err
+	})
+	if err != nil || len(db.Film) != films+1 || len(db.Tokens) != tokens+1 {
+		t.Errorf("Изменения должны сохраниться без ошибки: %v", err)
+	}
+
+	_ = filmRepo.Delete(context.Background(), "tx-film")
+	_ = tokenRepo.Delete(context.Background(), "tx-token")
+}
@@ -0,0 +1,103 @@
+package mockRepository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type ratingRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (r ratingRepository) Create(ctx context.Context, data *model.Rating) (*model.Film, error) {
+	film := r.film(data.FilmId)
+	if film == nil {
+		return nil, sql.ErrNoRows
+	}
+	has := slices.ContainsFunc(r.db.Rating, func(item *model.Rating) bool {
+		return item.UserId == data.UserId && item.FilmId == data.FilmId
+	})
+	if has {
+		return nil, repository.ErrRatingExist
+	}
+
+	now := time.Now()
+	data.CreatedAt, data.UpdatedAt = now, now
+	rating := *data
+	r.db.Rating = append(r.db.Rating, &rating)
+
+	r.recompute(film)
+	result := *film
+	return &result, nil
+}
+
+func (r ratingRepository) Update(ctx context.Context, data *model.Rating) (*model.Film, error) {
+	film := r.film(data.FilmId)
+	if film == nil {
+		return nil, sql.ErrNoRows
+	}
+	for _, item := range r.db.Rating {
+		if item.UserId == data.UserId && item.FilmId == data.FilmId {
+			item.Score = data.Score
+			item.UpdatedAt = time.Now()
+			data.CreatedAt, data.UpdatedAt = item.CreatedAt, item.UpdatedAt
+
+			r.recompute(film)
+			result := *film
+			return &result, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r ratingRepository) Delete(ctx context.Context, userId string, filmId string) (*model.Film, error) {
+	film := r.film(filmId)
+	if film == nil {
+		return nil, sql.ErrNoRows
+	}
+	count := len(r.db.Rating)
+	r.db.Rating = slices.DeleteFunc(r.db.Rating, func(item *model.Rating) bool {
+		return item.UserId == userId && item.FilmId == filmId
+	})
+	if count == len(r.db.Rating) {
+		return nil, sql.ErrNoRows
+	}
+
+	r.recompute(film)
+	result := *film
+	return &result, nil
+}
+
+func (r ratingRepository) Get(ctx context.Context, userId string, filmId string) (*model.Rating, error) {
+	for _, item := range r.db.Rating {
+		if item.UserId == userId && item.FilmId == filmId {
+			rating := *item
+			return &rating, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// recompute как UPDATE в postgres: версия фильма от оценок не растет
+func (r ratingRepository) recompute(film *model.Film) {
+	r.db.RecomputeRate(film)
+}
+
+func (r ratingRepository) film(id string) *model.Film {
+	for _, film := range liveFilms(r.db) {
+		if film.Id == id {
+			return film
+		}
+	}
+	return nil
+}
+
+func NewRatingRepository() repository.RatingRepository {
+	return &ratingRepository{db: inMemDb.New()}
+}
@@ -0,0 +1,143 @@
+package mockRepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type reviewRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (r reviewRepository) Create(ctx context.Context, data *aggregate.ReviewAggregate) (*aggregate.ReviewAggregate, error) {
+	hasFilm := slices.ContainsFunc(liveFilms(r.db), func(item *model.Film) bool {
+		return item.Id == data.Review.FilmId
+	})
+	if !hasFilm {
+		return nil, errors.New("film foreign key violation")
+	}
+	has := slices.ContainsFunc(r.db.Review, func(item *model.Review) bool {
+		return item.Id == data.Review.Id || (item.UserId == data.Review.UserId && item.FilmId == data.Review.FilmId)
+	})
+	if has {
+		return nil, repository.ErrReviewExist
+	}
+
+	review := data.Review
+	review.CreatedAt = time.Now()
+	review.UpdatedAt = review.CreatedAt
+	r.db.Review = append(r.db.Review, &review)
+	return r.toAggregate(&review), nil
+}
+
+func (r reviewRepository) Update(ctx context.Context, data *aggregate.ReviewAggregate) (*aggregate.ReviewAggregate, error) {
+	for _, item := range r.db.Review {
+		if item.Id == data.Review.Id {
+			item.Title = data.Review.Title
+			item.Body = data.Review.Body
+			item.Spoiler = data.Review.Spoiler
+			item.Status = data.Review.Status
+			item.ModerationNote = data.Review.ModerationNote
+			item.UpdatedAt = time.Now()
+			return r.toAggregate(item), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r reviewRepository) Delete(ctx context.Context, id string) error {
+	count := len(r.db.Review)
+	r.db.Review = slices.DeleteFunc(r.db.Review, func(item *model.Review) bool {
+		return item.Id == id
+	})
+	if count == len(r.db.Review) {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r reviewRepository) GetById(ctx context.Context, id string) (*aggregate.ReviewAggregate, error) {
+	for _, item := range r.db.Review {
+		if item.Id == id {
+			return r.toAggregate(item), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r reviewRepository) GetByQuery(ctx context.Context, query domainQuery.ReviewRepositoryQuery) ([]*aggregate.ReviewAggregate, int, error) {
+	filtered := make([]*model.Review, 0, len(r.db.Review))
+	for _, item := range r.db.Review {
+		if query.FilmId != "" && item.FilmId != query.FilmId {
+			continue
+		}
+		if len(query.Statuses) != 0 && !slices.Contains(query.Statuses, item.Status) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+
+	pageCount := len(filtered) / query.PageCount
+	if len(filtered)%query.PageCount != 0 {
+		pageCount++
+	}
+
+	start := query.PageCount * (query.CurrentPage - 1)
+	if start > len(filtered) {
+		start = len(filtered)
+	}
+	end := min(start+query.PageCount, len(filtered))
+
+	result := make([]*aggregate.ReviewAggregate, 0, end-start)
+	for _, item := range filtered[start:end] {
+		result = append(result, r.toAggregate(item))
+	}
+	return result, pageCount, nil
+}
+
+func (r reviewRepository) HasByUserAndFilm(ctx context.Context, userId string, filmId string) (bool, error) {
+	return slices.ContainsFunc(r.db.Review, func(item *model.Review) bool {
+		return item.UserId == userId && item.FilmId == filmId
+	}), nil
+}
+
+func (r reviewRepository) SetStatus(ctx context.Context, id string, status string, note *string) (*aggregate.ReviewAggregate, error) {
+	for _, item := range r.db.Review {
+		if item.Id == id {
+			item.Status = status
+			item.ModerationNote = note
+			item.UpdatedAt = time.Now()
+			return r.toAggregate(item), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// toAggregate копия рецензии с оценкой автора, как LEFT JOIN ratings в postgres
+func (r reviewRepository) toAggregate(review *model.Review) *aggregate.ReviewAggregate {
+	result := &aggregate.ReviewAggregate{Review: *review}
+	for _, rating := range r.db.Rating {
+		if rating.UserId == review.UserId && rating.FilmId == review.FilmId {
+			copied := *rating
+			result.Rating = &copied
+		}
+	}
+	return result
+}
+
+func NewReviewRepository() repository.ReviewRepository {
+	return &reviewRepository{db: inMemDb.New()}
+}
@@ -0,0 +1,64 @@
+package mockRepository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type revisionRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (r revisionRepository) Create(ctx context.Context, revision *model.Revision) (*model.Revision, error) {
+	created := *revision
+	created.Number = 1
+	for _, item := range r.db.Revision {
+		if item.EntityType == revision.EntityType && item.EntityId == revision.EntityId && item.Number >= created.Number {
+			created.Number = item.Number + 1
+		}
+	}
+	created.CreatedAt = time.Now()
+	r.db.Revision = append(r.db.Revision, &created)
+	result := created
+	return &result, nil
+}
+
+func (r revisionRepository) GetByEntity(ctx context.Context, entityType string, entityId string, query domainQuery.PageQuery) ([]*model.Revision, int, error) {
+	filtered := make([]*model.Revision, 0, 8)
+	// ревизии добавляются по возрастанию номера, обратный обход дает порядок от новых к старым
+	for i := len(r.db.Revision) - 1; i >= 0; i-- {
+		item := r.db.Revision[i]
+		if item.EntityType == entityType && item.EntityId == entityId {
+			copied := *item
+			filtered = append(filtered, &copied)
+		}
+	}
+
+	pageCount := len(filtered) / query.PageCount
+	if len(filtered)%query.PageCount != 0 {
+		pageCount++
+	}
+	start := min(query.PageCount*(query.CurrentPage-1), len(filtered))
+	end := min(start+query.PageCount, len(filtered))
+	return filtered[start:end], pageCount, nil
+}
+
+func (r revisionRepository) GetByNumber(ctx context.Context, entityType string, entityId string, number int) (*model.Revision, error) {
+	for _, item := range r.db.Revision {
+		if item.EntityType == entityType && item.EntityId == entityId && item.Number == number {
+			copied := *item
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func NewRevisionRepository() repository.RevisionRepository {
+	return &revisionRepository{db: inMemDb.New()}
+}
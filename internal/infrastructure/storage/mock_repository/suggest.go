@@ -0,0 +1,68 @@
+package mockRepository
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type suggestRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (s suggestRepository) Suggest(ctx context.Context, query domainQuery.SuggestQuery) ([]*model.Suggestion, error) {
+	suggestions := make([]*model.Suggestion, 0, query.Limit)
+
+	for _, film := range liveFilms(s.db) {
+		if sml := wordSimilarity(query.Value, film.Name); sml >= similarityThreshold {
+			suggestions = append(suggestions, &model.Suggestion{Type: constants.SuggestFilm, Id: film.Id, Name: film.Name, Similarity: sml, Popularity: film.VoteCount})
+		}
+	}
+	for _, actor := range liveActors(s.db) {
+		if sml := wordSimilarity(query.Value, actor.Name); sml >= similarityThreshold {
+			suggestions = append(suggestions, &model.Suggestion{Type: constants.SuggestActor, Id: actor.Id, Name: actor.Name, Similarity: sml, Popularity: s.actorFilmCount(actor.Id)})
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b *model.Suggestion) int {
+		first, second := suggestScore(a), suggestScore(b)
+		if first != second {
+			if first > second {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if len(suggestions) > query.Limit {
+		suggestions = suggestions[:query.Limit]
+	}
+	return suggestions, nil
+}
+
+func (s suggestRepository) actorFilmCount(actorId string) int {
+	films := make([]string, 0, 8)
+	for _, item := range s.db.ActorFilm {
+		if item.ActorId == actorId && !slices.Contains(films, item.FilmId) {
+			films = append(films, item.FilmId)
+		}
+	}
+	return len(films)
+}
+
+// suggestScore та же формула, что и в postgres репозитории
+func suggestScore(suggestion *model.Suggestion) float64 {
+	return float64(suggestion.Similarity) + math.Log(1+float64(suggestion.Popularity))*0.05
+}
+
+func NewSuggestRepository() repository.SuggestRepository {
+	return &suggestRepository{db: inMemDb.New()}
+}
@@ -0,0 +1,108 @@
+package mockRepository
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+// liveFilms фильмы вне корзины, как условие deleted_at IS NULL в postgres
+func liveFilms(db *inMemDb.InMemDb) []*model.Film {
+	return slices.DeleteFunc(slices.Clone(db.Film), func(item *model.Film) bool {
+		return item.DeletedAt != nil
+	})
+}
+
+func liveActors(db *inMemDb.InMemDb) []*model.Actor {
+	return slices.DeleteFunc(slices.Clone(db.Actor), func(item *model.Actor) bool {
+		return item.DeletedAt != nil
+	})
+}
+
+// liveLinks связи, у которых ни фильм ни актер не в корзине
+func liveLinks(db *inMemDb.InMemDb) []*inMemDb.ActorFilm {
+	films, actors := liveFilms(db), liveActors(db)
+	return slices.DeleteFunc(slices.Clone(db.ActorFilm), func(item *inMemDb.ActorFilm) bool {
+		return !slices.ContainsFunc(films, func(film *model.Film) bool {
+			return film.Id == item.FilmId
+		}) || !slices.ContainsFunc(actors, func(actor *model.Actor) bool {
+			return actor.Id == item.ActorId
+		})
+	})
+}
+
+// trashPage страница корзины, сначала удаленные последними
+func trashPage[T any](items []*T, deletedAt func(item *T) int64, query domainQuery.PageQuery) ([]*T, int) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return deletedAt(items[i]) > deletedAt(items[j])
+	})
+	start := min(query.PageCount*(query.CurrentPage-1), len(items))
+	end := min(start+query.PageCount, len(items))
+
+	pageCount := len(items) / query.PageCount
+	if len(items)%query.PageCount != 0 {
+		pageCount++
+	}
+	return items[start:end], pageCount
+}
+
+// purgeFilms удаляет навсегда фильмы корзины, подходящие под match, и все их зависимые записи как каскад в postgres
+func purgeFilms(db *inMemDb.InMemDb, match func(film *model.Film) bool) int {
+	ids := make([]string, 0, 8)
+	db.Film = slices.DeleteFunc(db.Film, func(item *model.Film) bool {
+		if item.DeletedAt != nil && match(item) {
+			ids = append(ids, item.Id)
+			return true
+		}
+		return false
+	})
+
+	db.ActorFilm = slices.DeleteFunc(db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+		return slices.Contains(ids, item.FilmId)
+	})
+	db.FilmGenre = slices.DeleteFunc(db.FilmGenre, func(item *inMemDb.FilmGenre) bool {
+		return slices.Contains(ids, item.FilmId)
+	})
+	db.Rating = slices.DeleteFunc(db.Rating, func(item *model.Rating) bool {
+		return slices.Contains(ids, item.FilmId)
+	})
+	db.Review = slices.DeleteFunc(db.Review, func(item *model.Review) bool {
+		return slices.Contains(ids, item.FilmId)
+	})
+	db.FilmExternalId = slices.DeleteFunc(db.FilmExternalId, func(item *inMemDb.ExternalId) bool {
+		return slices.Contains(ids, item.EntityId)
+	})
+	lists := make([]string, 0, 4)
+	db.UserListFilm = slices.DeleteFunc(db.UserListFilm, func(item *inMemDb.UserListFilm) bool {
+		if slices.Contains(ids, item.FilmId) {
+			lists = append(lists, item.ListId)
+			return true
+		}
+		return false
+	})
+	for _, listId := range lists {
+		renumberListFilms(db, listId)
+	}
+	return len(ids)
+}
+
+func purgeActors(db *inMemDb.InMemDb, match func(actor *model.Actor) bool) int {
+	ids := make([]string, 0, 8)
+	db.Actor = slices.DeleteFunc(db.Actor, func(item *model.Actor) bool {
+		if item.DeletedAt != nil && match(item) {
+			ids = append(ids, item.Id)
+			return true
+		}
+		return false
+	})
+	db.ActorFilm = slices.DeleteFunc(db.ActorFilm, func(item *inMemDb.ActorFilm) bool {
+		return slices.Contains(ids, item.ActorId)
+	})
+	db.ActorExternalId = slices.DeleteFunc(db.ActorExternalId, func(item *inMemDb.ExternalId) bool {
+		return slices.Contains(ids, item.EntityId)
+	})
+	return len(ids)
+}
@@ -0,0 +1,50 @@
+package mockRepository
+
+import (
+	"strings"
+	"unicode"
+)
+
+// similarityThreshold порог схожести, как pg_trgm.similarity_threshold по умолчанию
+const similarityThreshold = 0.3
+
+// trigrams набор триграмм как в pg_trgm: слова в нижнем регистре дополняются двумя пробелами слева и одним справа
+func trigrams(value string) map[string]struct{} {
+	result := make(map[string]struct{})
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			result[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+	return result
+}
+
+func similarity(a string, b string) float32 {
+	first, second := trigrams(a), trigrams(b)
+	if len(first) == 0 || len(second) == 0 {
+		return 0
+	}
+	common := 0
+	for trigram := range first {
+		if _, ok := second[trigram]; ok {
+			common++
+		}
+	}
+	return float32(common) / float32(len(first)+len(second)-common)
+}
+
+// wordSimilarity приближение word_similarity: лучшая схожесть запроса с непрерывной последовательностью слов текста
+func wordSimilarity(query string, text string) float32 {
+	words := strings.Fields(text)
+	best := float32(0)
+	for i := range words {
+		for j := i + 1; j <= len(words); j++ {
+			best = max(best, similarity(query, strings.Join(words[i:j], " ")))
+		}
+	}
+	return best
+}
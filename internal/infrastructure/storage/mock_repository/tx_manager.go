@@ -0,0 +1,40 @@
+package mockRepository
+
+import (
+	"context"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type (
+	txKey struct{}
+
+	txManager struct {
+		db *inMemDb.InMemDb
+	}
+)
+
+// Do при ошибке или панике в fn возвращает in memory базу к состоянию до начала транзакции
+func (t txManager) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	snapshot := t.db.Snapshot()
+	defer func() {
+		if p := recover(); p != nil {
+			t.db.Restore(snapshot)
+			panic(p)
+		}
+		if err != nil {
+			t.db.Restore(snapshot)
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, struct{}{}))
+}
+
+func NewTxManager() repository.TxManager {
+	return &txManager{db: inMemDb.New()}
+}
@@ -30,11 +30,6 @@ func (u userRepository) Create(ctx context.Context, data *aggregate.UserAggregat
 
 	u.db.Users = append(u.db.Users, &data.User)
 
-	if data.Token != nil {
-		token := model.Token{Id: data.Token.Id, Value: data.Token.Value}
-		u.db.Tokens = append(u.db.Tokens, &token)
-	}
-
 	return data, nil
 }
 
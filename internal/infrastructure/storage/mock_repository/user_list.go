@@ -0,0 +1,225 @@
+package mockRepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+)
+
+type userListRepository struct {
+	db *inMemDb.InMemDb
+}
+
+func (u userListRepository) Create(ctx context.Context, data *model.UserList) (*model.UserList, error) {
+	has := slices.ContainsFunc(u.db.UserList, func(item *model.UserList) bool {
+		return item.Id == data.Id || (data.ShareSlug != nil && item.ShareSlug != nil && *item.ShareSlug == *data.ShareSlug)
+	})
+	if has {
+		return nil, errors.New("conflict fields")
+	}
+
+	list := *data
+	list.CreatedAt = time.Now()
+	u.db.UserList = append(u.db.UserList, &list)
+	return u.withCount(&list), nil
+}
+
+func (u userListRepository) CreateSystem(ctx context.Context, data *model.UserList) error {
+	has := slices.ContainsFunc(u.db.UserList, func(item *model.UserList) bool {
+		return item.UserId == data.UserId && item.Kind == data.Kind
+	})
+	if has {
+		return nil
+	}
+	_, err := u.Create(ctx, data)
+	return err
+}
+
+func (u userListRepository) Update(ctx context.Context, data *model.UserList) (*model.UserList, error) {
+	for _, item := range u.db.UserList {
+		if item.Id == data.Id {
+			item.Name = data.Name
+			item.ShareSlug = data.ShareSlug
+			return u.withCount(item), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (u userListRepository) Delete(ctx context.Context, id string) error {
+	count := len(u.db.UserList)
+	u.db.UserList = slices.DeleteFunc(u.db.UserList, func(item *model.UserList) bool {
+		return item.Id == id
+	})
+	if count == len(u.db.UserList) {
+		return sql.ErrNoRows
+	}
+	u.db.UserListFilm = slices.DeleteFunc(u.db.UserListFilm, func(item *inMemDb.UserListFilm) bool {
+		return item.ListId == id
+	})
+	return nil
+}
+
+func (u userListRepository) GetById(ctx context.Context, id string) (*model.UserList, error) {
+	for _, item := range u.db.UserList {
+		if item.Id == id {
+			return u.withCount(item), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (u userListRepository) GetBySlug(ctx context.Context, slug string) (*model.UserList, error) {
+	for _, item := range u.db.UserList {
+		if item.ShareSlug != nil && *item.ShareSlug == slug {
+			return u.withCount(item), nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (u userListRepository) GetByUser(ctx context.Context, userId string) ([]*model.UserList, error) {
+	lists := make([]*model.UserList, 0, 8)
+	for _, item := range u.db.UserList {
+		if item.UserId == userId {
+			lists = append(lists, u.withCount(item))
+		}
+	}
+	return lists, nil
+}
+
+func (u userListRepository) AddFilm(ctx context.Context, listId string, filmId string) error {
+	if !u.hasList(listId) {
+		return sql.ErrNoRows
+	}
+	hasFilm := slices.ContainsFunc(liveFilms(u.db), func(item *model.Film) bool {
+		return item.Id == filmId
+	})
+	if !hasFilm {
+		return sql.ErrNoRows
+	}
+
+	items := u.items(listId)
+	for _, item := range items {
+		if item.FilmId == filmId {
+			return nil
+		}
+	}
+	u.db.UserListFilm = append(u.db.UserListFilm, &inMemDb.UserListFilm{ListId: listId, FilmId: filmId, Position: len(items), AddedAt: time.Now()})
+	return nil
+}
+
+func (u userListRepository) RemoveFilm(ctx context.Context, listId string, filmId string) error {
+	if !u.hasList(listId) {
+		return sql.ErrNoRows
+	}
+	count := len(u.db.UserListFilm)
+	u.db.UserListFilm = slices.DeleteFunc(u.db.UserListFilm, func(item *inMemDb.UserListFilm) bool {
+		return item.ListId == listId && item.FilmId == filmId
+	})
+	if count == len(u.db.UserListFilm) {
+		return sql.ErrNoRows
+	}
+	renumberListFilms(u.db, listId)
+	return nil
+}
+
+func (u userListRepository) MoveFilm(ctx context.Context, listId string, filmId string, position int) error {
+	if !u.hasList(listId) {
+		return sql.ErrNoRows
+	}
+	items := u.items(listId)
+	current := slices.IndexFunc(items, func(item *inMemDb.UserListFilm) bool {
+		return item.FilmId == filmId
+	})
+	if current == -1 {
+		return sql.ErrNoRows
+	}
+
+	position = max(0, min(position, len(items)-1))
+	moved := items[current]
+	items = slices.Delete(items, current, current+1)
+	items = slices.Insert(items, position, moved)
+	for i, item := range items {
+		item.Position = i
+	}
+	return nil
+}
+
+func (u userListRepository) GetFilms(ctx context.Context, listId string, query domainQuery.PageQuery) ([]*aggregate.FilmAggregate, int, error) {
+	items := u.liveItems(listId)
+	start := min(query.PageCount*(query.CurrentPage-1), len(items))
+	end := min(start+query.PageCount, len(items))
+
+	films := make([]*aggregate.FilmAggregate, 0, end-start)
+	for _, item := range items[start:end] {
+		for _, film := range liveFilms(u.db) {
+			if film.Id == item.FilmId {
+				films = append(films, &aggregate.FilmAggregate{Film: *film})
+			}
+		}
+	}
+
+	pageCount := len(items) / query.PageCount
+	if len(items)%query.PageCount != 0 {
+		pageCount++
+	}
+	return films, pageCount, nil
+}
+
+func (u userListRepository) hasList(id string) bool {
+	return slices.ContainsFunc(u.db.UserList, func(item *model.UserList) bool {
+		return item.Id == id
+	})
+}
+
+// liveItems фильмы списка без фильмов из корзины, позиции при этом не меняются
+func (u userListRepository) liveItems(listId string) []*inMemDb.UserListFilm {
+	films := liveFilms(u.db)
+	return slices.DeleteFunc(u.items(listId), func(item *inMemDb.UserListFilm) bool {
+		return !slices.ContainsFunc(films, func(film *model.Film) bool {
+			return film.Id == item.FilmId
+		})
+	})
+}
+
+// items фильмы списка, отсортированные по позиции
+func (u userListRepository) items(listId string) []*inMemDb.UserListFilm {
+	items := make([]*inMemDb.UserListFilm, 0, 16)
+	for _, item := range u.db.UserListFilm {
+		if item.ListId == listId {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Position < items[j].Position
+	})
+	return items
+}
+
+func (u userListRepository) withCount(list *model.UserList) *model.UserList {
+	result := *list
+	result.FilmCount = len(u.liveItems(list.Id))
+	return &result
+}
+
+// renumberListFilms восстанавливает позиции 0..n-1 после удаления фильма из списка
+func renumberListFilms(db *inMemDb.InMemDb, listId string) {
+	items := userListRepository{db: db}.items(listId)
+	for i, item := range items {
+		item.Position = i
+	}
+}
+
+func NewUserListRepository() repository.UserListRepository {
+	return &userListRepository{db: inMemDb.New()}
+}
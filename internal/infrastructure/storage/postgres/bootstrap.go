@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/valuesobject"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	"github.com/google/uuid"
+)
+
+// SeedAdmin создает админа из конфига, если его еще нет. Вызывать после применения миграций
+func SeedAdmin(ctx context.Context, db *sql.DB, cfg *config.Config) error {
+	query := "SELECT EXISTS(SELECT 1 FROM users WHERE name = $1)"
+	var exists bool
+	err := db.QueryRowContext(ctx, query, cfg.AdminName).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	hashPassword, err := valuesobject.NewPassword(cfg.AdminPassword)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name, password, role) VALUES ($1, $2, $3, $4)`,
+		uuid.New().String(), cfg.AdminName, hashPassword.Value, constants.AdminRole)
+	return err
+}
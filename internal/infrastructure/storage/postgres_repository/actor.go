@@ -3,21 +3,25 @@ package postgresRepository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/lib/pq"
 	"slices"
 	"time"
 )
 
 type actorRepository struct {
 	db *sql.DB
+	// rate колонка оценки фильмов, см. filmRateColumn
+	rate string
 }
 
 func (a actorRepository) Create(ctx context.Context, data *aggregate.ActorAggregate) (*aggregate.ActorAggregate, error) {
-	query := "INSERT INTO actors (id, name, gender, birthday) VALUES ($1, $2, $3, $4) RETURNING id, name, gender, birthday"
-	stmt, err := a.db.PrepareContext(ctx, query)
+	query := "INSERT INTO actors (id, name, gender, birthday) VALUES ($1, $2, $3, $4) RETURNING id, name, gender, birthday, version"
+	stmt, err := conn(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +30,7 @@ func (a actorRepository) Create(ctx context.Context, data *aggregate.ActorAggreg
 	}(stmt)
 
 	actor := data.Actor
-	err = stmt.QueryRowContext(ctx, actor.Id, actor.Name, actor.Gender, actor.Birthday).Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.Birthday)
+	err = stmt.QueryRowContext(ctx, actor.Id, actor.Name, actor.Gender, actor.Birthday).Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.Birthday, &actor.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -36,8 +40,10 @@ func (a actorRepository) Create(ctx context.Context, data *aggregate.ActorAggreg
 }
 
 func (a actorRepository) Update(ctx context.Context, data *aggregate.ActorAggregate) (*aggregate.ActorAggregate, error) {
-	query := "UPDATE actors SET name = $1, gender = $2, birthday = $3 WHERE id = $4 RETURNING id, name, gender, birthday"
-	stmt, err := a.db.PrepareContext(ctx, query)
+	query := `UPDATE actors SET name = $1, gender = $2, birthday = $3, version = version + 1
+		WHERE id = $4 AND deleted_at IS NULL AND ($5::int = 0 OR version = $5)
+		RETURNING id, name, gender, birthday, version`
+	stmt, err := conn(ctx, a.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -46,9 +52,9 @@ func (a actorRepository) Update(ctx context.Context, data *aggregate.ActorAggreg
 	}(stmt)
 
 	actor := data.Actor
-	err = stmt.QueryRowContext(ctx, actor.Name, actor.Gender, actor.Birthday, actor.Id).Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.Birthday)
+	err = stmt.QueryRowContext(ctx, actor.Name, actor.Gender, actor.Birthday, actor.Id, actor.Version).Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.Birthday, &actor.Version)
 	if err != nil {
-		return nil, err
+		return nil, versionError(ctx, conn(ctx, a.db), "actors", actor.Id, err)
 	}
 
 	data.Actor = actor
@@ -56,94 +62,123 @@ func (a actorRepository) Update(ctx context.Context, data *aggregate.ActorAggreg
 }
 
 func (a actorRepository) Delete(ctx context.Context, id string) error {
-	_, err := a.db.ExecContext(ctx, "DELETE FROM actor_film WHERE actor_id = $1", id)
-	if err != nil {
-		return err
-	}
-	query := "DELETE FROM actors WHERE id = $1"
-	_, err = a.db.ExecContext(ctx, query, id)
-	return err
+	return softDelete(ctx, a.db, creditsByActor, id)
 }
 
-func (a actorRepository) AddFilm(ctx context.Context, actorId string, filmIds ...string) error {
-	tx, err := a.db.BeginTx(ctx, nil)
+func (a actorRepository) GetDeleted(ctx context.Context, query domainQuery.PageQuery) ([]*aggregate.ActorAggregate, int, error) {
+	rows, err := conn(ctx, a.db).QueryContext(ctx, `
+		SELECT id, name, gender, birthday, version, deleted_at
+		FROM actors
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+		LIMIT $1 OFFSET $2
+	`, query.PageCount, query.PageCount*(query.CurrentPage-1))
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	defer func() {
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	actors := make([]*aggregate.ActorAggregate, 0, query.PageCount)
+	for rows.Next() {
+		aggr := &aggregate.ActorAggregate{}
+		err := rows.Scan(&aggr.Actor.Id, &aggr.Actor.Name, &aggr.Actor.Gender, &aggr.Actor.Birthday, &aggr.Actor.Version, &aggr.Actor.DeletedAt)
 		if err != nil {
-			_ = tx.Rollback()
-			return
+			return nil, 0, err
 		}
-		err = tx.Commit()
-	}()
-
-	var actorExists bool
-	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM actors WHERE id = $1)", actorId).Scan(&actorExists)
-	if err != nil {
-		return err
+		actors = append(actors, aggr)
 	}
-	if !actorExists {
-		return sql.ErrNoRows
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	for _, filmId := range filmIds {
-		var filmExists bool
-		err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM films WHERE id = $1)", filmId).Scan(&filmExists)
-		if err != nil {
-			return err
-		}
-		if !filmExists {
-			return sql.ErrNoRows
-		}
-
-		_, err := tx.ExecContext(ctx, "INSERT INTO actor_film (actor_id, film_id) VALUES ($1, $2)", actorId, filmId)
-		if err != nil {
-			return err
-		}
+	total := 0
+	if err = conn(ctx, a.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM actors WHERE deleted_at IS NOT NULL").Scan(&total); err != nil {
+		return nil, 0, err
 	}
+	return actors, pageCount(total, query.PageCount), nil
+}
 
-	return nil
+func (a actorRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, a.db, creditsByActor, id)
+}
+
+func (a actorRepository) Purge(ctx context.Context, id string) error {
+	return purgeOne(ctx, a.db, creditsByActor, id)
+}
+
+func (a actorRepository) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	return purgeDeleted(ctx, a.db, creditsByActor, "deleted_at < $1", before)
+}
+
+func (a actorRepository) AddFilm(ctx context.Context, actorId string, credits ...*model.Credit) error {
+	return addCredits(ctx, a.db, creditsByActor, actorId, credits)
+}
+
+func (a actorRepository) SetFilms(ctx context.Context, actorId string, credits ...*model.Credit) error {
+	return replaceCredits(ctx, a.db, creditsByActor, actorId, credits)
+}
+
+func (a actorRepository) RemoveFilm(ctx context.Context, actorId string, filmIds ...string) error {
+	return removeCredits(ctx, a.db, creditsByActor, actorId, filmIds)
 }
 
 func (a actorRepository) GetById(ctx context.Context, id string) (*aggregate.ActorAggregate, error) {
-	query := "SELECT id, name, gender, birthday FROM actors WHERE id = $1"
-	row := a.db.QueryRowContext(ctx, query, id)
+	query := "SELECT id, name, gender, birthday, version FROM actors WHERE id = $1 AND deleted_at IS NULL"
+	row := conn(ctx, a.db).QueryRowContext(ctx, query, id)
 
 	var actor model.Actor
-	err := row.Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.Birthday)
+	err := row.Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.Birthday, &actor.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	credits, err := loadCredits(ctx, a.db, creditsByActor, []string{actor.Id})
 	if err != nil {
 		return nil, err
 	}
 
-	return &aggregate.ActorAggregate{Actor: actor}, nil
+	result := &aggregate.ActorAggregate{Actor: actor}
+	result.SetCredits(credits[actor.Id])
+	return result, nil
+}
+
+func (a actorRepository) GetByNameAndBirthday(ctx context.Context, name string, birthday time.Time) (*aggregate.ActorAggregate, error) {
+	query := "SELECT id FROM actors WHERE lower(name) = lower($1) AND birthday = $2 AND deleted_at IS NULL ORDER BY id LIMIT 1"
+	var id string
+	if err := conn(ctx, a.db).QueryRowContext(ctx, query, name, birthday).Scan(&id); err != nil {
+		return nil, err
+	}
+	return a.GetById(ctx, id)
 }
 
+// GetByQuery LIMIT/OFFSET применяются к актерам, фильмы догружаются отдельным запросом для всей страницы.
+// С курсором вместо OFFSET используется keyset условие и выбирается на одного актера больше
 func (a actorRepository) GetByQuery(ctx context.Context, query domainQuery.ActorRepositoryQuery) ([]*aggregate.ActorAggregate, int, error) {
 	offset := query.PageCount * (query.CurrentPage - 1)
 	limit := query.PageCount
+	fetch := limit
+	direction := query.OrderBy
+	if query.Cursor != nil {
+		offset, fetch = 0, limit+1
+		if query.Cursor.Before {
+			direction = reverseDirection(direction)
+		}
+	}
 
 	sqlQuery := `
-		SELECT
-		a.*, 
-		f.id AS film_id, 
-		f.name AS film_name, 
-		f.description AS film_description, 
-		f.release_date AS film_release_date, 
-		f.rate AS film_rate
+		SELECT a.id, a.name, a.gender, a.birthday, a.version
 		FROM actors a
-		LEFT JOIN actor_film af ON a.id = af.actor_id and 'film' = $1
-		LEFT JOIN films f ON af.film_id = f.id and 'film' = $1
-		ORDER BY a.id, f.id NULLS LAST
-		LIMIT $2 OFFSET $3
+		WHERE ` + actorFilterSql("a", 3) + ` AND ` + actorKeysetSql(query.Cursor, 10) + `
+		ORDER BY ` + actorOrderSql(query.SortField, direction) + `
+		LIMIT $1 OFFSET $2
     `
 
-	conn := "not"
-	if slices.Contains(query.WithConnection, "film") {
-		conn = "film"
-	}
-
-	rows, err := a.db.QueryContext(ctx, sqlQuery, conn, limit, offset)
+	filterArgs := actorFilterArgs(query)
+	args := append([]interface{}{fetch, offset}, filterArgs...)
+	args = append(args, actorKeysetArgs(query.Cursor)...)
+	rows, err := conn(ctx, a.db).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -151,57 +186,34 @@ func (a actorRepository) GetByQuery(ctx context.Context, query domainQuery.Actor
 		_ = rows.Close()
 	}(rows)
 
-	actorMap := make(map[string]*aggregate.ActorAggregate, 20)
-	actors := make([]*aggregate.ActorAggregate, 0, len(actorMap))
-
+	actors := make([]*aggregate.ActorAggregate, 0, fetch)
 	for rows.Next() {
-		var (
-			actorID         string
-			actorName       string
-			actorGender     string
-			actorBirthday   time.Time
-			filmID          sql.NullString
-			filmName        sql.NullString
-			filmDescription sql.NullString
-			filmReleaseDate sql.NullTime
-			filmRate        sql.NullFloat64
-		)
-
-		err := rows.Scan(&actorID, &actorName, &actorGender, &actorBirthday, &filmID, &filmName, &filmDescription, &filmReleaseDate, &filmRate)
+		aggr := &aggregate.ActorAggregate{}
+		err := rows.Scan(&aggr.Actor.Id, &aggr.Actor.Name, &aggr.Actor.Gender, &aggr.Actor.Birthday, &aggr.Actor.Version)
 		if err != nil {
 			return nil, 0, err
 		}
-
-		var aggr *aggregate.ActorAggregate
-		if res, ok := actorMap[actorID]; !ok {
-			aggr = &aggregate.ActorAggregate{
-				Actor: model.Actor{
-					Id:       actorID,
-					Name:     actorName,
-					Gender:   actorGender,
-					Birthday: actorBirthday,
-				},
-			}
-			actorMap[actorID] = aggr
-			actors = append(actors, aggr)
-		} else {
-			aggr = res
-		}
-
-		if filmID.Valid {
-			aggr.Films = append(aggr.Films, &model.Film{Id: filmID.String, Name: filmName.String, Description: &filmDescription.String, ReleaseDate: filmReleaseDate.Time, Rate: float32(filmRate.Float64)})
-		}
+		actors = append(actors, aggr)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, 0, err
 	}
+	if query.Cursor != nil && query.Cursor.Before {
+		slices.Reverse(actors)
+	}
+
+	if slices.Contains(query.WithConnection, "film") {
+		if err := a.loadFilms(ctx, actors); err != nil {
+			return nil, 0, err
+		}
+	}
 
 	totalCount := 0
-	err = a.db.QueryRowContext(ctx, `
+	err = conn(ctx, a.db).QueryRowContext(ctx, `
         SELECT COUNT(*)
-        FROM actors
-    `).Scan(&totalCount)
+        FROM actors a
+        WHERE `+actorFilterSql("a", 1), filterArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -215,6 +227,153 @@ func (a actorRepository) GetByQuery(ctx context.Context, query domainQuery.Actor
 	return actors, totalCount, nil
 }
 
-func NewActorRepository(db *sql.DB) repository.ActorRepository {
-	return &actorRepository{db: db}
+func (a actorRepository) Each(ctx context.Context, query domainQuery.ActorRepositoryQuery, fn func(actor *model.Actor) error) error {
+	sqlQuery := `
+		SELECT a.id, a.name, a.gender, a.birthday, a.version
+		FROM actors a
+		WHERE ` + actorFilterSql("a", 1) + `
+		ORDER BY ` + actorOrderSql(query.SortField, query.OrderBy)
+
+	return eachRow(ctx, conn(ctx, a.db), sqlQuery, actorFilterArgs(query), func(row rowScanner) error {
+		var actor model.Actor
+		if err := row.Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.Birthday, &actor.Version); err != nil {
+			return err
+		}
+		return fn(&actor)
+	})
+}
+
+// loadFilms догружает фильмы и титры одним запросом на каждое для всех переданных актеров
+func (a actorRepository) loadFilms(ctx context.Context, actors []*aggregate.ActorAggregate) error {
+	if len(actors) == 0 {
+		return nil
+	}
+	actorsMap := make(map[string]*aggregate.ActorAggregate, len(actors))
+	ids := make([]string, 0, len(actors))
+	for _, actor := range actors {
+		actorsMap[actor.Actor.Id] = actor
+		ids = append(ids, actor.Actor.Id)
+	}
+
+	rows, err := conn(ctx, a.db).QueryContext(ctx, `
+		SELECT af.actor_id, f.id, f.name, f.description, f.release_date, f.`+a.rate+`, f.manual_rate, f.vote_count, f.version
+		FROM (SELECT DISTINCT actor_id, film_id FROM actor_film WHERE actor_id = ANY($1::uuid[])) af
+		JOIN films f ON af.film_id = f.id AND f.deleted_at IS NULL
+		ORDER BY f.id
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	for rows.Next() {
+		var (
+			actorId string
+			film    model.Film
+		)
+		if err := rows.Scan(&actorId, &film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate, &film.ManualRate, &film.VoteCount, &film.Version); err != nil {
+			return err
+		}
+		if actor, ok := actorsMap[actorId]; ok {
+			actor.Films = append(actor.Films, &film)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	credits, err := loadCredits(ctx, a.db, creditsByActor, ids)
+	if err != nil {
+		return err
+	}
+	for _, actor := range actors {
+		actor.SetCredits(credits[actor.Actor.Id])
+	}
+	return nil
+}
+
+// actorSortColumns допустимые поля сортировки, в запрос попадают только значения из этой карты
+var actorSortColumns = map[string]string{
+	"name":     "name",
+	"birthday": "birthday",
+}
+
+var actorSortTypes = map[string]string{
+	"name":     "text",
+	"birthday": "date",
+}
+
+func actorOrderSql(sortField string, orderBy domainQuery.OrderDirection) string {
+	direction := domainQuery.Asc
+	if orderBy == domainQuery.Desc {
+		direction = domainQuery.Desc
+	}
+	column, ok := actorSortColumns[sortField]
+	if !ok {
+		return fmt.Sprintf("a.id %s", direction)
+	}
+	return fmt.Sprintf("a.%s %s, a.id %s", column, direction, direction)
+}
+
+// actorKeysetSql условие keyset пагинации по паре (поле сортировки, id), согласованное с actorOrderSql.
+// idArg - номер параметра с id курсора, следующий за ним - значение поля сортировки
+func actorKeysetSql(cursor *domainQuery.Cursor, idArg int) string {
+	if cursor == nil {
+		return "TRUE"
+	}
+	operator := "<"
+	if cursor.Greater() {
+		operator = ">"
+	}
+	column, ok := actorSortColumns[cursor.SortField]
+	if !ok {
+		return fmt.Sprintf("a.id %s $%d::uuid", operator, idArg)
+	}
+	return fmt.Sprintf("(a.%s, a.id) %s ($%d::%s, $%d::uuid)", column, operator, idArg+1, actorSortTypes[column], idArg)
+}
+
+func actorKeysetArgs(cursor *domainQuery.Cursor) []interface{} {
+	if cursor == nil {
+		return []interface{}{}
+	}
+	if _, ok := actorSortColumns[cursor.SortField]; !ok {
+		return []interface{}{cursor.Id}
+	}
+	return []interface{}{cursor.Id, cursor.Value}
+}
+
+// actorFilterSql условия фильтров актера, firstArg - номер первого из семи параметров actorFilterArgs.
+// Актеры из корзины исключаются всегда, %, _ и \ в имени ищутся буквально
+func actorFilterSql(alias string, firstArg int) string {
+	return fmt.Sprintf(`%[1]s.deleted_at IS NULL
+		AND ($%[2]d::text IS NULL OR %[1]s.name ILIKE '%%' || replace(replace(replace($%[2]d, '\', '\\'), '%%', '\%%'), '_', '\_') || '%%' ESCAPE '\' OR $%[2]d <%% %[1]s.name)
+		AND ($%[3]d::text IS NULL OR %[1]s.gender = $%[3]d)
+		AND ($%[4]d::int IS NULL OR EXTRACT(YEAR FROM %[1]s.birthday) >= $%[4]d)
+		AND ($%[5]d::int IS NULL OR EXTRACT(YEAR FROM %[1]s.birthday) <= $%[5]d)
+		AND ($%[6]d::int IS NULL OR date_part('year', age(%[1]s.birthday)) >= $%[6]d)
+		AND ($%[7]d::int IS NULL OR date_part('year', age(%[1]s.birthday)) <= $%[7]d)
+		AND ($%[8]d::uuid IS NULL OR EXISTS (SELECT 1 FROM actor_film fa WHERE fa.actor_id = %[1]s.id AND fa.film_id = $%[8]d))`,
+		alias, firstArg, firstArg+1, firstArg+2, firstArg+3, firstArg+4, firstArg+5, firstArg+6)
+}
+
+func actorFilterArgs(query domainQuery.ActorRepositoryQuery) []interface{} {
+	return []interface{}{
+		nullString(query.Name),
+		nullString(query.Gender),
+		query.BirthYearFrom,
+		query.BirthYearTo,
+		query.AgeFrom,
+		query.AgeTo,
+		nullString(query.FilmId),
+	}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func NewActorRepository(db *sql.DB, honorManualRate bool) repository.ActorRepository {
+	return &actorRepository{db: db, rate: filmRateColumn(honorManualRate)}
 }
@@ -0,0 +1,87 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+// auditFilterSql пустые строки и NULL в параметрах отключают фильтр
+const auditFilterSql = `
+	WHERE ($1 = '' OR user_id = $1)
+	AND ($2 = '' OR entity_type = $2)
+	AND ($3 = '' OR entity_id = $3)
+	AND ($4::timestamptz IS NULL OR created_at >= $4)
+	AND ($5::timestamptz IS NULL OR created_at <= $5)
+`
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func (a auditRepository) Create(ctx context.Context, entry *model.AuditEntry) (*model.AuditEntry, error) {
+	query := `
+		INSERT INTO audit_log (id, user_id, action, entity_type, entity_id, diff, request_id)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, NULLIF($7, ''))
+		RETURNING created_at
+	`
+	result := *entry
+	err := conn(ctx, a.db).QueryRowContext(ctx, query, entry.Id, entry.UserId, entry.Action, entry.EntityType, entry.EntityId, []byte(entry.Diff), entry.RequestId).
+		Scan(&result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (a auditRepository) GetByQuery(ctx context.Context, query domainQuery.AuditRepositoryQuery) ([]*model.AuditEntry, int, error) {
+	offset := query.PageCount * (query.CurrentPage - 1)
+	limit := query.PageCount
+	args := []interface{}{query.UserId, query.EntityType, query.EntityId, query.From, query.To}
+
+	rows, err := conn(ctx, a.db).QueryContext(ctx, `
+		SELECT id, COALESCE(user_id, ''), action, entity_type, entity_id, diff, COALESCE(request_id, ''), created_at
+		FROM audit_log
+	`+auditFilterSql+`
+		ORDER BY created_at DESC, id
+		LIMIT $6 OFFSET $7
+	`, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	entries := make([]*model.AuditEntry, 0, limit)
+	for rows.Next() {
+		var (
+			entry model.AuditEntry
+			diff  []byte
+		)
+		err := rows.Scan(&entry.Id, &entry.UserId, &entry.Action, &entry.EntityType, &entry.EntityId, &diff, &entry.RequestId, &entry.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		entry.Diff = diff
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	err = conn(ctx, a.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log "+auditFilterSql, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, pageCount(total, limit), nil
+}
+
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &auditRepository{db: db}
+}
@@ -0,0 +1,211 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/lib/pq"
+)
+
+const (
+	creditsByFilm  = "film_id"
+	creditsByActor = "actor_id"
+)
+
+// loadCredits возвращает титры сгруппированные по film_id или actor_id (by - одна из констант creditsBy*).
+// Связи с записями из корзины не возвращаются, но остаются в таблице до очистки
+func loadCredits(ctx context.Context, db *sql.DB, by string, ids []string) (map[string][]*model.Credit, error) {
+	result := make(map[string][]*model.Credit, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	rows, err := conn(ctx, db).QueryContext(ctx, `
+		SELECT af.actor_id, af.film_id, af.role, af.character, af.billing_order
+		FROM actor_film af
+		JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL
+		JOIN actors a ON a.id = af.actor_id AND a.deleted_at IS NULL
+		WHERE af.`+by+` = ANY($1::uuid[])
+		ORDER BY af.billing_order, af.role
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	for rows.Next() {
+		var credit model.Credit
+		if err := rows.Scan(&credit.ActorId, &credit.FilmId, &credit.Role, &credit.Character, &credit.BillingOrder); err != nil {
+			return nil, err
+		}
+		key := credit.FilmId
+		if by == creditsByActor {
+			key = credit.ActorId
+		}
+		result[key] = append(result[key], &credit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// creditTables таблица владельца связи и таблица связанной записи для by
+func creditTables(by string) (string, string) {
+	if by == creditsByActor {
+		return "actors", "films"
+	}
+	return "films", "actors"
+}
+
+// rowExists sql.ErrNoRows если записи с id нет, table только из констант кода
+func rowExists(ctx context.Context, tx querier, table string, id string) error {
+	return rowExistsWhere(ctx, tx, table, "id = $1", id)
+}
+
+// liveRowExists как rowExists, но запись в корзине тоже считается отсутствующей. table только films или actors
+func liveRowExists(ctx context.Context, tx querier, table string, id string) error {
+	return rowExistsWhere(ctx, tx, table, "id = $1 AND "+liveSql, id)
+}
+
+func rowExistsWhere(ctx context.Context, tx querier, table string, where string, id string) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM "+table+" WHERE "+where+")", id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// insertCredits связи всегда пишутся на ownerId, повторная связь с той же ролью не ошибка, она просто пропускается
+func insertCredits(ctx context.Context, tx querier, by string, ownerId string, credits []*model.Credit) error {
+	_, linkedTable := creditTables(by)
+	for _, credit := range credits {
+		linkedId := credit.ActorId
+		if by == creditsByActor {
+			linkedId, credit.ActorId = credit.FilmId, ownerId
+		} else {
+			credit.FilmId = ownerId
+		}
+		if err := liveRowExists(ctx, tx, linkedTable, linkedId); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO actor_film (actor_id, film_id, role, character, billing_order) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT DO NOTHING
+		`, credit.ActorId, credit.FilmId, credit.Role, credit.Character, credit.BillingOrder)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkedColumn колонка связанной записи для by
+func linkedColumn(by string) string {
+	if by == creditsByActor {
+		return creditsByFilm
+	}
+	return creditsByActor
+}
+
+// creditRows связи владельца по id связанной записи, нужны чтобы найти записи, чьи титры изменились
+func creditRows(ctx context.Context, tx querier, by string, ownerId string) (map[string]string, error) {
+	result := make(map[string]string)
+	query := "SELECT " + linkedColumn(by) + ", role, COALESCE(character, ''), billing_order FROM actor_film WHERE " + by + " = $1 ORDER BY role, billing_order, character"
+	err := eachRow(ctx, tx, query, []any{ownerId}, func(row rowScanner) error {
+		var linkedId, role, character string
+		var billing int
+		if err := row.Scan(&linkedId, &role, &character, &billing); err != nil {
+			return err
+		}
+		result[linkedId] += fmt.Sprintf("%s|%s|%d;", role, character, billing)
+		return nil
+	})
+	return result, err
+}
+
+// bumpVersions увеличивает версию записей, ETag которых зависит от изменившихся титров
+func bumpVersions(ctx context.Context, tx querier, table string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, "UPDATE "+table+" SET version = version + 1 WHERE id = ANY($1::uuid[])", pq.Array(ids))
+	return err
+}
+
+// withCredits выполняет изменение связей владельца ownerId в транзакции, неизвестный владелец - sql.ErrNoRows.
+// Титры входят в агрегаты обеих сторон, поэтому при изменении растет версия владельца и затронутых записей
+func withCredits(ctx context.Context, db *sql.DB, by string, ownerId string, change func(tx querier) error) error {
+	return inTx(ctx, db, func(ctx context.Context) error {
+		tx := conn(ctx, db)
+		ownerTable, linkedTable := creditTables(by)
+		if err := liveRowExists(ctx, tx, ownerTable, ownerId); err != nil {
+			return err
+		}
+		before, err := creditRows(ctx, tx, by, ownerId)
+		if err != nil {
+			return err
+		}
+		if err = change(tx); err != nil {
+			return err
+		}
+		after, err := creditRows(ctx, tx, by, ownerId)
+		if err != nil {
+			return err
+		}
+
+		changed := make([]string, 0, len(before)+len(after))
+		for id, rows := range before {
+			if after[id] != rows {
+				changed = append(changed, id)
+			}
+		}
+		for id := range after {
+			if _, ok := before[id]; !ok {
+				changed = append(changed, id)
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		if err = bumpVersions(ctx, tx, ownerTable, []string{ownerId}); err != nil {
+			return err
+		}
+		return bumpVersions(ctx, tx, linkedTable, changed)
+	})
+}
+
+// addCredits добавляет связи не трогая существующие
+func addCredits(ctx context.Context, db *sql.DB, by string, ownerId string, credits []*model.Credit) error {
+	return withCredits(ctx, db, by, ownerId, func(tx querier) error {
+		return insertCredits(ctx, tx, by, ownerId, credits)
+	})
+}
+
+// replaceCredits заменяет все связи владельца на credits, пустой список убирает все связи
+func replaceCredits(ctx context.Context, db *sql.DB, by string, ownerId string, credits []*model.Credit) error {
+	return withCredits(ctx, db, by, ownerId, func(tx querier) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM actor_film WHERE "+by+" = $1", ownerId); err != nil {
+			return err
+		}
+		return insertCredits(ctx, tx, by, ownerId, credits)
+	})
+}
+
+// removeCredits убирает все роли между владельцем и linkedIds, отсутствующая связь не ошибка
+func removeCredits(ctx context.Context, db *sql.DB, by string, ownerId string, linkedIds []string) error {
+	return withCredits(ctx, db, by, ownerId, func(tx querier) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM actor_film WHERE "+by+" = $1 AND "+linkedColumn(by)+" = ANY($2::uuid[])", ownerId, pq.Array(linkedIds))
+		return err
+	})
+}
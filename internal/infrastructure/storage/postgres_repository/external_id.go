@@ -0,0 +1,97 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	"github.com/lib/pq"
+)
+
+type externalIdRepository struct {
+	db *sql.DB
+}
+
+func (e externalIdRepository) FilmIds(ctx context.Context, source string, externalIds ...string) (map[string]string, error) {
+	return e.ids(ctx, "film_external_ids", "film_id", "films", source, externalIds)
+}
+
+func (e externalIdRepository) GetFilm(ctx context.Context, filmId string) (map[string]string, error) {
+	return e.get(ctx, "film_external_ids", "film_id", filmId)
+}
+
+func (e externalIdRepository) SetFilm(ctx context.Context, filmId string, ids map[string]string) error {
+	return e.set(ctx, "film_external_ids", "film_id", "films", filmId, ids)
+}
+
+func (e externalIdRepository) ActorIds(ctx context.Context, source string, externalIds ...string) (map[string]string, error) {
+	return e.ids(ctx, "actor_external_ids", "actor_id", "actors", source, externalIds)
+}
+
+func (e externalIdRepository) GetActor(ctx context.Context, actorId string) (map[string]string, error) {
+	return e.get(ctx, "actor_external_ids", "actor_id", actorId)
+}
+
+func (e externalIdRepository) SetActor(ctx context.Context, actorId string, ids map[string]string) error {
+	return e.set(ctx, "actor_external_ids", "actor_id", "actors", actorId, ids)
+}
+
+// ids внешний идентификатор -> id каталога, записи из корзины идентификаторы не занимают
+func (e externalIdRepository) ids(ctx context.Context, table string, by string, owners string, source string, externalIds []string) (map[string]string, error) {
+	query := "SELECT e.external_id, e." + by + " FROM " + table + " e JOIN " + owners + " o ON o.id = e." + by + " AND o." + liveSql +
+		" WHERE e.source = $1 AND e.external_id = ANY($2::text[])"
+	return e.pairs(ctx, query, source, pq.Array(externalIds))
+}
+
+// get источник -> идентификатор записи id
+func (e externalIdRepository) get(ctx context.Context, table string, by string, id string) (map[string]string, error) {
+	return e.pairs(ctx, "SELECT source, external_id FROM "+table+" WHERE "+by+" = $1", id)
+}
+
+func (e externalIdRepository) pairs(ctx context.Context, query string, args ...any) (map[string]string, error) {
+	result := make(map[string]string, 4)
+	err := eachRow(ctx, conn(ctx, e.db), query, args, func(row rowScanner) error {
+		var key, value string
+		if err := row.Scan(&key, &value); err != nil {
+			return err
+		}
+		result[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// set заменяет идентификаторы записи id в источниках ids. Идентификатор записи из корзины переходит к id,
+// идентификатор живой записи не отбирается: его PRIMARY KEY дает ErrExternalIdConflict, в том числе при гонке
+func (e externalIdRepository) set(ctx context.Context, table string, by string, owners string, id string, ids map[string]string) error {
+	return inTx(ctx, e.db, func(ctx context.Context) error {
+		tx := conn(ctx, e.db)
+		for source, externalId := range ids {
+			_, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE source = $1 AND "+by+" = $2", source, id)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE source = $1 AND external_id = $2 AND "+by+
+				" IN (SELECT id FROM "+owners+" WHERE deleted_at IS NOT NULL)", source, externalId)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, "INSERT INTO "+table+" (source, external_id, "+by+") VALUES ($1, $2, $3)", source, externalId, id)
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: %s %s", repository.ErrExternalIdConflict, source, externalId)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func NewExternalIdRepository(db *sql.DB) repository.ExternalIdRepository {
+	return &externalIdRepository{db: db}
+}
@@ -3,40 +3,79 @@ package postgresRepository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/lib/pq"
 	"slices"
+	"time"
 )
 
 type filmRepository struct {
 	db *sql.DB
+	// rate колонка оценки по политике honor_manual_rate, см. filmRateColumn
+	rate string
 }
 
 func (f filmRepository) Create(ctx context.Context, aggregate *aggregate.FilmAggregate) (*aggregate.FilmAggregate, error) {
-	query := "INSERT INTO films (id, name, description, release_date, rate) VALUES ($1, $2, $3, $4, $5) RETURNING id, name, description, release_date, rate"
-	stmt, err := f.db.PrepareContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer func(stmt *sql.Stmt) {
-		_ = stmt.Close()
-	}(stmt)
+	err := inTx(ctx, f.db, func(ctx context.Context) error {
+		tx := conn(ctx, f.db)
+		film := aggregate.Film
+		err := tx.QueryRowContext(ctx, `INSERT INTO films (id, name, description, release_date, manual_rate, rate)
+			VALUES ($1, $2, $3, $4, $5, COALESCE($5::numeric, 0))
+			RETURNING id, name, description, release_date, `+f.rate+`, manual_rate, vote_count, version`,
+			film.Id, film.Name, film.Description, film.ReleaseDate, film.ManualRate,
+		).Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate, &film.ManualRate, &film.VoteCount, &film.Version)
+		if err != nil {
+			return err
+		}
 
-	film := aggregate.Film
-	err = stmt.QueryRowContext(ctx, film.Id, film.Name, film.Description, film.ReleaseDate, film.Rate).Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate)
+		for _, actor := range aggregate.Actors {
+			_, err = tx.ExecContext(ctx, "INSERT INTO actors (id, name, gender, birthday) VALUES ($1, $2, $3, $4)",
+				actor.Id, actor.Name, actor.Gender, actor.Birthday)
+			if err != nil {
+				return err
+			}
+		}
+		if err = insertCredits(ctx, tx, creditsByFilm, film.Id, aggregate.Credits); err != nil {
+			return err
+		}
+		if err = bumpVersions(ctx, tx, "actors", existingActorIds(aggregate)); err != nil {
+			return err
+		}
+
+		aggregate.Film = film
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	aggregate.Film = film
 	return aggregate, nil
 }
 
+// existingActorIds актеры из титров нового фильма, созданные раньше него: их титры изменились
+func existingActorIds(aggregate *aggregate.FilmAggregate) []string {
+	ids := make([]string, 0, len(aggregate.Credits))
+	for _, credit := range aggregate.Credits {
+		created := slices.ContainsFunc(aggregate.Actors, func(actor *model.Actor) bool {
+			return actor.Id == credit.ActorId
+		})
+		if !created && !slices.Contains(ids, credit.ActorId) {
+			ids = append(ids, credit.ActorId)
+		}
+	}
+	return ids
+}
+
 func (f filmRepository) Update(ctx context.Context, aggregate *aggregate.FilmAggregate) (*aggregate.FilmAggregate, error) {
-	query := "UPDATE films SET name = $1, description = $2, release_date = $3, rate = $4 WHERE id = $5 RETURNING id, name, description, release_date, rate"
-	stmt, err := f.db.PrepareContext(ctx, query)
+	// без ручной оценки rate берется из оценок пользователей, user_rate и vote_count меняет только ratingRepository
+	query := `UPDATE films SET name = $1, description = $2, release_date = $3, manual_rate = $4,
+		rate = COALESCE($4::numeric, user_rate), version = version + 1
+		WHERE id = $5 AND deleted_at IS NULL AND ($6::int = 0 OR version = $6)
+		RETURNING id, name, description, release_date, ` + f.rate + `, manual_rate, vote_count, version`
+	stmt, err := conn(ctx, f.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -45,9 +84,9 @@ func (f filmRepository) Update(ctx context.Context, aggregate *aggregate.FilmAgg
 	}(stmt)
 
 	film := aggregate.Film
-	err = stmt.QueryRowContext(ctx, film.Name, film.Description, film.ReleaseDate, film.Rate, film.Id).Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate)
+	err = stmt.QueryRowContext(ctx, film.Name, film.Description, film.ReleaseDate, film.ManualRate, film.Id, film.Version).Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate, &film.ManualRate, &film.VoteCount, &film.Version)
 	if err != nil {
-		return nil, err
+		return nil, versionError(ctx, conn(ctx, f.db), "films", film.Id, err)
 	}
 
 	aggregate.Film = film
@@ -55,58 +94,116 @@ func (f filmRepository) Update(ctx context.Context, aggregate *aggregate.FilmAgg
 }
 
 func (f filmRepository) Delete(ctx context.Context, id string) error {
-	_, err := f.db.ExecContext(ctx, "DELETE FROM actor_film WHERE film_id = $1", id)
+	return softDelete(ctx, f.db, creditsByFilm, id)
+}
+
+func (f filmRepository) GetDeleted(ctx context.Context, query domainQuery.PageQuery) ([]*aggregate.FilmAggregate, int, error) {
+	rows, err := conn(ctx, f.db).QueryContext(ctx, `
+		SELECT id, name, description, release_date, `+f.rate+`, manual_rate, vote_count, version, deleted_at
+		FROM films
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id
+		LIMIT $1 OFFSET $2
+	`, query.PageCount, query.PageCount*(query.CurrentPage-1))
 	if err != nil {
-		return err
+		return nil, 0, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	films := make([]*aggregate.FilmAggregate, 0, query.PageCount)
+	for rows.Next() {
+		aggr := &aggregate.FilmAggregate{}
+		err := rows.Scan(&aggr.Film.Id, &aggr.Film.Name, &aggr.Film.Description, &aggr.Film.ReleaseDate, &aggr.Film.Rate, &aggr.Film.ManualRate, &aggr.Film.VoteCount, &aggr.Film.Version, &aggr.Film.DeletedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		films = append(films, aggr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
-	query := "DELETE FROM films WHERE id = $1"
-	_, err = f.db.ExecContext(ctx, query, id)
-	return err
+
+	total := 0
+	if err = conn(ctx, f.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM films WHERE deleted_at IS NOT NULL").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return films, pageCount(total, query.PageCount), nil
+}
+
+func (f filmRepository) Restore(ctx context.Context, id string) error {
+	return restoreDeleted(ctx, f.db, creditsByFilm, id)
+}
+
+func (f filmRepository) Purge(ctx context.Context, id string) error {
+	return purgeOne(ctx, f.db, creditsByFilm, id)
+}
+
+func (f filmRepository) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	return purgeDeleted(ctx, f.db, creditsByFilm, "deleted_at < $1", before)
+}
+
+func (f filmRepository) SetCast(ctx context.Context, filmId string, credits ...*model.Credit) error {
+	return replaceCredits(ctx, f.db, creditsByFilm, filmId, credits)
+}
+
+func (f filmRepository) RemoveActor(ctx context.Context, filmId string, actorIds ...string) error {
+	return removeCredits(ctx, f.db, creditsByFilm, filmId, actorIds)
 }
 
 func (f filmRepository) GetById(ctx context.Context, id string) (*aggregate.FilmAggregate, error) {
-	query := "SELECT id, name, description, release_date, rate FROM films WHERE id = $1"
-	row := f.db.QueryRowContext(ctx, query, id)
+	query := "SELECT id, name, description, release_date, " + f.rate + ", manual_rate, vote_count, version FROM films WHERE id = $1 AND deleted_at IS NULL"
+	row := conn(ctx, f.db).QueryRowContext(ctx, query, id)
 
 	var film model.Film
-	err := row.Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate)
+	err := row.Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate, &film.ManualRate, &film.VoteCount, &film.Version)
 	if err != nil {
 		return nil, err
 	}
 
-	return &aggregate.FilmAggregate{Film: film}, nil
+	result := &aggregate.FilmAggregate{Film: film}
+	if err := f.loadActors(ctx, []*aggregate.FilmAggregate{result}); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
+func (f filmRepository) GetByNameAndRelease(ctx context.Context, name string, releaseDate time.Time) (*aggregate.FilmAggregate, error) {
+	query := "SELECT id FROM films WHERE lower(name) = lower($1) AND release_date = $2 AND deleted_at IS NULL ORDER BY id LIMIT 1"
+	var id string
+	if err := conn(ctx, f.db).QueryRowContext(ctx, query, name, releaseDate).Scan(&id); err != nil {
+		return nil, err
+	}
+	return f.GetById(ctx, id)
+}
+
+// GetByQuery LIMIT/OFFSET применяются к фильмам, связи догружаются отдельными запросами для всей страницы.
+// С курсором вместо OFFSET используется keyset условие и выбирается на один фильм больше
 func (f filmRepository) GetByQuery(ctx context.Context, query domainQuery.FilmRepositoryQuery) ([]*aggregate.FilmAggregate, int, error) {
 	offset := query.PageCount * (query.CurrentPage - 1)
 	limit := query.PageCount
-	field := "f.rate"
-	if query.SortField == "name" {
-		field = "f.name"
-	} else if query.SortField == "released_date" {
-		field = "f.released_date"
+	fetch := limit
+	direction := query.OrderBy
+	if query.Cursor != nil {
+		offset, fetch = 0, limit+1
+		if query.Cursor.Before {
+			direction = reverseDirection(direction)
+		}
 	}
 
 	sqlQuery := `
-        SELECT
-            f.*,
-            a.id AS actor_id,
-            a.name AS actor_name,
-            a.gender AS actor_gender,
-            a.birthday AS actor_birthday
-        FROM films f
-        LEFT JOIN actor_film af ON f.id = af.film_id AND 'actor' = $1
-        LEFT JOIN actors a ON af.actor_id = a.id AND 'actor' = $1
-		ORDER BY ` + field + " " + string(query.OrderBy) + `
-		LIMIT $2 OFFSET $3;
-		`
-
-	conn := "not"
-	if slices.Contains(query.WithConnection, "actor") {
-		conn = "actor"
-	}
+		SELECT f.id, f.name, f.description, f.release_date, f.` + f.rate + `, f.manual_rate, f.vote_count, f.version
+		FROM films f
+		WHERE ` + f.filterSql("f", 5) + ` AND ` + f.keysetSql("f", 3, 13) + `
+		ORDER BY ` + f.orderSql("f", 3, 4) + `
+		LIMIT $1 OFFSET $2
+	`
 
-	rows, err := f.db.QueryContext(ctx, sqlQuery, conn, limit, offset)
+	filterArgs := filmFilterArgs(query.FilmFilter)
+	args := append([]interface{}{fetch, offset, query.SortField, string(direction)}, filterArgs...)
+	args = append(args, filmKeysetArgs(query.Cursor)...)
+	rows, err := conn(ctx, f.db).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -114,59 +211,40 @@ func (f filmRepository) GetByQuery(ctx context.Context, query domainQuery.FilmRe
 		_ = rows.Close()
 	}(rows)
 
-	filmsMap := make(map[string]*aggregate.FilmAggregate, 20)
-	films := make([]*aggregate.FilmAggregate, 0, len(filmsMap))
-
+	films := make([]*aggregate.FilmAggregate, 0, fetch)
 	for rows.Next() {
-		var (
-			film          model.Film
-			actorId       sql.NullString
-			actorName     sql.NullString
-			actorBirthday sql.NullTime
-			actorGender   sql.NullString
-		)
-
-		err := rows.Scan(
-			&film.Id,
-			&film.Name,
-			&film.Description,
-			&film.ReleaseDate,
-			&film.Rate,
-			&actorId,
-			&actorName,
-			&actorGender,
-			&actorBirthday,
-		)
-
+		aggr := &aggregate.FilmAggregate{}
+		err := rows.Scan(&aggr.Film.Id, &aggr.Film.Name, &aggr.Film.Description, &aggr.Film.ReleaseDate, &aggr.Film.Rate, &aggr.Film.ManualRate, &aggr.Film.VoteCount, &aggr.Film.Version)
 		if err != nil {
 			return nil, 0, err
 		}
+		films = append(films, aggr)
+	}
 
-		var aggr *aggregate.FilmAggregate
-		if res, ok := filmsMap[film.Id]; !ok {
-			aggr = &aggregate.FilmAggregate{
-				Film: film,
-			}
-			filmsMap[film.Id] = aggr
-			films = append(films, aggr)
-		} else {
-			aggr = res
-		}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if query.Cursor != nil && query.Cursor.Before {
+		slices.Reverse(films)
+	}
 
-		if actorId.Valid {
-			aggr.Actors = append(aggr.Actors, &model.Actor{Id: actorId.String, Name: actorName.String, Birthday: actorBirthday.Time, Gender: actorGender.String})
+	if slices.Contains(query.WithConnection, "actor") {
+		if err := f.loadActors(ctx, films); err != nil {
+			return nil, 0, err
 		}
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
+	if slices.Contains(query.WithConnection, "genre") {
+		if err := f.loadGenres(ctx, films); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	totalCount := 0
-	err = f.db.QueryRowContext(ctx, `
+	err = conn(ctx, f.db).QueryRowContext(ctx, `
         SELECT COUNT(*)
-        FROM actors
-    `).Scan(&totalCount)
+        FROM films f
+        WHERE `+f.filterSql("f", 1), filterArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -180,18 +258,64 @@ func (f filmRepository) GetByQuery(ctx context.Context, query domainQuery.FilmRe
 	return films, totalCount, nil
 }
 
-func (f filmRepository) SearchByNameAndActorName(ctx context.Context, searchValue string) ([]*aggregate.FilmAggregate, int, error) {
+func (f filmRepository) Each(ctx context.Context, query domainQuery.FilmRepositoryQuery, fn func(film *model.Film) error) error {
 	sqlQuery := `
-		SELECT DISTINCT ON(films.id) films.*
-		FROM films
-		LEFT JOIN actor_film af ON films.id = af.film_id
-		LEFT JOIN actors a ON af.actor_id = a.id
-		WHERE (films.name LIKE '%' || $1 || '%' OR a.name LIKE '%' || $1 || '%')
-		ORDER BY films.id, films.rate ASC
-		LIMIT 20 OFFSET 0;
+		SELECT f.id, f.name, f.description, f.release_date, f.` + f.rate + `, f.manual_rate, f.vote_count, f.version
+		FROM films f
+		WHERE ` + f.filterSql("f", 3) + `
+		ORDER BY ` + f.orderSql("f", 1, 2)
+
+	args := append([]interface{}{query.SortField, string(query.OrderBy)}, filmFilterArgs(query.FilmFilter)...)
+	return eachRow(ctx, conn(ctx, f.db), sqlQuery, args, func(row rowScanner) error {
+		var film model.Film
+		if err := row.Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate, &film.ManualRate, &film.VoteCount, &film.Version); err != nil {
+			return err
+		}
+		return fn(&film)
+	})
+}
+
+func (f filmRepository) EachCredit(ctx context.Context, query domainQuery.FilmRepositoryQuery, fn func(credit *model.CreditDetails) error) error {
+	sqlQuery := `
+		SELECT af.actor_id, af.film_id, af.role, af.character, af.billing_order, f.name, f.release_date, a.name, a.birthday
+		FROM films f
+		JOIN actor_film af ON af.film_id = f.id
+		JOIN actors a ON a.id = af.actor_id AND a.deleted_at IS NULL
+		WHERE ` + f.filterSql("f", 3) + `
+		ORDER BY ` + f.orderSql("f", 1, 2) + `, af.billing_order, af.role`
+
+	args := append([]interface{}{query.SortField, string(query.OrderBy)}, filmFilterArgs(query.FilmFilter)...)
+	return eachRow(ctx, conn(ctx, f.db), sqlQuery, args, func(row rowScanner) error {
+		var credit model.CreditDetails
+		err := row.Scan(&credit.ActorId, &credit.FilmId, &credit.Role, &credit.Character, &credit.BillingOrder,
+			&credit.FilmName, &credit.FilmRelease, &credit.ActorName, &credit.ActorBirthday)
+		if err != nil {
+			return err
+		}
+		return fn(&credit)
+	})
+}
+
+func (f filmRepository) SearchByNameAndActorName(ctx context.Context, query domainQuery.FilmSearchQuery) ([]*aggregate.FilmAggregate, int, error) {
+	// совпадение в названии (полнотекстовое или по триграммам) поднимает фильм выше остальных, внутри групп порядок по рангу
+	sqlQuery := `
+		WITH q AS (SELECT ` + searchTsQuerySql + ` AS query)
+		SELECT
+			f.id, f.name, f.description, f.release_date, f.` + f.rate + `, f.manual_rate, f.vote_count, f.version,
+			ts_rank(f.search_vector, q.query) + word_similarity($1, f.name) AS rank,
+			((to_tsvector('russian', f.name) || to_tsvector('english', f.name)) @@ q.query OR $1 <% f.name) AS title_match,
+			ts_headline('russian', f.name, q.query, 'StartSel=<b>, StopSel=</b>, HighlightAll=true') AS name_headline,
+			CASE WHEN f.description IS NULL THEN NULL
+				ELSE ts_headline('russian', f.description, q.query, 'StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15')
+			END AS description_headline
+		FROM films f, q
+		WHERE f.deleted_at IS NULL AND ` + searchMatchSql + `
+		ORDER BY title_match DESC, rank DESC, f.name, f.id
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := f.db.QueryContext(ctx, sqlQuery, searchValue)
+	offset := query.PageCount * (query.CurrentPage - 1)
+	rows, err := conn(ctx, f.db).QueryContext(ctx, sqlQuery, query.Value, query.PageCount, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -199,11 +323,14 @@ func (f filmRepository) SearchByNameAndActorName(ctx context.Context, searchValu
 		_ = rows.Close()
 	}(rows)
 
-	films := make([]*aggregate.FilmAggregate, 0, 20)
+	films := make([]*aggregate.FilmAggregate, 0, query.PageCount)
 
 	for rows.Next() {
-		aggr := &aggregate.FilmAggregate{}
-		err := rows.Scan(&aggr.Film.Id, &aggr.Film.Name, &aggr.Film.Description, &aggr.Film.ReleaseDate, &aggr.Film.Rate)
+		aggr := &aggregate.FilmAggregate{Search: &model.FilmSearchMatch{}}
+		err := rows.Scan(
+			&aggr.Film.Id, &aggr.Film.Name, &aggr.Film.Description, &aggr.Film.ReleaseDate, &aggr.Film.Rate, &aggr.Film.ManualRate, &aggr.Film.VoteCount, &aggr.Film.Version,
+			&aggr.Search.Rank, &aggr.Search.TitleMatch, &aggr.Search.Name, &aggr.Search.Description,
+		)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -215,9 +342,223 @@ func (f filmRepository) SearchByNameAndActorName(ctx context.Context, searchValu
 		return nil, 0, err
 	}
 
-	return films, 1, nil
+	total := 0
+	err = conn(ctx, f.db).QueryRowContext(ctx, `
+		WITH q AS (SELECT `+searchTsQuerySql+` AS query)
+		SELECT COUNT(*) FROM films f, q WHERE f.deleted_at IS NULL AND `+searchMatchSql, query.Value).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return films, total, nil
+}
+
+// searchTsQuerySql запрос из пользовательского ввода ($1) сразу по русской и английской конфигурации
+const searchTsQuerySql = `websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1)`
+
+// searchMatchSql фильм найден полнотекстово или с опечаткой по триграммам в названии либо имени актера
+const searchMatchSql = `(f.search_vector @@ q.query
+		OR $1 <% f.name
+		OR EXISTS (SELECT 1 FROM actor_film af JOIN actors a ON a.id = af.actor_id AND a.deleted_at IS NULL WHERE af.film_id = f.id AND $1 <% a.name))`
+
+// loadActors догружает актеров и титры одним запросом на каждое для всех переданных фильмов
+func (f filmRepository) loadActors(ctx context.Context, films []*aggregate.FilmAggregate) error {
+	if len(films) == 0 {
+		return nil
+	}
+	filmsMap := make(map[string]*aggregate.FilmAggregate, len(films))
+	ids := make([]string, 0, len(films))
+	for _, film := range films {
+		filmsMap[film.Film.Id] = film
+		ids = append(ids, film.Film.Id)
+	}
+
+	rows, err := conn(ctx, f.db).QueryContext(ctx, `
+		SELECT af.film_id, a.id, a.name, a.gender, a.birthday, a.version
+		FROM (SELECT DISTINCT actor_id, film_id FROM actor_film WHERE film_id = ANY($1::uuid[])) af
+		JOIN actors a ON af.actor_id = a.id AND a.deleted_at IS NULL
+		ORDER BY a.id
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	for rows.Next() {
+		var (
+			filmId string
+			actor  model.Actor
+		)
+		if err := rows.Scan(&filmId, &actor.Id, &actor.Name, &actor.Gender, &actor.Birthday, &actor.Version); err != nil {
+			return err
+		}
+		if film, ok := filmsMap[filmId]; ok {
+			film.Actors = append(film.Actors, &actor)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	credits, err := loadCredits(ctx, f.db, creditsByFilm, ids)
+	if err != nil {
+		return err
+	}
+	for _, film := range films {
+		film.SetCredits(credits[film.Film.Id])
+	}
+	return nil
+}
+
+// loadGenres догружает жанры одним запросом для всех переданных фильмов
+func (f filmRepository) loadGenres(ctx context.Context, films []*aggregate.FilmAggregate) error {
+	if len(films) == 0 {
+		return nil
+	}
+	filmsMap := make(map[string]*aggregate.FilmAggregate, len(films))
+	ids := make([]string, 0, len(films))
+	for _, film := range films {
+		filmsMap[film.Film.Id] = film
+		ids = append(ids, film.Film.Id)
+	}
+
+	rows, err := conn(ctx, f.db).QueryContext(ctx, `
+		SELECT fg.film_id, g.id, g.name
+		FROM film_genre fg
+		JOIN genres g ON fg.genre_id = g.id
+		WHERE fg.film_id = ANY($1::uuid[])
+		ORDER BY g.name
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	for rows.Next() {
+		var (
+			filmId string
+			genre  model.Genre
+		)
+		if err := rows.Scan(&filmId, &genre.Id, &genre.Name); err != nil {
+			return err
+		}
+		if film, ok := filmsMap[filmId]; ok {
+			film.Genres = append(film.Genres, &genre)
+		}
+	}
+
+	return rows.Err()
+}
+
+// orderSql сортировка по параметрам запроса: fieldArg - поле (name, release_date, rate), directionArg - ASC/DESC.
+// Значения не подставляются в текст запроса, неизвестное поле сортирует по rate
+func (f filmRepository) orderSql(alias string, fieldArg int, directionArg int) string {
+	return fmt.Sprintf(`CASE WHEN $%[3]d = 'DESC' THEN NULL WHEN $%[2]d = 'name' THEN %[1]s.name END ASC,
+		CASE WHEN $%[3]d <> 'DESC' THEN NULL WHEN $%[2]d = 'name' THEN %[1]s.name END DESC,
+		CASE WHEN $%[3]d = 'DESC' THEN NULL WHEN $%[2]d = 'release_date' THEN %[1]s.release_date END ASC,
+		CASE WHEN $%[3]d <> 'DESC' THEN NULL WHEN $%[2]d = 'release_date' THEN %[1]s.release_date END DESC,
+		CASE WHEN $%[3]d = 'DESC' OR $%[2]d IN ('name', 'release_date') THEN NULL ELSE %[1]s.%[4]s END ASC,
+		CASE WHEN $%[3]d <> 'DESC' OR $%[2]d IN ('name', 'release_date') THEN NULL ELSE %[1]s.%[4]s END DESC,
+		CASE WHEN $%[3]d = 'DESC' THEN %[1]s.id END DESC,
+		%[1]s.id`, alias, fieldArg, directionArg, f.rate)
+}
+
+// keysetSql условие keyset пагинации по паре (поле сортировки, id), согласованное с orderSql.
+// firstArg - номер первого из пяти параметров filmKeysetArgs
+func (f filmRepository) keysetSql(alias string, fieldArg int, firstArg int) string {
+	compare := func(column string, valueArg string) string {
+		return fmt.Sprintf(`(($%[3]d AND (%[1]s, %[2]s.id) > (%[4]s, $%[5]d::uuid)) OR (NOT $%[3]d AND (%[1]s, %[2]s.id) < (%[4]s, $%[5]d::uuid)))`,
+			column, alias, firstArg+1, valueArg, firstArg)
+	}
+	return fmt.Sprintf(`($%[2]d::uuid IS NULL
+		OR ($%[1]d = 'name' AND %[3]s)
+		OR ($%[1]d = 'release_date' AND %[4]s)
+		OR ($%[1]d NOT IN ('name', 'release_date') AND %[5]s))`,
+		fieldArg, firstArg,
+		compare(alias+".name", fmt.Sprintf("$%d::text", firstArg+2)),
+		compare(alias+".release_date", fmt.Sprintf("$%d::date", firstArg+3)),
+		compare(alias+"."+f.rate, fmt.Sprintf("$%d::numeric", firstArg+4)))
+}
+
+// filmKeysetArgs id курсора, направление сравнения и значение ключа в параметре нужного типа
+func filmKeysetArgs(cursor *domainQuery.Cursor) []interface{} {
+	if cursor == nil {
+		return []interface{}{nil, true, nil, nil, nil}
+	}
+	args := []interface{}{cursor.Id, cursor.Greater(), nil, nil, nil}
+	switch cursor.SortField {
+	case "name":
+		args[2] = cursor.Value
+	case "release_date":
+		args[3] = cursor.Value
+	default:
+		args[4] = cursor.Value
+	}
+	return args
+}
+
+// filterSql условия FilmFilter через AND, firstArg - номер первого из восьми параметров filmFilterArgs.
+// Фильмы из корзины исключаются всегда
+func (f filmRepository) filterSql(alias string, firstArg int) string {
+	return alias + ".deleted_at IS NULL AND " + genreFilterSql(alias, firstArg, firstArg+1) + fmt.Sprintf(`
+		AND ($%[2]d::date IS NULL OR %[1]s.release_date >= $%[2]d)
+		AND ($%[3]d::date IS NULL OR %[1]s.release_date <= $%[3]d)
+		AND ($%[4]d::numeric IS NULL OR %[1]s.%[6]s >= $%[4]d)
+		AND ($%[5]d::numeric IS NULL OR %[1]s.%[6]s <= $%[5]d)`,
+		alias, firstArg+2, firstArg+3, firstArg+4, firstArg+5, f.rate) + " AND " + actorInFilmFilterSql(alias, firstArg+6, firstArg+7)
+}
+
+func filmFilterArgs(filter domainQuery.FilmFilter) []interface{} {
+	genres, genreMatch := matchFilterArgs(filter.Genres, filter.GenreMatch)
+	actors, actorMatch := matchFilterArgs(filter.Actors, filter.ActorMatch)
+	return []interface{}{genres, genreMatch, filter.ReleaseDateFrom, filter.ReleaseDateTo, filter.RateFrom, filter.RateTo, actors, actorMatch}
+}
+
+// genreFilterSql условие фильтра по жанрам, arrayArg - номер параметра с массивом id, matchArg - any/all
+func genreFilterSql(alias string, arrayArg int, matchArg int) string {
+	return fmt.Sprintf(`($%[2]d::uuid[] IS NULL
+		OR ($%[3]d = 'any' AND EXISTS (SELECT 1 FROM film_genre fg WHERE fg.film_id = %[1]s.id AND fg.genre_id = ANY($%[2]d::uuid[])))
+		OR ($%[3]d = 'all' AND (SELECT COUNT(*) FROM film_genre fg WHERE fg.film_id = %[1]s.id AND fg.genre_id = ANY($%[2]d::uuid[])) = cardinality($%[2]d::uuid[])))`,
+		alias, arrayArg, matchArg)
+}
+
+// actorInFilmFilterSql условие фильтра по актерам, как genreFilterSql
+func actorInFilmFilterSql(alias string, arrayArg int, matchArg int) string {
+	return fmt.Sprintf(`($%[2]d::uuid[] IS NULL
+		OR ($%[3]d = 'any' AND EXISTS (SELECT 1 FROM actor_film fa WHERE fa.film_id = %[1]s.id AND fa.actor_id = ANY($%[2]d::uuid[])))
+		OR ($%[3]d = 'all' AND (SELECT COUNT(DISTINCT fa.actor_id) FROM actor_film fa WHERE fa.film_id = %[1]s.id AND fa.actor_id = ANY($%[2]d::uuid[])) = cardinality($%[2]d::uuid[])))`,
+		alias, arrayArg, matchArg)
+}
+
+// matchFilterArgs уникальные id (nil если фильтра нет) и режим any/all
+func matchFilterArgs(ids []string, mode domainQuery.MatchMode) (interface{}, string) {
+	match := string(mode)
+	if match == "" {
+		match = string(domainQuery.MatchAny)
+	}
+	if len(ids) == 0 {
+		return nil, match
+	}
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return pq.Array(unique), match
+}
+
+func reverseDirection(direction domainQuery.OrderDirection) domainQuery.OrderDirection {
+	if direction == domainQuery.Desc {
+		return domainQuery.Asc
+	}
+	return domainQuery.Desc
 }
 
-func NewFilmRepository(db *sql.DB) repository.FilmRepository {
-	return &filmRepository{db: db}
+func NewFilmRepository(db *sql.DB, honorManualRate bool) repository.FilmRepository {
+	return &filmRepository{db: db, rate: filmRateColumn(honorManualRate)}
 }
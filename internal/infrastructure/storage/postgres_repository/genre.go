@@ -0,0 +1,153 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+)
+
+type genreRepository struct {
+	db *sql.DB
+}
+
+func (g genreRepository) Create(ctx context.Context, genre *model.Genre) (*model.Genre, error) {
+	query := "INSERT INTO genres (id, name) VALUES ($1, $2) RETURNING id, name"
+	stmt, err := conn(ctx, g.db).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func(stmt *sql.Stmt) {
+		_ = stmt.Close()
+	}(stmt)
+
+	err = stmt.QueryRowContext(ctx, genre.Id, genre.Name).Scan(&genre.Id, &genre.Name)
+	if isUniqueViolation(err) {
+		return nil, repository.ErrGenreNameExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return genre, nil
+}
+
+func (g genreRepository) Update(ctx context.Context, genre *model.Genre) (*model.Genre, error) {
+	query := "UPDATE genres SET name = $1 WHERE id = $2 RETURNING id, name"
+	stmt, err := conn(ctx, g.db).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func(stmt *sql.Stmt) {
+		_ = stmt.Close()
+	}(stmt)
+
+	err = stmt.QueryRowContext(ctx, genre.Name, genre.Id).Scan(&genre.Id, &genre.Name)
+	if isUniqueViolation(err) {
+		return nil, repository.ErrGenreNameExist
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return genre, nil
+}
+
+func (g genreRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, g.db).ExecContext(ctx, "DELETE FROM genres WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (g genreRepository) GetById(ctx context.Context, id string) (*model.Genre, error) {
+	query := "SELECT id, name FROM genres WHERE id = $1"
+	row := conn(ctx, g.db).QueryRowContext(ctx, query, id)
+
+	var genre model.Genre
+	err := row.Scan(&genre.Id, &genre.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &genre, nil
+}
+
+func (g genreRepository) GetAll(ctx context.Context) ([]*model.Genre, error) {
+	rows, err := conn(ctx, g.db).QueryContext(ctx, "SELECT id, name FROM genres ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	genres := make([]*model.Genre, 0, 20)
+	for rows.Next() {
+		var genre model.Genre
+		if err := rows.Scan(&genre.Id, &genre.Name); err != nil {
+			return nil, err
+		}
+		genres = append(genres, &genre)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+func (g genreRepository) HasByName(ctx context.Context, name string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM genres WHERE name = $1)"
+	var exists bool
+	err := conn(ctx, g.db).QueryRowContext(ctx, query, name).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (g genreRepository) AddFilm(ctx context.Context, genreId string, filmIds ...string) error {
+	return inTx(ctx, g.db, func(ctx context.Context) error {
+		tx := conn(ctx, g.db)
+		if err := rowExists(ctx, tx, "genres", genreId); err != nil {
+			return err
+		}
+
+		for _, filmId := range filmIds {
+			if err := liveRowExists(ctx, tx, "films", filmId); err != nil {
+				return err
+			}
+
+			_, err := tx.ExecContext(ctx, "INSERT INTO film_genre (film_id, genre_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", filmId, genreId)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (g genreRepository) RemoveFilm(ctx context.Context, genreId string, filmIds ...string) error {
+	for _, filmId := range filmIds {
+		_, err := conn(ctx, g.db).ExecContext(ctx, "DELETE FROM film_genre WHERE film_id = $1 AND genre_id = $2", filmId, genreId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewGenreRepository(db *sql.DB) repository.GenreRepository {
+	return &genreRepository{db: db}
+}
@@ -0,0 +1,40 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+)
+
+type imdbRepository struct {
+	db *sql.DB
+}
+
+func (i imdbRepository) GetProgress(ctx context.Context) (*model.ImdbProgress, error) {
+	var progress model.ImdbProgress
+	err := conn(ctx, i.db).QueryRowContext(ctx, "SELECT fingerprint, stage, done, updated_at FROM imdb_import_progress").
+		Scan(&progress.Fingerprint, &progress.Stage, &progress.Done, &progress.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (i imdbRepository) SaveProgress(ctx context.Context, progress *model.ImdbProgress) error {
+	_, err := conn(ctx, i.db).ExecContext(ctx, `
+		INSERT INTO imdb_import_progress (fingerprint, stage, done) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, stage = EXCLUDED.stage, done = EXCLUDED.done, updated_at = now()
+	`, progress.Fingerprint, progress.Stage, progress.Done)
+	return err
+}
+
+func (i imdbRepository) DeleteProgress(ctx context.Context) error {
+	_, err := conn(ctx, i.db).ExecContext(ctx, "DELETE FROM imdb_import_progress")
+	return err
+}
+
+func NewImdbRepository(db *sql.DB) repository.ImdbRepository {
+	return &imdbRepository{db: db}
+}
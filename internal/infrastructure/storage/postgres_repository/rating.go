@@ -0,0 +1,117 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+)
+
+type ratingRepository struct {
+	db *sql.DB
+	// rate колонка оценки фильма, см. filmRateColumn
+	rate string
+}
+
+func (r ratingRepository) Create(ctx context.Context, rating *model.Rating) (*model.Film, error) {
+	return r.inFilmTx(ctx, rating.FilmId, func(tx querier) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO ratings (user_id, film_id, score) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, film_id) DO NOTHING
+			RETURNING created_at, updated_at
+		`, rating.UserId, rating.FilmId, rating.Score).Scan(&rating.CreatedAt, &rating.UpdatedAt)
+		// фильм уже заблокирован, поэтому пустой результат значит только существующую оценку
+		if err == sql.ErrNoRows {
+			return repository.ErrRatingExist
+		}
+		return err
+	})
+}
+
+func (r ratingRepository) Update(ctx context.Context, rating *model.Rating) (*model.Film, error) {
+	return r.inFilmTx(ctx, rating.FilmId, func(tx querier) error {
+		return tx.QueryRowContext(ctx, `
+			UPDATE ratings SET score = $1, updated_at = now() WHERE user_id = $2 AND film_id = $3
+			RETURNING created_at, updated_at
+		`, rating.Score, rating.UserId, rating.FilmId).Scan(&rating.CreatedAt, &rating.UpdatedAt)
+	})
+}
+
+func (r ratingRepository) Delete(ctx context.Context, userId string, filmId string) (*model.Film, error) {
+	return r.inFilmTx(ctx, filmId, func(tx querier) error {
+		result, err := tx.ExecContext(ctx, "DELETE FROM ratings WHERE user_id = $1 AND film_id = $2", userId, filmId)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+func (r ratingRepository) Get(ctx context.Context, userId string, filmId string) (*model.Rating, error) {
+	query := "SELECT user_id, film_id, score, created_at, updated_at FROM ratings WHERE user_id = $1 AND film_id = $2"
+	var rating model.Rating
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userId, filmId).Scan(&rating.UserId, &rating.FilmId, &rating.Score, &rating.CreatedAt, &rating.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// inFilmTx блокирует строку фильма, выполняет fn и пересчитывает rate и vote_count в той же транзакции.
+// version не меняется: оценки пользователей не должны давать 412 на правки админа, ETag учитывает их отдельно
+func (r ratingRepository) inFilmTx(ctx context.Context, filmId string, fn func(tx querier) error) (*model.Film, error) {
+	film := &model.Film{}
+	err := inTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		var lockedId string
+		err := tx.QueryRowContext(ctx, "SELECT id FROM films WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", filmId).Scan(&lockedId)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(tx); err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx, `
+			UPDATE films SET
+				vote_count = (SELECT COUNT(*) FROM ratings WHERE film_id = $1),
+				user_rate = COALESCE(`+avgScoreSql("$1")+`, 0),
+				rate = COALESCE(manual_rate, `+avgScoreSql("$1")+`, 0)
+			WHERE id = $1
+			RETURNING id, name, description, release_date, `+r.rate+`, manual_rate, vote_count, version
+		`, filmId).Scan(&film.Id, &film.Name, &film.Description, &film.ReleaseDate, &film.Rate, &film.ManualRate, &film.VoteCount, &film.Version)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return film, nil
+}
+
+// filmRateColumn колонка films с оценкой по политике honor_manual_rate: rate учитывает ручную оценку админа,
+// user_rate - только оценки пользователей. Обе поддерживаются всегда, поэтому ручные оценки при выключенной политике
+// сохраняются и снова учитываются после ее включения
+func filmRateColumn(honorManualRate bool) string {
+	if honorManualRate {
+		return "rate"
+	}
+	return "user_rate"
+}
+
+// avgScoreSql подзапрос средней оценки пользователей, округленной как колонка rate
+func avgScoreSql(filmIdArg string) string {
+	return fmt.Sprintf("(SELECT ROUND(AVG(score)::numeric, 1) FROM ratings WHERE film_id = %s)", filmIdArg)
+}
+
+func NewRatingRepository(db *sql.DB, honorManualRate bool) repository.RatingRepository {
+	return &ratingRepository{db: db, rate: filmRateColumn(honorManualRate)}
+}
@@ -0,0 +1,202 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/lib/pq"
+)
+
+// reviewSelectSql рецензия вместе с оценкой автора, порядок колонок соответствует scanReview
+const reviewSelectSql = `
+	SELECT
+		rv.id, rv.film_id, rv.user_id, rv.title, rv.body, rv.spoiler, rv.status, rv.moderation_note, rv.created_at, rv.updated_at,
+		r.score, r.created_at, r.updated_at
+	FROM reviews rv
+	LEFT JOIN ratings r ON r.user_id = rv.user_id AND r.film_id = rv.film_id
+`
+
+type reviewRepository struct {
+	db *sql.DB
+}
+
+func (r reviewRepository) Create(ctx context.Context, aggregate *aggregate.ReviewAggregate) (*aggregate.ReviewAggregate, error) {
+	query := `
+		INSERT INTO reviews (id, film_id, user_id, title, body, spoiler, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	review := aggregate.Review
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, review.Id, review.FilmId, review.UserId, review.Title, review.Body, review.Spoiler, review.Status)
+	if isUniqueViolation(err) {
+		return nil, repository.ErrReviewExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetById(ctx, review.Id)
+}
+
+func (r reviewRepository) Update(ctx context.Context, aggregate *aggregate.ReviewAggregate) (*aggregate.ReviewAggregate, error) {
+	query := `
+		UPDATE reviews SET title = $1, body = $2, spoiler = $3, status = $4, moderation_note = $5, updated_at = now()
+		WHERE id = $6
+	`
+	review := aggregate.Review
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, review.Title, review.Body, review.Spoiler, review.Status, review.ModerationNote, review.Id)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return r.GetById(ctx, review.Id)
+}
+
+func (r reviewRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM reviews WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r reviewRepository) GetById(ctx context.Context, id string) (*aggregate.ReviewAggregate, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, reviewSelectSql+" WHERE rv.id = $1", id)
+	return scanReview(row)
+}
+
+func (r reviewRepository) GetByQuery(ctx context.Context, query domainQuery.ReviewRepositoryQuery) ([]*aggregate.ReviewAggregate, int, error) {
+	offset := query.PageCount * (query.CurrentPage - 1)
+	limit := query.PageCount
+	filmId, statuses := reviewFilterArgs(query)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, reviewSelectSql+`
+		WHERE ($1::uuid IS NULL OR rv.film_id = $1)
+		AND ($2::text[] IS NULL OR rv.status = ANY($2::text[]))
+		ORDER BY rv.created_at DESC, rv.id
+		LIMIT $3 OFFSET $4
+	`, filmId, statuses, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	reviews := make([]*aggregate.ReviewAggregate, 0, limit)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	totalCount := 0
+	err = conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM reviews rv
+		WHERE ($1::uuid IS NULL OR rv.film_id = $1)
+		AND ($2::text[] IS NULL OR rv.status = ANY($2::text[]))
+	`, filmId, statuses).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	remainder := totalCount % limit
+	totalCount /= limit
+	if remainder != 0 {
+		totalCount++
+	}
+
+	return reviews, totalCount, nil
+}
+
+func (r reviewRepository) HasByUserAndFilm(ctx context.Context, userId string, filmId string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM reviews WHERE user_id = $1 AND film_id = $2)"
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userId, filmId).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r reviewRepository) SetStatus(ctx context.Context, id string, status string, note *string) (*aggregate.ReviewAggregate, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE reviews SET status = $1, moderation_note = $2, updated_at = now() WHERE id = $3", status, note, id)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return r.GetById(ctx, id)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReview(row rowScanner) (*aggregate.ReviewAggregate, error) {
+	var (
+		review          model.Review
+		score           sql.NullInt64
+		ratingCreatedAt sql.NullTime
+		ratingUpdatedAt sql.NullTime
+	)
+	err := row.Scan(
+		&review.Id, &review.FilmId, &review.UserId, &review.Title, &review.Body, &review.Spoiler,
+		&review.Status, &review.ModerationNote, &review.CreatedAt, &review.UpdatedAt,
+		&score, &ratingCreatedAt, &ratingUpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &aggregate.ReviewAggregate{Review: review}
+	if score.Valid {
+		result.Rating = &model.Rating{
+			UserId:    review.UserId,
+			FilmId:    review.FilmId,
+			Score:     int(score.Int64),
+			CreatedAt: ratingCreatedAt.Time,
+			UpdatedAt: ratingUpdatedAt.Time,
+		}
+	}
+	return result, nil
+}
+
+func reviewFilterArgs(query domainQuery.ReviewRepositoryQuery) (interface{}, interface{}) {
+	var filmId, statuses interface{}
+	if query.FilmId != "" {
+		filmId = query.FilmId
+	}
+	if len(query.Statuses) != 0 {
+		statuses = pq.Array(query.Statuses)
+	}
+	return filmId, statuses
+}
+
+func NewReviewRepository(db *sql.DB) repository.ReviewRepository {
+	return &reviewRepository{db: db}
+}
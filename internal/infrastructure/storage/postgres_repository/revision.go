@@ -0,0 +1,102 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+const revisionSelectSql = "SELECT id, entity_type, entity_id, number, action, COALESCE(user_id, ''), snapshot, created_at FROM revisions"
+
+type revisionRepository struct {
+	db *sql.DB
+}
+
+// Create номер считается в том же запросе под блокировкой строки сущности, поэтому параллельные правки,
+// в том числе правки связей с другой стороны, не получают одинаковый номер
+func (r revisionRepository) Create(ctx context.Context, revision *model.Revision) (*model.Revision, error) {
+	table := "films"
+	if revision.EntityType == constants.AuditActor {
+		table = "actors"
+	}
+	query := `
+		INSERT INTO revisions (id, entity_type, entity_id, number, action, user_id, snapshot)
+		SELECT $1, $2, $3, COALESCE(MAX(number), 0) + 1, $4, NULLIF($5, ''), $6
+		FROM revisions WHERE entity_type = $2 AND entity_id = $3
+		RETURNING number, created_at
+	`
+	result := *revision
+	err := inTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		if _, err := tx.ExecContext(ctx, "SELECT 1 FROM "+table+" WHERE id = $1 FOR UPDATE", revision.EntityId); err != nil {
+			return err
+		}
+		return tx.QueryRowContext(ctx, query, revision.Id, revision.EntityType, revision.EntityId, revision.Action, revision.UserId, []byte(revision.Snapshot)).
+			Scan(&result.Number, &result.CreatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r revisionRepository) GetByEntity(ctx context.Context, entityType string, entityId string, query domainQuery.PageQuery) ([]*model.Revision, int, error) {
+	offset := query.PageCount * (query.CurrentPage - 1)
+	limit := query.PageCount
+	rows, err := conn(ctx, r.db).QueryContext(ctx, revisionSelectSql+`
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY number DESC
+		LIMIT $3 OFFSET $4
+	`, entityType, entityId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	revisions := make([]*model.Revision, 0, limit)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	err = conn(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM revisions WHERE entity_type = $1 AND entity_id = $2", entityType, entityId).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+	return revisions, pageCount(total, limit), nil
+}
+
+func (r revisionRepository) GetByNumber(ctx context.Context, entityType string, entityId string, number int) (*model.Revision, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, revisionSelectSql+" WHERE entity_type = $1 AND entity_id = $2 AND number = $3", entityType, entityId, number)
+	return scanRevision(row)
+}
+
+func scanRevision(row rowScanner) (*model.Revision, error) {
+	var (
+		revision model.Revision
+		snapshot []byte
+	)
+	err := row.Scan(&revision.Id, &revision.EntityType, &revision.EntityId, &revision.Number, &revision.Action, &revision.UserId, &snapshot, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	revision.Snapshot = snapshot
+	return &revision, nil
+}
+
+func NewRevisionRepository(db *sql.DB) repository.RevisionRepository {
+	return &revisionRepository{db: db}
+}
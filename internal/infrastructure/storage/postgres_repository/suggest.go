@@ -0,0 +1,69 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+type suggestRepository struct {
+	db *sql.DB
+}
+
+// Suggest кандидаты отбираются оператором <% по триграммному индексу, популярность учитывается логарифмически
+func (s suggestRepository) Suggest(ctx context.Context, query domainQuery.SuggestQuery) ([]*model.Suggestion, error) {
+	sqlQuery := `
+		WITH candidates AS (
+			SELECT $3 AS type, f.id, f.name, word_similarity($1, f.name) AS similarity, f.vote_count AS popularity
+			FROM films f
+			WHERE f.deleted_at IS NULL AND $1 <% f.name
+			UNION ALL
+			SELECT $4 AS type, a.id, a.name, word_similarity($1, a.name) AS similarity,
+				(SELECT COUNT(DISTINCT af.film_id) FROM actor_film af WHERE af.actor_id = a.id)::int AS popularity
+			FROM actors a
+			WHERE a.deleted_at IS NULL AND $1 <% a.name
+		)
+		SELECT type, id, name, similarity, popularity
+		FROM candidates
+		ORDER BY similarity + ln(1 + popularity) * 0.05 DESC, name, id
+		LIMIT $2
+	`
+	stmt, err := conn(ctx, s.db).PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func(stmt *sql.Stmt) {
+		_ = stmt.Close()
+	}(stmt)
+
+	rows, err := stmt.QueryContext(ctx, query.Value, query.Limit, constants.SuggestFilm, constants.SuggestActor)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	suggestions := make([]*model.Suggestion, 0, query.Limit)
+	for rows.Next() {
+		var suggestion model.Suggestion
+		err := rows.Scan(&suggestion.Type, &suggestion.Id, &suggestion.Name, &suggestion.Similarity, &suggestion.Popularity)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func NewSuggestRepository(db *sql.DB) repository.SuggestRepository {
+	return &suggestRepository{db: db}
+}
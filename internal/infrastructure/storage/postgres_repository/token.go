@@ -13,7 +13,7 @@ type tokenRepository struct {
 
 func (t tokenRepository) Create(ctx context.Context, token *model.Token) (*model.Token, error) {
 	query := "INSERT INTO tokens (id, value) VALUES ($1, $2) RETURNING id, value"
-	stmt, err := t.db.PrepareContext(ctx, query)
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +31,7 @@ func (t tokenRepository) Create(ctx context.Context, token *model.Token) (*model
 
 func (t tokenRepository) Update(ctx context.Context, token *model.Token) (*model.Token, error) {
 	query := "UPDATE tokens SET value = $1 WHERE id = $2 RETURNING id, value"
-	stmt, err := t.db.PrepareContext(ctx, query)
+	stmt, err := conn(ctx, t.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -49,13 +49,13 @@ func (t tokenRepository) Update(ctx context.Context, token *model.Token) (*model
 
 func (t tokenRepository) Delete(ctx context.Context, id string) error {
 	query := "DELETE FROM tokens WHERE id = $1"
-	_, err := t.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, t.db).ExecContext(ctx, query, id)
 	return err
 }
 
 func (t tokenRepository) GetById(ctx context.Context, id string) (*model.Token, error) {
 	query := "SELECT id, value FROM tokens WHERE id = $1"
-	row := t.db.QueryRowContext(ctx, query, id)
+	row := conn(ctx, t.db).QueryRowContext(ctx, query, id)
 
 	var token model.Token
 	err := row.Scan(&token.Id, &token.Value)
@@ -68,14 +68,14 @@ func (t tokenRepository) GetById(ctx context.Context, id string) (*model.Token,
 
 func (t tokenRepository) DeleteByValue(ctx context.Context, value string) error {
 	query := "DELETE FROM tokens WHERE value = $1"
-	_, err := t.db.ExecContext(ctx, query, value)
+	_, err := conn(ctx, t.db).ExecContext(ctx, query, value)
 	return err
 }
 
 func (t tokenRepository) HasByValue(ctx context.Context, value string) (bool, error) {
 	query := "SELECT EXISTS(SELECT 1 FROM tokens WHERE value = $1)"
 	var exists bool
-	err := t.db.QueryRowContext(ctx, query, value).Scan(&exists)
+	err := conn(ctx, t.db).QueryRowContext(ctx, query, value).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
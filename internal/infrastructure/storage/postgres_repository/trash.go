@@ -0,0 +1,88 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// liveSql условие для записей вне корзины
+const liveSql = "deleted_at IS NULL"
+
+// softDelete переносит запись владельца by (одна из констант creditsBy*) в корзину, связи не трогаются
+func softDelete(ctx context.Context, db *sql.DB, by string, id string) error {
+	table, _ := creditTables(by)
+	return inTx(ctx, db, func(ctx context.Context) error {
+		tx := conn(ctx, db)
+		if err := execAffected(ctx, tx, "UPDATE "+table+" SET deleted_at = now() WHERE id = $1 AND "+liveSql, id); err != nil {
+			return err
+		}
+		return bumpLinked(ctx, tx, by, id)
+	})
+}
+
+// restoreDeleted возвращает запись из корзины, версия растет как при любом изменении строки
+func restoreDeleted(ctx context.Context, db *sql.DB, by string, id string) error {
+	table, _ := creditTables(by)
+	query := "UPDATE " + table + " SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL"
+	return inTx(ctx, db, func(ctx context.Context) error {
+		tx := conn(ctx, db)
+		if err := execAffected(ctx, tx, query, id); err != nil {
+			return err
+		}
+		return bumpLinked(ctx, tx, by, id)
+	})
+}
+
+// bumpLinked записи из корзины не попадают в титры, поэтому удаление и восстановление меняет агрегаты связанных записей
+func bumpLinked(ctx context.Context, tx querier, by string, id string) error {
+	_, linkedTable := creditTables(by)
+	_, err := tx.ExecContext(ctx, "UPDATE "+linkedTable+" SET version = version + 1 WHERE id IN (SELECT "+linkedColumn(by)+" FROM actor_film WHERE "+by+" = $1)", id)
+	return err
+}
+
+// purgeDeleted навсегда удаляет записи корзины по условию where с одним параметром arg вместе с их связями.
+// Остальные зависимые таблицы чистятся каскадом
+func purgeDeleted(ctx context.Context, db *sql.DB, by string, where string, arg interface{}) (int, error) {
+	table, _ := creditTables(by)
+	purged := 0
+	err := inTx(ctx, db, func(ctx context.Context) error {
+		tx := conn(ctx, db)
+		where := "deleted_at IS NOT NULL AND " + where
+		_, err := tx.ExecContext(ctx, "DELETE FROM actor_film WHERE "+by+" IN (SELECT id FROM "+table+" WHERE "+where+")", arg)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE "+where, arg)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		purged = int(affected)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// purgeOne удаляет навсегда одну запись из корзины, запись вне корзины - sql.ErrNoRows
+func purgeOne(ctx context.Context, db *sql.DB, by string, id string) error {
+	purged, err := purgeDeleted(ctx, db, by, "id = $1", id)
+	if err != nil {
+		return err
+	}
+	if purged == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// pageCount кол-во страниц по общему кол-ву записей
+func pageCount(total int, limit int) int {
+	count := total / limit
+	if total%limit != 0 {
+		count++
+	}
+	return count
+}
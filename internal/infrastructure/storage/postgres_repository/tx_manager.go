@@ -0,0 +1,107 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	"github.com/lib/pq"
+)
+
+type (
+	// querier общее у *sql.DB и *sql.Tx, через него репозитории выполняют запросы
+	querier interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+		PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	}
+
+	txKey struct{}
+
+	txManager struct {
+		db *sql.DB
+	}
+)
+
+// conn транзакция из ctx, если она открыта через TxManager, иначе сама база
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// inTx выполняет fn в транзакции из ctx либо в новой. Коммит и откат делает только тот, кто открыл транзакцию
+func inTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// execAffected выполняет изменение одной записи, если ни одна строка не затронута - sql.ErrNoRows и откат транзакции
+func execAffected(ctx context.Context, tx querier, query string, args ...any) error {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// eachRow передает строки запроса в fn по мере чтения из курсора, ничего не накапливая. Ошибка fn закрывает курсор
+func eachRow(ctx context.Context, tx querier, query string, args []any, fn func(row rowScanner) error) error {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (t txManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTx(ctx, t.db, fn)
+}
+
+func NewTxManager(db *sql.DB) repository.TxManager {
+	return &txManager{db: db}
+}
+
+// isUniqueViolation нарушение уникального индекса или первичного ключа
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -14,7 +14,7 @@ type userRepository struct {
 
 func (u userRepository) Create(ctx context.Context, userAggregate *aggregate.UserAggregate) (*aggregate.UserAggregate, error) {
 	query := "INSERT INTO users (id, name, password, role) VALUES ($1, $2, $3, $4) RETURNING id, name, password, role"
-	stmt, err := u.db.PrepareContext(ctx, query)
+	stmt, err := conn(ctx, u.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +34,7 @@ func (u userRepository) Create(ctx context.Context, userAggregate *aggregate.Use
 
 func (u userRepository) Update(ctx context.Context, userAggregate *aggregate.UserAggregate) (*aggregate.UserAggregate, error) {
 	query := "UPDATE users SET name = $1, password = $2, role = $3 WHERE id = $4 RETURNING id, name, password, role"
-	stmt, err := u.db.PrepareContext(ctx, query)
+	stmt, err := conn(ctx, u.db).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -54,13 +54,13 @@ func (u userRepository) Update(ctx context.Context, userAggregate *aggregate.Use
 
 func (u userRepository) Delete(ctx context.Context, id string) error {
 	query := "DELETE FROM users WHERE id = $1"
-	_, err := u.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, u.db).ExecContext(ctx, query, id)
 	return err
 }
 
 func (u userRepository) GetById(ctx context.Context, id string) (*aggregate.UserAggregate, error) {
 	query := "SELECT id, name, password, role FROM users WHERE id = $1"
-	row := u.db.QueryRowContext(ctx, query, id)
+	row := conn(ctx, u.db).QueryRowContext(ctx, query, id)
 
 	var user model.User
 	err := row.Scan(&user.Id, &user.Name, &user.Password.Value, &user.Role)
@@ -74,7 +74,7 @@ func (u userRepository) GetById(ctx context.Context, id string) (*aggregate.User
 func (u userRepository) HasUserByName(ctx context.Context, name string) (bool, error) {
 	query := "SELECT EXISTS(SELECT 1 FROM users WHERE name = $1)"
 	var exists bool
-	err := u.db.QueryRowContext(ctx, query, name).Scan(&exists)
+	err := conn(ctx, u.db).QueryRowContext(ctx, query, name).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -83,7 +83,7 @@ func (u userRepository) HasUserByName(ctx context.Context, name string) (bool, e
 
 func (u userRepository) GetByName(ctx context.Context, name string) (*aggregate.UserAggregate, error) {
 	query := "SELECT id, name, password, role FROM users WHERE name = $1"
-	row := u.db.QueryRowContext(ctx, query, name)
+	row := conn(ctx, u.db).QueryRowContext(ctx, query, name)
 
 	var user model.User
 	err := row.Scan(&user.Id, &user.Name, &user.Password.Value, &user.Role)
@@ -0,0 +1,248 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+)
+
+// userListSelectSql порядок колонок соответствует scanUserList
+const userListSelectSql = `
+	SELECT l.id, l.user_id, l.kind, l.name, l.share_slug, l.created_at,
+		(SELECT COUNT(*) FROM user_list_films lf JOIN films f ON f.id = lf.film_id AND f.deleted_at IS NULL WHERE lf.list_id = l.id)
+	FROM user_lists l
+`
+
+type userListRepository struct {
+	db *sql.DB
+	// rate колонка оценки фильмов, см. filmRateColumn
+	rate string
+}
+
+func (u userListRepository) Create(ctx context.Context, list *model.UserList) (*model.UserList, error) {
+	query := "INSERT INTO user_lists (id, user_id, kind, name, share_slug) VALUES ($1, $2, $3, $4, $5)"
+	_, err := conn(ctx, u.db).ExecContext(ctx, query, list.Id, list.UserId, list.Kind, list.Name, list.ShareSlug)
+	if err != nil {
+		return nil, err
+	}
+	return u.GetById(ctx, list.Id)
+}
+
+func (u userListRepository) CreateSystem(ctx context.Context, list *model.UserList) error {
+	query := `INSERT INTO user_lists (id, user_id, kind, name) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, kind) WHERE kind <> 'custom' DO NOTHING`
+	_, err := conn(ctx, u.db).ExecContext(ctx, query, list.Id, list.UserId, list.Kind, list.Name)
+	return err
+}
+
+func (u userListRepository) Update(ctx context.Context, list *model.UserList) (*model.UserList, error) {
+	result, err := conn(ctx, u.db).ExecContext(ctx, "UPDATE user_lists SET name = $1, share_slug = $2 WHERE id = $3", list.Name, list.ShareSlug, list.Id)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return u.GetById(ctx, list.Id)
+}
+
+func (u userListRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, u.db).ExecContext(ctx, "DELETE FROM user_lists WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (u userListRepository) GetById(ctx context.Context, id string) (*model.UserList, error) {
+	return scanUserList(conn(ctx, u.db).QueryRowContext(ctx, userListSelectSql+" WHERE l.id = $1", id))
+}
+
+func (u userListRepository) GetBySlug(ctx context.Context, slug string) (*model.UserList, error) {
+	return scanUserList(conn(ctx, u.db).QueryRowContext(ctx, userListSelectSql+" WHERE l.share_slug = $1", slug))
+}
+
+func (u userListRepository) GetByUser(ctx context.Context, userId string) ([]*model.UserList, error) {
+	rows, err := conn(ctx, u.db).QueryContext(ctx, userListSelectSql+" WHERE l.user_id = $1 ORDER BY l.created_at, l.id", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	lists := make([]*model.UserList, 0, 8)
+	for rows.Next() {
+		list, err := scanUserList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (u userListRepository) AddFilm(ctx context.Context, listId string, filmId string) error {
+	return u.inListTx(ctx, listId, func(tx querier) error {
+		var filmExists bool
+		err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM films WHERE id = $1 AND deleted_at IS NULL)", filmId).Scan(&filmExists)
+		if err != nil {
+			return err
+		}
+		if !filmExists {
+			return sql.ErrNoRows
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_list_films (list_id, film_id, position)
+			SELECT $1, $2, COALESCE(MAX(position) + 1, 0) FROM user_list_films WHERE list_id = $1
+			ON CONFLICT DO NOTHING
+		`, listId, filmId)
+		return err
+	})
+}
+
+func (u userListRepository) RemoveFilm(ctx context.Context, listId string, filmId string) error {
+	return u.inListTx(ctx, listId, func(tx querier) error {
+		var position int
+		err := tx.QueryRowContext(ctx, "DELETE FROM user_list_films WHERE list_id = $1 AND film_id = $2 RETURNING position", listId, filmId).Scan(&position)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE user_list_films SET position = position - 1 WHERE list_id = $1 AND position > $2", listId, position)
+		return err
+	})
+}
+
+func (u userListRepository) MoveFilm(ctx context.Context, listId string, filmId string, position int) error {
+	return u.inListTx(ctx, listId, func(tx querier) error {
+		// удаление фильма из каталога каскадом оставляет дыры в позициях, сначала уплотняем их
+		_, err := tx.ExecContext(ctx, `
+			UPDATE user_list_films lf SET position = r.rn - 1
+			FROM (SELECT film_id, ROW_NUMBER() OVER (ORDER BY position, added_at) AS rn FROM user_list_films WHERE list_id = $1) r
+			WHERE lf.list_id = $1 AND lf.film_id = r.film_id
+		`, listId)
+		if err != nil {
+			return err
+		}
+
+		var current, count int
+		err = tx.QueryRowContext(ctx, "SELECT position FROM user_list_films WHERE list_id = $1 AND film_id = $2", listId, filmId).Scan(&current)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_list_films WHERE list_id = $1", listId).Scan(&count)
+		if err != nil {
+			return err
+		}
+		position = max(0, min(position, count-1))
+
+		switch {
+		case position < current:
+			_, err = tx.ExecContext(ctx, "UPDATE user_list_films SET position = position + 1 WHERE list_id = $1 AND position >= $2 AND position < $3", listId, position, current)
+		case position > current:
+			_, err = tx.ExecContext(ctx, "UPDATE user_list_films SET position = position - 1 WHERE list_id = $1 AND position > $2 AND position <= $3", listId, current, position)
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE user_list_films SET position = $1 WHERE list_id = $2 AND film_id = $3", position, listId, filmId)
+		return err
+	})
+}
+
+func (u userListRepository) GetFilms(ctx context.Context, listId string, query domainQuery.PageQuery) ([]*aggregate.FilmAggregate, int, error) {
+	offset := query.PageCount * (query.CurrentPage - 1)
+	limit := query.PageCount
+
+	rows, err := conn(ctx, u.db).QueryContext(ctx, `
+		SELECT f.id, f.name, f.description, f.release_date, f.`+u.rate+`, f.manual_rate, f.vote_count, f.version
+		FROM user_list_films lf
+		JOIN films f ON f.id = lf.film_id AND f.deleted_at IS NULL
+		WHERE lf.list_id = $1
+		ORDER BY lf.position
+		LIMIT $2 OFFSET $3
+	`, listId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	films := make([]*aggregate.FilmAggregate, 0, limit)
+	for rows.Next() {
+		aggr := &aggregate.FilmAggregate{}
+		err := rows.Scan(&aggr.Film.Id, &aggr.Film.Name, &aggr.Film.Description, &aggr.Film.ReleaseDate, &aggr.Film.Rate, &aggr.Film.ManualRate, &aggr.Film.VoteCount, &aggr.Film.Version)
+		if err != nil {
+			return nil, 0, err
+		}
+		films = append(films, aggr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	totalCount := 0
+	err = conn(ctx, u.db).QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM user_list_films lf JOIN films f ON f.id = lf.film_id AND f.deleted_at IS NULL WHERE lf.list_id = $1
+	`, listId).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	remainder := totalCount % limit
+	totalCount /= limit
+	if remainder != 0 {
+		totalCount++
+	}
+
+	return films, totalCount, nil
+}
+
+// inListTx блокирует строку списка, чтобы параллельные изменения не перемешали позиции
+func (u userListRepository) inListTx(ctx context.Context, listId string, fn func(tx querier) error) error {
+	return inTx(ctx, u.db, func(ctx context.Context) error {
+		tx := conn(ctx, u.db)
+		var lockedId string
+		err := tx.QueryRowContext(ctx, "SELECT id FROM user_lists WHERE id = $1 FOR UPDATE", listId).Scan(&lockedId)
+		if err != nil {
+			return err
+		}
+
+		return fn(tx)
+	})
+}
+
+func scanUserList(row rowScanner) (*model.UserList, error) {
+	var list model.UserList
+	err := row.Scan(&list.Id, &list.UserId, &list.Kind, &list.Name, &list.ShareSlug, &list.CreatedAt, &list.FilmCount)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func NewUserListRepository(db *sql.DB, honorManualRate bool) repository.UserListRepository {
+	return &userListRepository{db: db, rate: filmRateColumn(honorManualRate)}
+}
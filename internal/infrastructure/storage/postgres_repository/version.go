@@ -0,0 +1,19 @@
+package postgresRepository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/OddEer0/vk-filmoteka/internal/domain/repository"
+)
+
+// versionError условный UPDATE не вернул строку: если запись есть, значит не совпала версия
+func versionError(ctx context.Context, tx querier, table string, id string, err error) error {
+	if err != sql.ErrNoRows {
+		return err
+	}
+	if err := liveRowExists(ctx, tx, table, id); err != nil {
+		return err
+	}
+	return repository.ErrVersionConflict
+}
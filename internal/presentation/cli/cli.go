@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+)
+
+type Command func(ctx context.Context, cfg *config.Config, out io.Writer, args []string) error
+
+var commands = map[string]Command{
+	"export":      Export,
+	"import":      Import,
+	"migrate":     Migrate,
+	"purge-trash": PurgeTrash,
+}
+
+// Run выполняет подкоманду бинарника, args без имени программы (os.Args[1:])
+func Run(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("command required, available: %s", available())
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %s, available: %s", args[0], available())
+	}
+	return command(ctx, cfg, os.Stdout, args[1:])
+}
+
+func available() string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// cliError ошибка use case для терминала: у AppError подробности только в DevMessage
+func cliError(err error) error {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) && appErr.DevMessage != "" {
+		return errors.New(appErr.DevMessage)
+	}
+	return err
+}
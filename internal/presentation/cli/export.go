@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	exportUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/export_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres"
+	postgresRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres_repository"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+)
+
+const exportUsage = "usage: export films | actors | credits [--format json | ndjson | csv] [--out file] [filter=value ...], " +
+	"filters as query params of GET /http/v2/export/..."
+
+// Export подкоманда export, выгрузка в stdout или в файл --out. Формат по умолчанию - по расширению файла, иначе json
+func Export(ctx context.Context, cfg *config.Config, out io.Writer, args []string) error {
+	if len(args) == 0 || !slices.Contains(constants.ImportKinds, args[0]) {
+		return errors.New(exportUsage)
+	}
+	kind, format, path := args[0], "", ""
+	filters := url.Values{}
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--format", "--out":
+			if i+1 == len(args) {
+				return errors.New(exportUsage)
+			}
+			if args[i] == "--format" {
+				format = args[i+1]
+			} else {
+				path = args[i+1]
+			}
+			i++
+		default:
+			key, value, ok := strings.Cut(args[i], "=")
+			if !ok {
+				return fmt.Errorf("invalid filter %s, %s", args[i], exportUsage)
+			}
+			filters.Add(key, value)
+		}
+	}
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if _, ok := httpUtils.StreamContentTypes[format]; !ok {
+			format = httpUtils.StreamJson
+		}
+	}
+
+	db, err := postgres.ConnectPg(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		out = file
+	}
+	writer, err := httpUtils.NewStreamWriter(out, format, constants.CatalogColumns[kind])
+	if err != nil {
+		return fmt.Errorf("%s, %s", err.Error(), exportUsage)
+	}
+
+	useCase := exportUseCase.New(postgresRepository.NewFilmRepository(db, cfg.HonorManualRate), postgresRepository.NewActorRepository(db, cfg.HonorManualRate))
+	err = export(ctx, useCase, kind, filters, writer)
+	if err != nil && path != "" {
+		// недописанный файл легко принять за полную выгрузку
+		_ = os.Remove(path)
+	}
+	return err
+}
+
+func export(ctx context.Context, useCase exportUseCase.ExportUseCase, kind string, filters url.Values, writer httpUtils.StreamWriter) error {
+	if kind == constants.ImportActors {
+		query, err := httpv1.ExportActorQuery(filters)
+		if err != nil {
+			return fmt.Errorf("invalid filters, %s", exportUsage)
+		}
+		return cliError(useCase.Actors(ctx, *query, writer))
+	}
+
+	query, err := httpv1.ExportFilmQuery(filters)
+	if err != nil {
+		return fmt.Errorf("invalid filters, %s", exportUsage)
+	}
+	if kind == constants.ImportCredits {
+		return cliError(useCase.Credits(ctx, *query, writer))
+	}
+	return cliError(useCase.Films(ctx, *query, writer))
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
+	importUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/import_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres"
+	postgresRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres_repository"
+)
+
+const importUsage = "usage: import films | actors | credits <file.csv | file.ndjson> [--dry-run] [--batch-size n], " +
+	"import imdb --dir <dataset dir> [--types movie,tvMovie] [--min-votes n] [--batch-size n] [--restart]"
+
+// importExtensions формат файла импорта по расширению
+var importExtensions = map[string]string{
+	".csv":    constants.ImportCsv,
+	".ndjson": constants.ImportNdjson,
+	".jsonl":  constants.ImportNdjson,
+}
+
+// Import подкоманда import, печатает итог каждой строки. Если хоть одна строка не импортирована - ошибка.
+// import imdb загружает датасет IMDb, см. importImdb
+func Import(ctx context.Context, cfg *config.Config, out io.Writer, args []string) error {
+	if len(args) > 0 && args[0] == "imdb" {
+		return importImdb(ctx, cfg, out, args[1:])
+	}
+	data, path, err := parseImportArgs(args)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	data.Data = file
+
+	db, err := postgres.ConnectPg(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	report, err := newImportUseCase(cfg, db).Import(ctx, data)
+	if err != nil {
+		return cliError(err)
+	}
+
+	for _, row := range report.Rows {
+		_, _ = fmt.Fprintf(out, "line %d: %s", row.Line, row.Status)
+		for _, value := range []string{row.Id, row.FilmId, row.ActorId, row.Reason} {
+			if value != "" {
+				_, _ = fmt.Fprintf(out, " %s", value)
+			}
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+	prefix := ""
+	if report.DryRun {
+		prefix = "dry run: "
+	}
+	_, _ = fmt.Fprintf(out, "%s%d created, %d updated, %d skipped, %d failed\n", prefix, report.Created, report.Updated, report.Skipped, report.Failed)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d row(s) failed", report.Failed)
+	}
+	return nil
+}
+
+func newImportUseCase(cfg *config.Config, db *sql.DB) importUseCase.ImportUseCase {
+	filmRepo := postgresRepository.NewFilmRepository(db, cfg.HonorManualRate)
+	actorRepo := postgresRepository.NewActorRepository(db, cfg.HonorManualRate)
+	txManager := postgresRepository.NewTxManager(db)
+	audit := auditService.New(postgresRepository.NewAuditRepository(db), txManager)
+	revision := revisionService.New(postgresRepository.NewRevisionRepository(db), filmRepo, actorRepo)
+	return importUseCase.New(filmRepo, actorRepo, postgresRepository.NewImdbRepository(db), postgresRepository.NewExternalIdRepository(db), txManager, audit, revision, cfg.HonorManualRate)
+}
+
+func parseImportArgs(args []string) (appDto.ImportUseCaseDto, string, error) {
+	var data appDto.ImportUseCaseDto
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--dry-run":
+			data.DryRun = true
+		case "--batch-size":
+			if i+1 == len(args) {
+				return data, "", errors.New(importUsage)
+			}
+			i++
+			batchSize, err := strconv.Atoi(args[i])
+			if err != nil || batchSize < 1 {
+				return data, "", fmt.Errorf("invalid batch size %s, %s", args[i], importUsage)
+			}
+			data.BatchSize = batchSize
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+	if len(positional) != 2 || !slices.Contains(constants.ImportKinds, positional[0]) {
+		return data, "", errors.New(importUsage)
+	}
+
+	format, ok := importExtensions[strings.ToLower(filepath.Ext(positional[1]))]
+	if !ok {
+		return data, "", fmt.Errorf("unknown file format %s, %s", positional[1], importUsage)
+	}
+	data.Kind, data.Format = positional[0], format
+	return data, positional[1], nil
+}
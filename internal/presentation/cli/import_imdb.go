@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres"
+)
+
+// importImdb import imdb, печатает прогресс после каждой пачки, итоги этапов и строки датасета с ошибками.
+// Прерванный импорт продолжается повторным запуском с теми же параметрами
+func importImdb(ctx context.Context, cfg *config.Config, out io.Writer, args []string) error {
+	data, err := parseImdbArgs(args)
+	if err != nil {
+		return err
+	}
+	data.Progress = func(stage string, done int, total int) {
+		_, _ = fmt.Fprintf(out, "%s: %d/%d\n", stage, done, total)
+	}
+
+	db, err := postgres.ConnectPg(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	report, err := newImportUseCase(cfg, db).ImportImdb(ctx, data)
+	if err != nil {
+		return cliError(err)
+	}
+
+	if report.Resumed != nil {
+		_, _ = fmt.Fprintf(out, "resumed from %s %d\n", report.Resumed.Stage, report.Resumed.Done)
+	}
+	failed := 0
+	for _, stage := range []appDto.ImportReport{report.Films, report.Actors, report.Credits} {
+		for _, row := range stage.Rows {
+			_, _ = fmt.Fprintf(out, "%s line %d: %s %s\n", stage.Kind, row.Line, row.Status, row.Reason)
+		}
+		_, _ = fmt.Fprintf(out, "%s: %d created, %d updated, %d skipped, %d failed\n", stage.Kind, stage.Created, stage.Updated, stage.Skipped, stage.Failed)
+		failed += stage.Failed
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d row(s) failed", failed)
+	}
+	return nil
+}
+
+func parseImdbArgs(args []string) (appDto.ImdbImportUseCaseDto, error) {
+	var data appDto.ImdbImportUseCaseDto
+	dir := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--restart":
+			data.Restart = true
+		case "--dir", "--types", "--min-votes", "--batch-size":
+			if i+1 == len(args) {
+				return data, errors.New(importUsage)
+			}
+			flag, value := args[i], args[i+1]
+			i++
+			switch flag {
+			case "--dir":
+				dir = value
+			case "--types":
+				data.TitleTypes = strings.Split(value, ",")
+			default:
+				number, err := strconv.Atoi(value)
+				if err != nil || number < 0 || (flag == "--batch-size" && number == 0) {
+					return data, fmt.Errorf("invalid %s %s, %s", strings.TrimPrefix(flag, "--"), value, importUsage)
+				}
+				if flag == "--min-votes" {
+					data.MinVotes = number
+				} else {
+					data.BatchSize = number
+				}
+			}
+		default:
+			return data, fmt.Errorf("unknown argument %s, %s", args[i], importUsage)
+		}
+	}
+	if dir == "" {
+		return data, errors.New(importUsage)
+	}
+	data.Dir = os.DirFS(dir)
+	return data, nil
+}
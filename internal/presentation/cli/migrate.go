@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/migrations"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// Migrate подкоманда migrate up|down|status
+func Migrate(ctx context.Context, cfg *config.Config, out io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := postgres.ConnectPg(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if err = postgres.SeedAdmin(ctx, db, cfg); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "applied %d migration(s)\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %s, %s", args[1], migrateUsage)
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "reverted %d migration(s)\n", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(out, "%06d %-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	trashUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/trash_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres"
+	postgresRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres_repository"
+)
+
+const purgeTrashUsage = "usage: purge-trash [retention], retention as 720h, by default trash.retention from config"
+
+// PurgeTrash подкоманда purge-trash, однократная очистка корзины без ожидания фоновой
+func PurgeTrash(ctx context.Context, cfg *config.Config, out io.Writer, args []string) error {
+	retention := cfg.Trash.Retention
+	if len(args) > 0 {
+		parsed, err := time.ParseDuration(args[0])
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid retention %s, %s", args[0], purgeTrashUsage)
+		}
+		retention = parsed
+	}
+	if len(args) > 1 {
+		return errors.New(purgeTrashUsage)
+	}
+
+	db, err := postgres.ConnectPg(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	audit := auditService.New(postgresRepository.NewAuditRepository(db), postgresRepository.NewTxManager(db))
+	useCase := trashUseCase.New(postgresRepository.NewFilmRepository(db, cfg.HonorManualRate), postgresRepository.NewActorRepository(db, cfg.HonorManualRate), audit)
+	result, err := useCase.PurgeExpired(ctx, retention)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "purged %d film(s), %d actor(s)\n", result.Films, result.Actors)
+	return nil
+}
@@ -10,8 +10,23 @@ type (
 		Birthday time.Time `json:"birhday" validate:"required,dateIsLessNow"`
 	}
 
+	CreditDto struct {
+		FilmId       string  `json:"filmId" validate:"required,uuidv4"`
+		Role         string  `json:"role,omitempty" validate:"omitempty,creditRole"`
+		Character    *string `json:"character,omitempty" validate:"omitempty,max=150"`
+		BillingOrder int     `json:"billingOrder" validate:"min=0"`
+	}
+
+	// AddFilmToActorDto filmIds оставлен для старых клиентов, такие связи создаются с ролью actor
 	AddFilmToActorDto struct {
-		ActorId string   `json:"actorId" validate:"required,uuidv4"`
-		FilmIds []string `json:"filmIds" validate:"required"`
+		ActorId string      `json:"actorId" validate:"required,uuidv4"`
+		FilmIds []string    `json:"filmIds,omitempty" validate:"required_without=Credits"`
+		Credits []CreditDto `json:"credits,omitempty" validate:"required_without=FilmIds,dive"`
+	}
+
+	// SetActorFilmsDto новый полный список участий актера, пустой объект {} убирает актера из всех фильмов
+	SetActorFilmsDto struct {
+		FilmIds []string    `json:"filmIds,omitempty" validate:"dive,uuidv4"`
+		Credits []CreditDto `json:"credits,omitempty" validate:"dive"`
 	}
 )
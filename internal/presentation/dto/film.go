@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"time"
+)
 
 type (
 	CreateFilmDto struct {
@@ -17,4 +21,34 @@ type (
 		ReleaseDate time.Time `json:"createdAt" validate:"required"`
 		Rate        float32   `json:"rate" validate:"min=0,max=10"`
 	}
+
+	CastCreditDto struct {
+		ActorId      string  `json:"actorId" validate:"required,uuidv4"`
+		Role         string  `json:"role,omitempty" validate:"omitempty,creditRole"`
+		Character    *string `json:"character,omitempty" validate:"omitempty,max=150"`
+		BillingOrder int     `json:"billingOrder" validate:"min=0"`
+	}
+
+	// SetCastDto новый полный состав фильма, actorIds создает роль actor, пустой объект {} убирает всех актеров
+	SetCastDto struct {
+		ActorIds []string        `json:"actorIds,omitempty" validate:"dive,uuidv4"`
+		Credits  []CastCreditDto `json:"credits,omitempty" validate:"dive"`
+	}
+
+	// NullableRate оценка, которую можно не передавать: Set - поле есть в теле, Value nil - передан null
+	NullableRate struct {
+		Set   bool
+		Value *float32
+	}
+
+	// UpdateFilmBody тело PUT фильма. manualRate меняет ручную оценку только если передан, null ее сбрасывает
+	UpdateFilmBody struct {
+		model.Film
+		ManualRate NullableRate `json:"manualRate" swaggertype:"number"`
+	}
 )
+
+func (n *NullableRate) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	return json.Unmarshal(data, &n.Value)
+}
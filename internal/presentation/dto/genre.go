@@ -0,0 +1,8 @@
+package dto
+
+type (
+	FilmsToGenreDto struct {
+		GenreId string   `json:"genreId" validate:"required,uuidv4"`
+		FilmIds []string `json:"filmIds" validate:"required,dive,uuidv4"`
+	}
+)
@@ -8,9 +8,12 @@ import (
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 	"github.com/OddEer0/vk-filmoteka/internal/presentation/dto"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/mapper"
 	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/google/uuid"
 	"net/http"
-	"strconv"
+	"net/url"
+	"strings"
 )
 
 type (
@@ -20,6 +23,15 @@ type (
 		GetByQuery(res http.ResponseWriter, req *http.Request) error
 		Update(res http.ResponseWriter, req *http.Request) error
 		AddFilm(res http.ResponseWriter, req *http.Request) error
+		GetById(res http.ResponseWriter, req *http.Request) error
+		GetByExternalId(res http.ResponseWriter, req *http.Request) error
+		UpdateById(res http.ResponseWriter, req *http.Request) error
+		Patch(res http.ResponseWriter, req *http.Request) error
+		PatchById(res http.ResponseWriter, req *http.Request) error
+		DeleteById(res http.ResponseWriter, req *http.Request) error
+		GetByFilm(res http.ResponseWriter, req *http.Request) error
+		SetFilms(res http.ResponseWriter, req *http.Request) error
+		RemoveFilm(res http.ResponseWriter, req *http.Request) error
 	}
 
 	actorHandler struct {
@@ -41,6 +53,7 @@ func NewActorHandler(useCase actorUseCase.ActorUseCase) ActorHandler {
 // @Param reg body appDto.CreateActorUseCaseDto true "Данные актера"
 // @Success 200 {object} model.Actor "Данные созданного актера"
 // @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 409 {object} appErrors.ResponseError "Внешний идентификатор уже у другой записи"
 // @Router /http/v1/actor [post]
 func (a *actorHandler) Create(res http.ResponseWriter, req *http.Request) error {
 	var body appDto.CreateActorUseCaseDto
@@ -62,7 +75,7 @@ func (a *actorHandler) Create(res http.ResponseWriter, req *http.Request) error
 }
 
 // @Summary Удаление актера [Админы]
-// @Description Доступно только админам, ничего ответом не возвращает
+// @Description Доступно только админам, ничего ответом не возвращает. Актер переносится в корзину, его можно восстановить
 // @Tags actor
 // @Accept json
 // @Produce json
@@ -89,35 +102,25 @@ func (a *actorHandler) Delete(res http.ResponseWriter, req *http.Request) error
 // @Produce json
 // @Param page query string false "текущая страница"
 // @Param page-count query string false "кол-во актеров на странице"
+// @Param cursor query string false "токен next/prev из прошлого ответа, вместо page. Сортировка берется из токена, фильтры нужно передать снова"
 // @Param connection query string false "Вернуть вместе со связями (film)"
+// @Param name query string false "поиск по имени, допускает опечатки"
+// @Param gender query string false "пол (male, female)"
+// @Param birth-year-from query int false "год рождения от"
+// @Param birth-year-to query int false "год рождения до"
+// @Param age-from query int false "возраст от"
+// @Param age-to query int false "возраст до"
+// @Param film query string false "id фильма, в котором участвовал актер"
+// @Param order-field query string false "поле сортировки (name, birthday), по умолчанию id"
+// @Param order-by query string false "направление сортировки (asc, desc)"
 // @Success 200 {object} appDto.ActorGetByQueryResult "получаемые актеры"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
 // @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
 // @Router /http/v1/actor [get]
 func (a *actorHandler) GetByQuery(res http.ResponseWriter, req *http.Request) error {
-	query := req.URL.Query()
-	fQuery := domainQuery.NewActorRepositoryQuery()
-	var err error
-
-	if query.Has("page") {
-		currentPageQ := req.URL.Query().Get("page")
-		fQuery.CurrentPage, err = strconv.Atoi(currentPageQ)
-		if err != nil {
-			return appErrors.BadRequest("invalid page")
-		}
-	}
-	if query.Has("page-count") {
-		pageCountQ := req.URL.Query().Get("page-count")
-		fQuery.PageCount, err = strconv.Atoi(pageCountQ)
-		if err != nil {
-			return appErrors.BadRequest("invalid page count")
-		}
-	}
-	if query.Has("connection") {
-		connectionQ := req.URL.Query().Get("connection")
-		if connectionQ != "film" {
-			return appErrors.BadRequest("invalid connection")
-		}
-		fQuery.WithConnection = append(fQuery.WithConnection, connectionQ)
+	fQuery, err := parseActorQuery(req.URL.Query())
+	if err != nil {
+		return err
 	}
 
 	result, err := a.ActorUseCase.GetByQuery(req.Context(), *fQuery)
@@ -136,8 +139,10 @@ func (a *actorHandler) GetByQuery(res http.ResponseWriter, req *http.Request) er
 // @Accept json
 // @Produce json
 // @Param reg body model.Actor true "Данные актера"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
 // @Success 200 {object} model.Actor "Данные актера"
 // @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
 // @Router /http/v1/actor [put]
 func (a *actorHandler) Update(res http.ResponseWriter, req *http.Request) error {
 	var body model.Actor
@@ -149,7 +154,23 @@ func (a *actorHandler) Update(res http.ResponseWriter, req *http.Request) error
 		_ = req.Body.Close()
 	}()
 
-	actorAggregate, err := aggregate.NewActorAggregate(body)
+	return a.update(res, req, body)
+}
+
+// update валидирует актера целиком и сохраняет его, общий шаг PUT и PATCH.
+// Версия берется только из If-Match, version в теле игнорируется
+func (a *actorHandler) update(res http.ResponseWriter, req *http.Request, actor model.Actor) error {
+	version, err := httpUtils.IfMatchVersion(req)
+	if err != nil {
+		return appErrors.PreconditionFailed("", "error: ", err.Error())
+	}
+	actor.Version = version
+	return a.save(res, req, actor)
+}
+
+// save валидирует и сохраняет актера с версией actor.Version, 0 - без проверки версии
+func (a *actorHandler) save(res http.ResponseWriter, req *http.Request, actor model.Actor) error {
+	actorAggregate, err := aggregate.NewActorAggregate(actor)
 	if err != nil {
 		return appErrors.UnprocessableEntity("")
 	}
@@ -159,12 +180,234 @@ func (a *actorHandler) Update(res http.ResponseWriter, req *http.Request) error
 		return err
 	}
 
+	res.Header().Set("ETag", httpUtils.ETag(actorAggregate.Actor.Version))
 	httpUtils.SendJson(res, http.StatusOK, actorAggregate.Actor)
 	return nil
 }
 
-// @Summary Обновление актера [Админы]
-// @Description Доступно только админам
+// @Summary Получение актера по id
+// @Description Актер вместе с фильмами
+// @Tags actor
+// @Produce json
+// @Param id path string true "id актера"
+// @Param If-None-Match header string false "ETag из прошлого ответа, при совпадении 304 без тела"
+// @Success 200 {object} aggregate.ActorAggregate "Данные актера"
+// @Header 200 {string} ETag "версия записи"
+// @Success 304 "Не изменился"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/actors/{id} [get]
+func (a *actorHandler) GetById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	actor, err := a.ActorUseCase.GetById(req.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	etag := httpUtils.ETag(actor.Actor.Version)
+	res.Header().Set("ETag", etag)
+	if httpUtils.NotModified(req, etag) {
+		res.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, actor)
+	return nil
+}
+
+// @Summary Получение актера по внешнему идентификатору
+// @Description Поиск по идентификатору IMDb, TMDB или Кинопоиска, например /external/imdb/nm0000209/actor
+// @Tags actor
+// @Produce json
+// @Param source path string true "источник: imdb, tmdb или kinopoisk"
+// @Param externalId path string true "идентификатор в источнике"
+// @Success 200 {object} aggregate.ActorAggregate "Данные актера"
+// @Failure 400 {object} appErrors.ResponseError "Неизвестный источник"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/external/{source}/{externalId}/actor [get]
+func (a *actorHandler) GetByExternalId(res http.ResponseWriter, req *http.Request) error {
+	actor, err := a.ActorUseCase.GetByExternalId(req.Context(), req.PathValue("source"), req.PathValue("externalId"))
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, actor)
+	return nil
+}
+
+// @Summary Замена актера [Админы]
+// @Description Доступно только админам, актер передается целиком, id берется из пути
+// @Tags actor
+// @Accept json
+// @Produce json
+// @Param id path string true "id актера"
+// @Param reg body model.Actor true "Данные актера"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
+// @Success 200 {object} model.Actor "Данные обновленного актера"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
+// @Router /http/v2/actors/{id} [put]
+func (a *actorHandler) UpdateById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	var body model.Actor
+	if err := httpUtils.DecodeJson(req, &body); err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	body.Id = id
+	return a.update(res, req, body)
+}
+
+// @Summary Частичное обновление актера [Админы]
+// @Description Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, для обязательных полей это ошибка 422. Проверяется только итоговый актер
+// @Tags actor
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id query string true "id актера"
+// @Param reg body model.Actor true "Изменяемые поля актера"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
+// @Success 200 {object} model.Actor "Данные обновленного актера"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 415 {object} appErrors.ResponseError "Ошибка 415"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
+// @Failure 409 {object} appErrors.ResponseError "Ошибка 409"
+// @Router /http/v1/actor [patch]
+func (a *actorHandler) Patch(res http.ResponseWriter, req *http.Request) error {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		return appErrors.BadRequest("")
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return appErrors.NotFound("")
+	}
+
+	return a.patch(res, req, id)
+}
+
+// @Summary Частичное обновление актера [Админы]
+// @Description Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, для обязательных полей это ошибка 422. Проверяется только итоговый актер
+// @Tags actor
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "id актера"
+// @Param reg body model.Actor true "Изменяемые поля актера"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
+// @Success 200 {object} model.Actor "Данные обновленного актера"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 415 {object} appErrors.ResponseError "Ошибка 415"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
+// @Failure 409 {object} appErrors.ResponseError "Ошибка 409"
+// @Router /http/v2/actors/{id} [patch]
+func (a *actorHandler) PatchById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return a.patch(res, req, id)
+}
+
+// patch накладывает merge patch на текущего актера и сохраняет результат как PUT
+func (a *actorHandler) patch(res http.ResponseWriter, req *http.Request, id string) error {
+	if !httpUtils.IsMergePatch(req) {
+		return appErrors.UnsupportedMediaType("", "expected content type: ", httpUtils.MergePatchContentType)
+	}
+
+	current, err := a.ActorUseCase.GetById(req.Context(), id)
+	if err != nil {
+		return err
+	}
+	// устаревший If-Match отклоняется до применения патча, иначе ошибка валидации скрыла бы конфликт
+	version, err := httpUtils.IfMatchVersion(req)
+	if err != nil || (version != 0 && version != current.Actor.Version) {
+		return appErrors.PreconditionFailed("")
+	}
+
+	actor := current.Actor
+	if err := httpUtils.MergePatchJson(req, &actor); err != nil {
+		return appErrors.BadRequest("", "error: ", err.Error())
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	actor.Id = id
+	// без If-Match патч все равно сохраняется только поверх прочитанной версии, иначе параллельные патчи теряют поля
+	actor.Version = current.Actor.Version
+	err = a.save(res, req, actor)
+	if version == 0 && isPreconditionFailed(err) {
+		return appErrors.Conflict("", "error: actor changed during patch")
+	}
+	return err
+}
+
+// @Summary Удаление актера [Админы]
+// @Description Доступно только админам, ничего не возвращает. Актер переносится в корзину, его можно восстановить
+// @Tags actor
+// @Param id path string true "id актера"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/actors/{id} [delete]
+func (a *actorHandler) DeleteById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return a.ActorUseCase.Delete(req.Context(), id)
+}
+
+// @Summary Актеры фильма
+// @Description Принимает те же query что и список актеров
+// @Tags actor
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во актеров на странице"
+// @Param cursor query string false "токен next/prev из прошлого ответа, вместо page"
+// @Param order-by query string false "asc либо desc"
+// @Param order-field query string false "поле по которому сортируют (name, birthday)"
+// @Success 200 {object} appDto.ActorGetByQueryResult "актеры фильма"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/films/{id}/actors [get]
+func (a *actorHandler) GetByFilm(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	fQuery, err := parseActorQuery(req.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	result, err := a.ActorUseCase.GetByFilm(req.Context(), id, *fQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Добавление актеру участия в фильмах [Админы]
+// @Description Доступно только админам. Роль (actor, director, writer, producer, composer), персонаж и порядок в титрах передаются в credits, filmIds создает роль actor
 // @Tags actor
 // @Accept json
 // @Produce json
@@ -181,10 +424,120 @@ func (a *actorHandler) AddFilm(res http.ResponseWriter, req *http.Request) error
 		_ = req.Body.Close()
 	}()
 
-	err = a.ActorUseCase.AddFilm(req.Context(), body.ActorId, body.FilmIds...)
+	err = a.ActorUseCase.AddFilm(req.Context(), body.ActorId, mapper.AddFilmToActorDtoToCredits(body)...)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// @Summary Замена фильмов актера [Админы]
+// @Description Доступно только админам. Все текущие участия актера заменяются переданными, повторы пропускаются. Пустой объект {} убирает актера из всех фильмов
+// @Tags actor
+// @Accept json
+// @Produce json
+// @Param id path string true "id актера"
+// @Param reg body dto.SetActorFilmsDto true "Новые участия актера"
+// @Success 200 {object} aggregate.ActorAggregate "Актер с новыми фильмами"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Router /http/v2/actors/{id}/films [put]
+func (a *actorHandler) SetFilms(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	var body dto.SetActorFilmsDto
+	if err := httpUtils.BodyJson(req, &body); err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	actor, err := a.ActorUseCase.SetFilms(req.Context(), id, mapper.SetActorFilmsDtoToCredits(id, body)...)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, actor)
+	return nil
+}
+
+// @Summary Удаление фильма у актера [Админы]
+// @Description Доступно только админам, убирает все роли актера в фильме. Отсутствующая связь не ошибка
+// @Tags actor
+// @Param id path string true "id актера"
+// @Param filmId path string true "id фильма"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/actors/{id}/films/{filmId} [delete]
+func (a *actorHandler) RemoveFilm(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+	filmId, err := pathId(req, "filmId")
+	if err != nil {
+		return err
+	}
+
+	return a.ActorUseCase.RemoveFilm(req.Context(), id, filmId)
+}
+
+// parseActorQuery фильтры, сортировка и пагинация списка актеров
+func parseActorQuery(query url.Values) (*domainQuery.ActorRepositoryQuery, error) {
+	fQuery := domainQuery.NewActorRepositoryQuery()
+	if err := parsePage(query, &fQuery.CurrentPage, &fQuery.PageCount); err != nil {
+		return nil, err
+	}
+	if query.Has("connection") {
+		connectionQ := query.Get("connection")
+		if connectionQ != "film" {
+			return nil, appErrors.BadRequest("invalid connection")
+		}
+		fQuery.WithConnection = append(fQuery.WithConnection, connectionQ)
+	}
+	fQuery.Name = strings.TrimSpace(query.Get("name"))
+	if query.Has("gender") {
+		fQuery.Gender = query.Get("gender")
+		if fQuery.Gender != "male" && fQuery.Gender != "female" {
+			return nil, appErrors.BadRequest("invalid gender")
+		}
+	}
+	if err := parseIntRange(query, "birth-year-from", "birth-year-to", &fQuery.BirthYearFrom, &fQuery.BirthYearTo); err != nil {
+		return nil, err
+	}
+	if err := parseIntRange(query, "age-from", "age-to", &fQuery.AgeFrom, &fQuery.AgeTo); err != nil {
+		return nil, err
+	}
+	if query.Has("film") {
+		fQuery.FilmId = query.Get("film")
+		if _, err := uuid.Parse(fQuery.FilmId); err != nil {
+			return nil, appErrors.BadRequest("invalid film")
+		}
+	}
+	if query.Has("order-field") {
+		fQuery.SortField = query.Get("order-field")
+		if fQuery.SortField != "name" && fQuery.SortField != "birthday" {
+			return nil, appErrors.BadRequest("invalid order field")
+		}
+	}
+	if query.Has("order-by") {
+		switch query.Get("order-by") {
+		case "asc":
+			fQuery.OrderBy = domainQuery.Asc
+		case "desc":
+			fQuery.OrderBy = domainQuery.Desc
+		default:
+			return nil, appErrors.BadRequest("invalid order by")
+		}
+	}
+	if err := parseCursor(query, []string{"name", "birthday", ""}, &fQuery.Cursor); err != nil {
+		return nil, err
+	}
+
+	return fQuery, nil
+}
@@ -2,11 +2,24 @@ package httpv1
 
 import (
 	"database/sql"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	revisionService "github.com/OddEer0/vk-filmoteka/internal/app/services/revision_service"
 	tokenService "github.com/OddEer0/vk-filmoteka/internal/app/services/token_service"
 	userService "github.com/OddEer0/vk-filmoteka/internal/app/services/user_service"
 	actorUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/actor_usecase"
+	auditUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/audit_usecase"
 	authUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/auth_usecase"
+	exportUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/export_usecase"
 	filmUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/film_usecase"
+	genreUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/genre_usecase"
+	importUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/import_usecase"
+	ratingUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/rating_usecase"
+	reviewUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/review_usecase"
+	revisionUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/revision_usecase"
+	suggestUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/suggest_usecase"
+	trashUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/trash_usecase"
+	userListUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/user_list_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
 	mockRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/mock_repository"
 	postgresRepository "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/postgres_repository"
 )
@@ -16,33 +29,75 @@ type (
 		AuthHandler
 		FilmHandler
 		ActorHandler
+		GenreHandler
+		RatingHandler
+		ReviewHandler
+		UserListHandler
+		SuggestHandler
+		TrashHandler
+		AuditHandler
+		RevisionHandler
+		ImportHandler
+		ExportHandler
 	}
 )
 
 var instance *AppHandler = nil
 var instance2 *AppHandler = nil
 
-func NewAppHandler(db *sql.DB) *AppHandler {
+func NewAppHandler(db *sql.DB, cfg *config.Config) *AppHandler {
 	if instance != nil {
 		return instance
 	}
 
 	userRepo := postgresRepository.NewUserRepository(db)
 	tokenRepo := postgresRepository.NewTokenRepository(db)
-	actorRepo := postgresRepository.NewActorRepository(db)
-	filmRepo := postgresRepository.NewFilmRepository(db)
+	actorRepo := postgresRepository.NewActorRepository(db, cfg.HonorManualRate)
+	filmRepo := postgresRepository.NewFilmRepository(db, cfg.HonorManualRate)
+	genreRepo := postgresRepository.NewGenreRepository(db)
+	ratingRepo := postgresRepository.NewRatingRepository(db, cfg.HonorManualRate)
+	reviewRepo := postgresRepository.NewReviewRepository(db)
+	userListRepo := postgresRepository.NewUserListRepository(db, cfg.HonorManualRate)
+	suggestRepo := postgresRepository.NewSuggestRepository(db)
+	txManager := postgresRepository.NewTxManager(db)
+	auditRepo := postgresRepository.NewAuditRepository(db)
+	revisionRepo := postgresRepository.NewRevisionRepository(db)
+	imdbRepo := postgresRepository.NewImdbRepository(db)
+	externalIdRepo := postgresRepository.NewExternalIdRepository(db)
 
 	userServ := userService.New(userRepo)
 	tokenServ := tokenService.New(tokenRepo)
+	auditServ := auditService.New(auditRepo, txManager)
+	revisionServ := revisionService.New(revisionRepo, filmRepo, actorRepo)
 
-	authUsecase := authUseCase.New(userServ, tokenServ, userRepo)
-	actorUsecase := actorUseCase.New(actorRepo, filmRepo)
-	filmUsecase := filmUseCase.New(filmRepo)
+	authUsecase := authUseCase.New(userServ, tokenServ, userRepo, txManager)
+	actorUsecase := actorUseCase.New(actorRepo, filmRepo, externalIdRepo, auditServ, revisionServ)
+	filmUsecase := filmUseCase.New(filmRepo, actorRepo, externalIdRepo, auditServ, revisionServ, cfg.HonorManualRate)
+	genreUsecase := genreUseCase.New(genreRepo, auditServ)
+	ratingUsecase := ratingUseCase.New(ratingRepo)
+	reviewUsecase := reviewUseCase.New(reviewRepo, filmRepo, auditServ)
+	userListUsecase := userListUseCase.New(userListRepo)
+	suggestUsecase := suggestUseCase.New(suggestRepo)
+	trashUsecase := trashUseCase.New(filmRepo, actorRepo, auditServ)
+	auditUsecase := auditUseCase.New(auditRepo)
+	revisionUsecase := revisionUseCase.New(revisionRepo, filmRepo, actorRepo, auditServ, revisionServ, cfg.HonorManualRate)
+	importUsecase := importUseCase.New(filmRepo, actorRepo, imdbRepo, externalIdRepo, txManager, auditServ, revisionServ, cfg.HonorManualRate)
+	exportUsecase := exportUseCase.New(filmRepo, actorRepo)
 
 	instance = &AppHandler{
-		AuthHandler:  NewAuthHandler(authUsecase),
-		FilmHandler:  NewFilmHandler(filmUsecase),
-		ActorHandler: NewActorHandler(actorUsecase),
+		AuthHandler:     NewAuthHandler(authUsecase),
+		FilmHandler:     NewFilmHandler(filmUsecase),
+		ActorHandler:    NewActorHandler(actorUsecase),
+		GenreHandler:    NewGenreHandler(genreUsecase),
+		RatingHandler:   NewRatingHandler(ratingUsecase),
+		ReviewHandler:   NewReviewHandler(reviewUsecase),
+		UserListHandler: NewUserListHandler(userListUsecase),
+		SuggestHandler:  NewSuggestHandler(suggestUsecase),
+		TrashHandler:    NewTrashHandler(trashUsecase),
+		AuditHandler:    NewAuditHandler(auditUsecase),
+		RevisionHandler: NewRevisionHandler(revisionUsecase),
+		ImportHandler:   NewImportHandler(importUsecase),
+		ExportHandler:   NewExportHandler(exportUsecase),
 	}
 
 	return instance
@@ -57,18 +112,51 @@ func NewAppHandlerMock() *AppHandler {
 	tokenRepo := mockRepository.NewTokenRepository()
 	actorRepo := mockRepository.NewActorRepository()
 	filmRepo := mockRepository.NewFilmRepository()
+	genreRepo := mockRepository.NewGenreRepository()
+	ratingRepo := mockRepository.NewRatingRepository()
+	reviewRepo := mockRepository.NewReviewRepository()
+	userListRepo := mockRepository.NewUserListRepository()
+	suggestRepo := mockRepository.NewSuggestRepository()
+	txManager := mockRepository.NewTxManager()
+	auditRepo := mockRepository.NewAuditRepository()
+	revisionRepo := mockRepository.NewRevisionRepository()
+	imdbRepo := mockRepository.NewImdbRepository()
+	externalIdRepo := mockRepository.NewExternalIdRepository()
 
 	userServ := userService.New(userRepo)
 	tokenServ := tokenService.New(tokenRepo)
+	auditServ := auditService.New(auditRepo, txManager)
+	revisionServ := revisionService.New(revisionRepo, filmRepo, actorRepo)
 
-	authUsecase := authUseCase.New(userServ, tokenServ, userRepo)
-	actorUsecase := actorUseCase.New(actorRepo, filmRepo)
-	filmUsecase := filmUseCase.New(filmRepo)
+	authUsecase := authUseCase.New(userServ, tokenServ, userRepo, txManager)
+	actorUsecase := actorUseCase.New(actorRepo, filmRepo, externalIdRepo, auditServ, revisionServ)
+	// в моке ручная оценка админа учитывается, чтобы данные фильмов в тестах оставались предсказуемыми
+	filmUsecase := filmUseCase.New(filmRepo, actorRepo, externalIdRepo, auditServ, revisionServ, true)
+	genreUsecase := genreUseCase.New(genreRepo, auditServ)
+	ratingUsecase := ratingUseCase.New(ratingRepo)
+	reviewUsecase := reviewUseCase.New(reviewRepo, filmRepo, auditServ)
+	userListUsecase := userListUseCase.New(userListRepo)
+	suggestUsecase := suggestUseCase.New(suggestRepo)
+	trashUsecase := trashUseCase.New(filmRepo, actorRepo, auditServ)
+	auditUsecase := auditUseCase.New(auditRepo)
+	revisionUsecase := revisionUseCase.New(revisionRepo, filmRepo, actorRepo, auditServ, revisionServ, true)
+	importUsecase := importUseCase.New(filmRepo, actorRepo, imdbRepo, externalIdRepo, txManager, auditServ, revisionServ, true)
+	exportUsecase := exportUseCase.New(filmRepo, actorRepo)
 
 	instance2 = &AppHandler{
-		AuthHandler:  NewAuthHandler(authUsecase),
-		FilmHandler:  NewFilmHandler(filmUsecase),
-		ActorHandler: NewActorHandler(actorUsecase),
+		AuthHandler:     NewAuthHandler(authUsecase),
+		FilmHandler:     NewFilmHandler(filmUsecase),
+		ActorHandler:    NewActorHandler(actorUsecase),
+		GenreHandler:    NewGenreHandler(genreUsecase),
+		RatingHandler:   NewRatingHandler(ratingUsecase),
+		ReviewHandler:   NewReviewHandler(reviewUsecase),
+		UserListHandler: NewUserListHandler(userListUsecase),
+		SuggestHandler:  NewSuggestHandler(suggestUsecase),
+		TrashHandler:    NewTrashHandler(trashUsecase),
+		AuditHandler:    NewAuditHandler(auditUsecase),
+		RevisionHandler: NewRevisionHandler(revisionUsecase),
+		ImportHandler:   NewImportHandler(importUsecase),
+		ExportHandler:   NewExportHandler(exportUsecase),
 	}
 
 	return instance2
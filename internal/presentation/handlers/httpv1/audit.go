@@ -0,0 +1,68 @@
+package httpv1
+
+import (
+	"net/http"
+	"slices"
+
+	auditUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/audit_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+)
+
+type (
+	AuditHandler interface {
+		GetByQuery(res http.ResponseWriter, req *http.Request) error
+	}
+
+	auditHandler struct {
+		auditUseCase.AuditUseCase
+	}
+)
+
+func NewAuditHandler(useCase auditUseCase.AuditUseCase) AuditHandler {
+	return &auditHandler{
+		AuditUseCase: useCase,
+	}
+}
+
+// @Summary Журнал изменений [Админы]
+// @Description Доступно только админам, от новых записей к старым. diff - изменившиеся поля {"путь": {"before", "after"}}
+// @Tags audit
+// @Produce json
+// @Param user query string false "id пользователя"
+// @Param entity-type query string false "тип сущности (film, actor, genre, review, trash)"
+// @Param entity-id query string false "id сущности"
+// @Param from query string false "начиная с (RFC3339 или 2000-12-31)"
+// @Param to query string false "заканчивая (RFC3339 или 2000-12-31 включительно)"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во записей на странице"
+// @Success 200 {object} appDto.AuditGetByQueryResult "Записи журнала"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v1/audit [get]
+func (a *auditHandler) GetByQuery(res http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	aQuery := domainQuery.NewAuditRepositoryQuery()
+	if err := parsePage(query, &aQuery.CurrentPage, &aQuery.PageCount); err != nil {
+		return err
+	}
+	if err := parseTimeRange(query, "from", "to", &aQuery.From, &aQuery.To); err != nil {
+		return err
+	}
+	aQuery.UserId, aQuery.EntityId = query.Get("user"), query.Get("entity-id")
+	if query.Has("entity-type") {
+		aQuery.EntityType = query.Get("entity-type")
+		if !slices.Contains(constants.AuditEntityTypes, aQuery.EntityType) {
+			return appErrors.BadRequest("invalid entity-type")
+		}
+	}
+
+	result, err := a.AuditUseCase.GetByQuery(req.Context(), *aQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
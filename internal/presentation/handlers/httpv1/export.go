@@ -0,0 +1,148 @@
+package httpv1
+
+import (
+	"net/http"
+	"net/url"
+
+	exportUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/export_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+)
+
+type (
+	ExportHandler interface {
+		Films(res http.ResponseWriter, req *http.Request) error
+		Actors(res http.ResponseWriter, req *http.Request) error
+		Credits(res http.ResponseWriter, req *http.Request) error
+	}
+
+	exportHandler struct {
+		exportUseCase.ExportUseCase
+	}
+)
+
+func NewExportHandler(useCase exportUseCase.ExportUseCase) ExportHandler {
+	return &exportHandler{
+		ExportUseCase: useCase,
+	}
+}
+
+// ExportFilmQuery фильтры и сортировка выгрузки фильмов с теми же параметрами, что у списка фильмов. Страницы не учитываются
+func ExportFilmQuery(query url.Values) (*domainQuery.FilmRepositoryQuery, error) {
+	return parseFilmQuery(query)
+}
+
+// ExportActorQuery как ExportFilmQuery для актеров
+func ExportActorQuery(query url.Values) (*domainQuery.ActorRepositoryQuery, error) {
+	return parseActorQuery(query)
+}
+
+// @Summary Выгрузка фильмов [Админы]
+// @Description Доступно только админам. Все фильмы под фильтр без страниц, ответ отдается по мере чтения из базы.
+// @Description Колонки: id, name, description, release, rate - файл можно снова загрузить импортом
+// @Tags export
+// @Produce json,plain
+// @Param format query string false "json (по умолчанию, один массив), ndjson или csv"
+// @Param genre query []string false "id жанров для фильтра" collectionFormat(multi)
+// @Param genre-match query string false "any - хотя бы один жанр, all - все жанры (по умолчанию any)"
+// @Param actor query []string false "id актеров для фильтра" collectionFormat(multi)
+// @Param actor-match query string false "any - хотя бы один актер, all - все актеры (по умолчанию any)"
+// @Param release-from query string false "дата выхода от, год (1990) либо дата (1990-05-20)"
+// @Param release-to query string false "дата выхода до включительно, год (2000) либо дата (2000-12-31)"
+// @Param rate-from query number false "рейтинг от (0-10)"
+// @Param rate-to query number false "рейтинг до (0-10)"
+// @Param order-by query string false "asc либо desc"
+// @Param order-field query string false "поле по которому сортируют (rate, name, release_date)"
+// @Success 200 {string} string "Выгрузка"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v2/export/films [get]
+func (e *exportHandler) Films(res http.ResponseWriter, req *http.Request) error {
+	fQuery, err := ExportFilmQuery(req.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	return stream(res, req, constants.ImportFilms, func(out httpUtils.StreamWriter) error {
+		return e.ExportUseCase.Films(req.Context(), *fQuery, out)
+	})
+}
+
+// @Summary Выгрузка актеров [Админы]
+// @Description Доступно только админам. Все актеры под фильтр без страниц, ответ отдается по мере чтения из базы.
+// @Description Колонки: id, name, gender, birthday - файл можно снова загрузить импортом
+// @Tags export
+// @Produce json,plain
+// @Param format query string false "json (по умолчанию, один массив), ndjson или csv"
+// @Param name query string false "поиск по имени, допускает опечатки"
+// @Param gender query string false "пол (male, female)"
+// @Param birth-year-from query int false "год рождения от"
+// @Param birth-year-to query int false "год рождения до"
+// @Param age-from query int false "возраст от"
+// @Param age-to query int false "возраст до"
+// @Param film query string false "id фильма, в котором участвовал актер"
+// @Param order-field query string false "поле сортировки (name, birthday), по умолчанию id"
+// @Param order-by query string false "направление сортировки (asc, desc)"
+// @Success 200 {string} string "Выгрузка"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v2/export/actors [get]
+func (e *exportHandler) Actors(res http.ResponseWriter, req *http.Request) error {
+	aQuery, err := ExportActorQuery(req.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	return stream(res, req, constants.ImportActors, func(out httpUtils.StreamWriter) error {
+		return e.ExportUseCase.Actors(req.Context(), *aQuery, out)
+	})
+}
+
+// @Summary Выгрузка титров [Админы]
+// @Description Доступно только админам. Титры фильмов под фильтр, фильтры как у выгрузки фильмов.
+// @Description Колонки: film, filmName, filmRelease, actor, actorName, actorBirthday, role, character, billingOrder
+// @Tags export
+// @Produce json,plain
+// @Param format query string false "json (по умолчанию, один массив), ndjson или csv"
+// @Param genre query []string false "id жанров для фильтра" collectionFormat(multi)
+// @Param actor query []string false "id актеров для фильтра" collectionFormat(multi)
+// @Param release-from query string false "дата выхода от, год (1990) либо дата (1990-05-20)"
+// @Param release-to query string false "дата выхода до включительно, год (2000) либо дата (2000-12-31)"
+// @Param order-field query string false "сортировка фильмов (rate, name, release_date)"
+// @Success 200 {string} string "Выгрузка"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v2/export/credits [get]
+func (e *exportHandler) Credits(res http.ResponseWriter, req *http.Request) error {
+	fQuery, err := ExportFilmQuery(req.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	return stream(res, req, constants.ImportCredits, func(out httpUtils.StreamWriter) error {
+		return e.ExportUseCase.Credits(req.Context(), *fQuery, out)
+	})
+}
+
+// stream отдает выгрузку kind в формате из параметра format. Ошибка до первых отправленных байт - обычный ответ с ошибкой,
+// после них статус уже не поменять, и соединение обрывается, чтобы клиент не принял обрезанный файл за целый
+func stream(res http.ResponseWriter, req *http.Request, kind string, export func(out httpUtils.StreamWriter) error) error {
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = httpUtils.StreamJson
+	}
+	out, err := httpUtils.NewStreamWriter(res, format, constants.CatalogColumns[kind])
+	if err != nil {
+		return appErrors.BadRequest("", "incorrect format: ", format)
+	}
+
+	httpUtils.SetStreamHeaders(res, format, kind)
+	if err := export(out); err != nil {
+		if out.Started() {
+			panic(http.ErrAbortHandler)
+		}
+		res.Header().Del("Content-Type")
+		res.Header().Del("Content-Disposition")
+		return err
+	}
+	return nil
+}
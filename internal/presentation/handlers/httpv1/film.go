@@ -7,8 +7,12 @@ import (
 	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
 	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/dto"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/mapper"
 	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/google/uuid"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
@@ -19,6 +23,16 @@ type (
 		GetByQuery(res http.ResponseWriter, req *http.Request) error
 		Update(res http.ResponseWriter, req *http.Request) error
 		SearchByNameAndActorName(res http.ResponseWriter, req *http.Request) error
+		GetById(res http.ResponseWriter, req *http.Request) error
+		GetByExternalId(res http.ResponseWriter, req *http.Request) error
+		UpdateById(res http.ResponseWriter, req *http.Request) error
+		Patch(res http.ResponseWriter, req *http.Request) error
+		PatchById(res http.ResponseWriter, req *http.Request) error
+		DeleteById(res http.ResponseWriter, req *http.Request) error
+		GetByActor(res http.ResponseWriter, req *http.Request) error
+		SetCast(res http.ResponseWriter, req *http.Request) error
+		RemoveActor(res http.ResponseWriter, req *http.Request) error
+		CreateWithCast(res http.ResponseWriter, req *http.Request) error
 	}
 
 	filmHandler struct {
@@ -33,7 +47,7 @@ func NewFilmHandler(useCase filmUseCase.FilmUseCase) FilmHandler {
 }
 
 // @Summary Создание фильма [Админы]
-// @Description Доступно только админам
+// @Description Доступно только админам. actorIds и actors сразу добавляют состав, фильм, новые актеры и связи сохраняются одной транзакцией
 // @Tags film
 // @Accept json
 // @Produce json
@@ -61,7 +75,7 @@ func (f *filmHandler) Create(res http.ResponseWriter, req *http.Request) error {
 }
 
 // @Summary Создание фильма [Админы]
-// @Description Доступно только админам, ничего не возвоащает
+// @Description Доступно только админам, ничего не возвоащает. Фильм переносится в корзину, его можно восстановить
 // @Tags film
 // @Accept json
 // @Produce json
@@ -89,61 +103,26 @@ func (f *filmHandler) Delete(res http.ResponseWriter, req *http.Request) error {
 // @Produce json
 // @Param page query string false "текущая страница"
 // @Param page-count query string false "кол-во актеров на странице"
-// @Param connection query string false "Вернуть вместе со связями (film)"
+// @Param cursor query string false "токен next/prev из прошлого ответа, вместо page. Сортировка берется из токена, фильтры нужно передать снова"
+// @Param connection query []string false "Вернуть вместе со связями (actor, genre)" collectionFormat(multi)
+// @Param genre query []string false "id жанров для фильтра" collectionFormat(multi)
+// @Param genre-match query string false "any - хотя бы один жанр, all - все жанры (по умолчанию any)"
+// @Param actor query []string false "id актеров для фильтра" collectionFormat(multi)
+// @Param actor-match query string false "any - хотя бы один актер, all - все актеры (по умолчанию any)"
+// @Param release-from query string false "дата выхода от, год (1990) либо дата (1990-05-20)"
+// @Param release-to query string false "дата выхода до включительно, год (2000) либо дата (2000-12-31)"
+// @Param rate-from query number false "рейтинг от (0-10)"
+// @Param rate-to query number false "рейтинг до (0-10)"
 // @Param order-by query string false "asc либо desc"
 // @Param order-field query string false "поле по которому сортируют (rate, name, release_date)"
 // @Success 200 {object} appDto.FilmGetByQueryResult "получаемые фильмы"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
 // @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
 // @Router /http/v1/film [get]
 func (f *filmHandler) GetByQuery(res http.ResponseWriter, req *http.Request) error {
-	fQuery := domainQuery.NewFilmRepositoryQuery()
-	var err error
-
-	query := req.URL.Query()
-	if query.Has("page") {
-		fQuery.CurrentPage, err = strconv.Atoi(query.Get("page"))
-		if err != nil {
-			return appErrors.BadRequest("")
-		}
-	}
-	if query.Has("page-count") {
-		fQuery.PageCount, err = strconv.Atoi(query.Get("page-count"))
-		if err != nil {
-			return appErrors.BadRequest("")
-		}
-	}
-	if query.Has("connection") {
-		conn := query.Get("connection")
-		if conn != "actor" {
-			return appErrors.BadRequest("")
-		}
-		fQuery.WithConnection = append(fQuery.WithConnection, conn)
-	}
-	if query.Has("order-by") {
-		order := query.Get("order-by")
-		switch order {
-		case "asc":
-			fQuery.OrderBy = domainQuery.Asc
-		case "desc":
-			fQuery.OrderBy = domainQuery.Desc
-		default:
-			return appErrors.BadRequest("")
-		}
-	}
-	if query.Has("order-field") {
-		field := query.Get("order-field")
-		correctFields := []string{"name", "release_date", "rate"}
-		has := false
-		for _, correctField := range correctFields {
-			if field == correctField {
-				has = true
-			}
-		}
-		if !has {
-			return appErrors.BadRequest("")
-		}
-
-		fQuery.SortField = field
+	fQuery, err := parseFilmQuery(req.URL.Query())
+	if err != nil {
+		return err
 	}
 
 	result, err := f.FilmUseCase.GetByQuery(req.Context(), *fQuery)
@@ -156,16 +135,18 @@ func (f *filmHandler) GetByQuery(res http.ResponseWriter, req *http.Request) err
 }
 
 // @Summary Создание фильма [Админы]
-// @Description Доступно только админам
+// @Description Доступно только админам. manualRate без поля не меняется, null сбрасывает ручную оценку
 // @Tags film
 // @Accept json
 // @Produce json
-// @Param reg body model.Film true "Данные фильма"
+// @Param reg body dto.UpdateFilmBody true "Данные фильма"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
 // @Success 200 {object} model.Film "Данные обновленного фильма"
 // @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
 // @Router /http/v1/film [put]
 func (f *filmHandler) Update(res http.ResponseWriter, req *http.Request) error {
-	var body model.Film
+	var body dto.UpdateFilmBody
 	err := httpUtils.BodyJson(req, &body)
 	if err != nil {
 		return appErrors.BadRequest("")
@@ -174,27 +155,306 @@ func (f *filmHandler) Update(res http.ResponseWriter, req *http.Request) error {
 		_ = req.Body.Close()
 	}()
 
-	filmAggregate, err := aggregate.NewFilmAggregate(body)
+	return f.update(res, req, body)
+}
+
+// update валидирует фильм целиком и сохраняет его как PUT.
+// Версия берется только из If-Match, version в теле игнорируется
+func (f *filmHandler) update(res http.ResponseWriter, req *http.Request, body dto.UpdateFilmBody) error {
+	version, err := httpUtils.IfMatchVersion(req)
+	if err != nil {
+		return appErrors.PreconditionFailed("", "error: ", err.Error())
+	}
+	film := body.Film
+	film.Version, film.ManualRate = version, body.ManualRate.Value
+	return f.save(res, req, film, body.ManualRate.Set)
+}
+
+// save валидирует и сохраняет фильм с версией film.Version, 0 - без проверки версии.
+// Ручная оценка film.ManualRate меняется только при setManualRate
+func (f *filmHandler) save(res http.ResponseWriter, req *http.Request, film model.Film, setManualRate bool) error {
+	filmAggregate, err := aggregate.NewFilmAggregate(film)
 	if err != nil {
 		return appErrors.UnprocessableEntity("")
 	}
 
-	filmAggregate, err = f.FilmUseCase.Update(req.Context(), filmAggregate)
+	filmAggregate, err = f.FilmUseCase.Update(req.Context(), filmAggregate, setManualRate)
 	if err != nil {
 		return err
 	}
 
+	res.Header().Set("ETag", filmETag(&filmAggregate.Film))
 	httpUtils.SendJson(res, http.StatusOK, filmAggregate.Film)
 	return nil
 }
 
+// @Summary Создание фильма с составом [Админы]
+// @Description Доступно только админам. Фильм, новые актеры из actors и связи с actorIds сохраняются одной транзакцией, при ошибке не создается ничего
+// @Tags film
+// @Accept json
+// @Produce json
+// @Param reg body appDto.CreateFilmUseCaseDto true "Данные фильма и состав"
+// @Success 200 {object} aggregate.FilmAggregate "Созданный фильм с актерами"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 409 {object} appErrors.ResponseError "Внешний идентификатор уже у другой записи"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Router /http/v2/films [post]
+func (f *filmHandler) CreateWithCast(res http.ResponseWriter, req *http.Request) error {
+	var body appDto.CreateFilmUseCaseDto
+	if err := httpUtils.BodyJson(req, &body); err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	filmAggregate, err := f.FilmUseCase.Create(req.Context(), body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, filmAggregate)
+	return nil
+}
+
+// @Summary Получение фильма по id
+// @Description Фильм вместе с актерами и жанрами
+// @Tags film
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param If-None-Match header string false "ETag из прошлого ответа, при совпадении 304 без тела"
+// @Success 200 {object} aggregate.FilmAggregate "Данные фильма"
+// @Header 200 {string} ETag "версия записи и хеш оценки пользователей"
+// @Success 304 "Не изменился"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/films/{id} [get]
+func (f *filmHandler) GetById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	film, err := f.FilmUseCase.GetById(req.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	etag := filmETag(&film.Film)
+	res.Header().Set("ETag", etag)
+	if httpUtils.NotModified(req, etag) {
+		res.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, film)
+	return nil
+}
+
+// @Summary Получение фильма по внешнему идентификатору
+// @Description Поиск по идентификатору IMDb, TMDB или Кинопоиска, например /external/imdb/tt0111161/film
+// @Tags film
+// @Produce json
+// @Param source path string true "источник: imdb, tmdb или kinopoisk"
+// @Param externalId path string true "идентификатор в источнике"
+// @Success 200 {object} aggregate.FilmAggregate "Данные фильма"
+// @Failure 400 {object} appErrors.ResponseError "Неизвестный источник"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/external/{source}/{externalId}/film [get]
+func (f *filmHandler) GetByExternalId(res http.ResponseWriter, req *http.Request) error {
+	film, err := f.FilmUseCase.GetByExternalId(req.Context(), req.PathValue("source"), req.PathValue("externalId"))
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, film)
+	return nil
+}
+
+// @Summary Замена фильма [Админы]
+// @Description Доступно только админам, фильм передается целиком, id берется из пути. manualRate без поля не меняется, null сбрасывает ручную оценку
+// @Tags film
+// @Accept json
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param reg body dto.UpdateFilmBody true "Данные фильма"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
+// @Success 200 {object} model.Film "Данные обновленного фильма"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
+// @Router /http/v2/films/{id} [put]
+func (f *filmHandler) UpdateById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	var body dto.UpdateFilmBody
+	if err := httpUtils.DecodeJson(req, &body); err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	body.Id = id
+	return f.update(res, req, body)
+}
+
+// @Summary Частичное обновление фильма [Админы]
+// @Description Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, поэтому {"description": null} очищает описание. Проверяется только итоговый фильм
+// @Tags film
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id query string true "id фильма"
+// @Param reg body model.Film true "Изменяемые поля фильма"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
+// @Success 200 {object} model.Film "Данные обновленного фильма"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 415 {object} appErrors.ResponseError "Ошибка 415"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
+// @Failure 409 {object} appErrors.ResponseError "Ошибка 409"
+// @Router /http/v1/film [patch]
+func (f *filmHandler) Patch(res http.ResponseWriter, req *http.Request) error {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		return appErrors.BadRequest("")
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return appErrors.NotFound("")
+	}
+
+	return f.patch(res, req, id)
+}
+
+// @Summary Частичное обновление фильма [Админы]
+// @Description Доступно только админам. Тело - JSON Merge Patch (RFC 7396), null удаляет поле, поэтому {"description": null} очищает описание. Проверяется только итоговый фильм
+// @Tags film
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param reg body model.Film true "Изменяемые поля фильма"
+// @Param If-Match header string false "ETag из прошлого ответа, при устаревшей версии 412"
+// @Success 200 {object} model.Film "Данные обновленного фильма"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 415 {object} appErrors.ResponseError "Ошибка 415"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Failure 412 {object} appErrors.ResponseError "Ошибка 412"
+// @Failure 409 {object} appErrors.ResponseError "Ошибка 409"
+// @Router /http/v2/films/{id} [patch]
+func (f *filmHandler) PatchById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return f.patch(res, req, id)
+}
+
+// filmETag оценки пользователей не меняют версию фильма, но входят в ответ, поэтому попадают в ETag отдельно
+func filmETag(film *model.Film) string {
+	return httpUtils.ETag(film.Version, strconv.FormatFloat(float64(film.Rate), 'f', 1, 32), strconv.Itoa(film.VoteCount))
+}
+
+// patch накладывает merge patch на текущего фильм и сохраняет результат как PUT
+func (f *filmHandler) patch(res http.ResponseWriter, req *http.Request, id string) error {
+	if !httpUtils.IsMergePatch(req) {
+		return appErrors.UnsupportedMediaType("", "expected content type: ", httpUtils.MergePatchContentType)
+	}
+
+	current, err := f.FilmUseCase.GetById(req.Context(), id)
+	if err != nil {
+		return err
+	}
+	// устаревший If-Match отклоняется до применения патча, иначе ошибка валидации скрыла бы конфликт
+	version, err := httpUtils.IfMatchVersion(req)
+	if err != nil || (version != 0 && version != current.Film.Version) {
+		return appErrors.PreconditionFailed("")
+	}
+
+	film := current.Film
+	if err := httpUtils.MergePatchJson(req, &film); err != nil {
+		return appErrors.BadRequest("", "error: ", err.Error())
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	film.Id = id
+	// без If-Match патч все равно сохраняется только поверх прочитанной версии, иначе параллельные патчи теряют поля
+	film.Version = current.Film.Version
+	// после патча manualRate равна текущей, если ее не передали, и nil, если передали null
+	err = f.save(res, req, film, true)
+	if version == 0 && isPreconditionFailed(err) {
+		return appErrors.Conflict("", "error: film changed during patch")
+	}
+	return err
+}
+
+// @Summary Удаление фильма [Админы]
+// @Description Доступно только админам, ничего не возвращает. Фильм переносится в корзину, его можно восстановить
+// @Tags film
+// @Param id path string true "id фильма"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/films/{id} [delete]
+func (f *filmHandler) DeleteById(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return f.FilmUseCase.Delete(req.Context(), id)
+}
+
+// @Summary Фильмы актера
+// @Description Принимает те же query что и список фильмов
+// @Tags film
+// @Produce json
+// @Param id path string true "id актера"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во фильмов на странице"
+// @Param cursor query string false "токен next/prev из прошлого ответа, вместо page"
+// @Param order-by query string false "asc либо desc"
+// @Param order-field query string false "поле по которому сортируют (rate, name, release_date)"
+// @Success 200 {object} appDto.FilmGetByQueryResult "фильмы актера"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/actors/{id}/films [get]
+func (f *filmHandler) GetByActor(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	fQuery, err := parseFilmQuery(req.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	result, err := f.FilmUseCase.GetByActor(req.Context(), id, *fQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
 // @Summary Поиск фильма
-// @Description поиск по имени и имени актеров
+// @Description полнотекстовый поиск по названию, описанию и именам актеров. Сначала фильмы с совпадением в названии, совпадения выделены тегом <b>
 // @Tags film
 // @Accept json
 // @Produce json
 // @Param search query string false "параметр поиска"
+// @Param page query int false "текущая страница"
+// @Param page-count query int false "кол-во фильмов на странице"
 // @Success 200 {object} appDto.FilmGetByQueryResult "получаемые фильмы"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
 // @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
 // @Router /http/v1/film/search [get]
 func (f *filmHandler) SearchByNameAndActorName(res http.ResponseWriter, req *http.Request) error {
@@ -208,7 +468,12 @@ func (f *filmHandler) SearchByNameAndActorName(res http.ResponseWriter, req *htt
 		return appErrors.BadRequest("searched value min 3 chars")
 	}
 
-	result, err := f.FilmUseCase.SearchByNameAndActorName(req.Context(), searchedValue)
+	searchQuery := domainQuery.NewFilmSearchQuery(searchedValue)
+	if err := parsePage(query, &searchQuery.CurrentPage, &searchQuery.PageCount); err != nil {
+		return err
+	}
+
+	result, err := f.FilmUseCase.SearchByNameAndActorName(req.Context(), *searchQuery)
 	if err != nil {
 		return err
 	}
@@ -216,3 +481,124 @@ func (f *filmHandler) SearchByNameAndActorName(res http.ResponseWriter, req *htt
 	httpUtils.SendJson(res, http.StatusOK, result)
 	return nil
 }
+
+// @Summary Замена состава фильма [Админы]
+// @Description Доступно только админам. Все текущие связи фильма с актерами заменяются переданными, повторы пропускаются. Пустой объект {} убирает всех актеров
+// @Tags film
+// @Accept json
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param reg body dto.SetCastDto true "Новый состав фильма"
+// @Success 200 {object} aggregate.FilmAggregate "Фильм с новым составом"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Failure 422 {object} appErrors.ResponseError "Ошибка 422"
+// @Router /http/v2/films/{id}/actors [put]
+func (f *filmHandler) SetCast(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	var body dto.SetCastDto
+	if err := httpUtils.BodyJson(req, &body); err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	film, err := f.FilmUseCase.SetCast(req.Context(), id, mapper.SetCastDtoToCredits(id, body)...)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, film)
+	return nil
+}
+
+// @Summary Удаление актера из фильма [Админы]
+// @Description Доступно только админам, убирает все роли актера в фильме. Отсутствующая связь не ошибка
+// @Tags film
+// @Param id path string true "id фильма"
+// @Param actorId path string true "id актера"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v2/films/{id}/actors/{actorId} [delete]
+func (f *filmHandler) RemoveActor(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+	actorId, err := pathId(req, "actorId")
+	if err != nil {
+		return err
+	}
+
+	return f.FilmUseCase.RemoveActor(req.Context(), id, actorId)
+}
+
+// parseFilmQuery фильтры, сортировка и пагинация списка фильмов
+func parseFilmQuery(query url.Values) (*domainQuery.FilmRepositoryQuery, error) {
+	fQuery := domainQuery.NewFilmRepositoryQuery()
+
+	if err := parsePage(query, &fQuery.CurrentPage, &fQuery.PageCount); err != nil {
+		return nil, err
+	}
+	if query.Has("connection") {
+		for _, conn := range query["connection"] {
+			if conn != "actor" && conn != "genre" {
+				return nil, appErrors.BadRequest("")
+			}
+			fQuery.WithConnection = append(fQuery.WithConnection, conn)
+		}
+	}
+	if err := parseIds(query, "genre", &fQuery.Genres); err != nil {
+		return nil, err
+	}
+	if err := parseMatchMode(query, "genre-match", &fQuery.GenreMatch); err != nil {
+		return nil, err
+	}
+	if err := parseIds(query, "actor", &fQuery.Actors); err != nil {
+		return nil, err
+	}
+	if err := parseMatchMode(query, "actor-match", &fQuery.ActorMatch); err != nil {
+		return nil, err
+	}
+	if err := parseDateRange(query, "release-from", "release-to", &fQuery.ReleaseDateFrom, &fQuery.ReleaseDateTo); err != nil {
+		return nil, err
+	}
+	if err := parseRateRange(query, "rate-from", "rate-to", &fQuery.RateFrom, &fQuery.RateTo); err != nil {
+		return nil, err
+	}
+	if query.Has("order-by") {
+		order := query.Get("order-by")
+		switch order {
+		case "asc":
+			fQuery.OrderBy = domainQuery.Asc
+		case "desc":
+			fQuery.OrderBy = domainQuery.Desc
+		default:
+			return nil, appErrors.BadRequest("")
+		}
+	}
+	if query.Has("order-field") {
+		field := query.Get("order-field")
+		correctFields := []string{"name", "release_date", "rate"}
+		has := false
+		for _, correctField := range correctFields {
+			if field == correctField {
+				has = true
+			}
+		}
+		if !has {
+			return nil, appErrors.BadRequest("")
+		}
+
+		fQuery.SortField = field
+	}
+	if err := parseCursor(query, []string{"name", "release_date", "rate"}, &fQuery.Cursor); err != nil {
+		return nil, err
+	}
+
+	return fQuery, nil
+}
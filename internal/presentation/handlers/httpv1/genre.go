@@ -0,0 +1,165 @@
+package httpv1
+
+import (
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	genreUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/genre_usecase"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/dto"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"net/http"
+)
+
+type (
+	GenreHandler interface {
+		Create(res http.ResponseWriter, req *http.Request) error
+		Delete(res http.ResponseWriter, req *http.Request) error
+		GetAll(res http.ResponseWriter, req *http.Request) error
+		Update(res http.ResponseWriter, req *http.Request) error
+		AddFilm(res http.ResponseWriter, req *http.Request) error
+		RemoveFilm(res http.ResponseWriter, req *http.Request) error
+	}
+
+	genreHandler struct {
+		genreUseCase.GenreUseCase
+	}
+)
+
+func NewGenreHandler(useCase genreUseCase.GenreUseCase) GenreHandler {
+	return &genreHandler{
+		GenreUseCase: useCase,
+	}
+}
+
+// @Summary Создание жанра [Админы]
+// @Description Доступно только админам
+// @Tags genre
+// @Accept json
+// @Produce json
+// @Param reg body appDto.CreateGenreUseCaseDto true "Данные жанра"
+// @Success 200 {object} model.Genre "Данные созданного жанра"
+// @Failure 409 {object} appErrors.ResponseError "Жанр уже существует"
+// @Router /http/v1/genre [post]
+func (g *genreHandler) Create(res http.ResponseWriter, req *http.Request) error {
+	var body appDto.CreateGenreUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	genre, err := g.GenreUseCase.Create(req.Context(), body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, genre)
+	return nil
+}
+
+// @Summary Удаление жанра [Админы]
+// @Description Доступно только админам, ничего ответом не возвращает. Связи с фильмами удаляются
+// @Tags genre
+// @Accept json
+// @Produce json
+// @Param id query string true "id удаляемого жанра"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v1/genre [delete]
+func (g *genreHandler) Delete(res http.ResponseWriter, req *http.Request) error {
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		return appErrors.BadRequest("")
+	}
+
+	return g.GenreUseCase.Delete(req.Context(), id)
+}
+
+// @Summary Получение всех жанров
+// @Tags genre
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Genre "Жанры отсортированные по названию"
+// @Router /http/v1/genre [get]
+func (g *genreHandler) GetAll(res http.ResponseWriter, req *http.Request) error {
+	genres, err := g.GenreUseCase.GetAll(req.Context())
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, genres)
+	return nil
+}
+
+// @Summary Обновление жанра [Админы]
+// @Description Доступно только админам
+// @Tags genre
+// @Accept json
+// @Produce json
+// @Param reg body model.Genre true "Данные жанра"
+// @Success 200 {object} model.Genre "Данные жанра"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v1/genre [put]
+func (g *genreHandler) Update(res http.ResponseWriter, req *http.Request) error {
+	var body model.Genre
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	genre, err := g.GenreUseCase.Update(req.Context(), &body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, genre)
+	return nil
+}
+
+// @Summary Привязка фильмов к жанру [Админы]
+// @Description Доступно только админам, повторная привязка игнорируется
+// @Tags genre
+// @Accept json
+// @Produce json
+// @Param reg body dto.FilmsToGenreDto true "id жанра и фильмов"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v1/genre/add-film [post]
+func (g *genreHandler) AddFilm(res http.ResponseWriter, req *http.Request) error {
+	var body dto.FilmsToGenreDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("", "Target: Handler")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	return g.GenreUseCase.AddFilm(req.Context(), body.GenreId, body.FilmIds...)
+}
+
+// @Summary Отвязка фильмов от жанра [Админы]
+// @Description Доступно только админам
+// @Tags genre
+// @Accept json
+// @Produce json
+// @Param reg body dto.FilmsToGenreDto true "id жанра и фильмов"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Ошибка 404"
+// @Router /http/v1/genre/remove-film [post]
+func (g *genreHandler) RemoveFilm(res http.ResponseWriter, req *http.Request) error {
+	var body dto.FilmsToGenreDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("", "Target: Handler")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	return g.GenreUseCase.RemoveFilm(req.Context(), body.GenreId, body.FilmIds...)
+}
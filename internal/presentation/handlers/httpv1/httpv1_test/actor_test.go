@@ -164,6 +164,11 @@ func TestActorHttpV1Test(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/http/v1/actor?page=1&page-count=2&connection=film", nil)
 		handler.ServeHTTP(rr, req)
 		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/actor?name=jas&gender=male&age-from=0&age-to=150&order-field=birthday&order-by=desc", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
 	})
 
 	t.Run("Should bad request get by query", func(t *testing.T) {
@@ -181,6 +186,13 @@ func TestActorHttpV1Test(t *testing.T) {
 		req, _ = http.NewRequest("GET", "/http/v1/actor?page=adsads&page-count=2&connection=film", nil)
 		handler.ServeHTTP(rr, req)
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		for _, params := range []string{"page-count=0", "page=-1", "cursor=incorrect", "gender=other", "age-from=40&age-to=30", "birth-year-from=abc", "film=incorrect", "order-field=rate", "order-by=up"} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", "/http/v1/actor?"+params, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, params)
+		}
 	})
 
 	cfg.Env = "dev"
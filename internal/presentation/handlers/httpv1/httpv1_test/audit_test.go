@@ -0,0 +1,91 @@
+package httpv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	auditService "github.com/OddEer0/vk-filmoteka/internal/app/services/audit_service"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHttpV1(t *testing.T) {
+	config.MustLoad()
+	handler := router.NewAppRouter(slog.New(slog.NewTextHandler(io.Discard, nil)), httpv1.NewAppHandlerMock())
+
+	genreId := ""
+	t.Run("Should record mutation with request id", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		body, _ := json.Marshal(appDto.CreateGenreUseCaseDto{Name: "Audited"})
+		req, _ := http.NewRequest("POST", "/http/v1/genre", bytes.NewBuffer(body))
+		req.Header.Set(appErrors.RequestIdHeader, "audit-request")
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "audit-request", rr.Header().Get(appErrors.RequestIdHeader))
+		var genre struct {
+			Id string `json:"id"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &genre); err != nil {
+			t.Fatal(err)
+		}
+		genreId = genre.Id
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/audit?entity-type=genre&entity-id="+genreId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEqual(t, "", rr.Header().Get(appErrors.RequestIdHeader))
+		var result appDto.AuditGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(result.Entries))
+		assert.Equal(t, constants.AuditCreate, result.Entries[0].Action)
+		assert.Equal(t, "audit-request", result.Entries[0].RequestId)
+		var diff map[string]auditService.FieldChange
+		if err := json.Unmarshal(result.Entries[0].Diff, &diff); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, `"Audited"`, string(diff["name"].After))
+		assert.Equal(t, "null", string(diff["name"].Before))
+	})
+
+	t.Run("Should filter by time range", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		from := time.Now().Add(time.Hour).Format(time.RFC3339)
+		req, _ := http.NewRequest("GET", "/http/v1/audit?entity-id="+genreId+"&from="+from, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var result appDto.AuditGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 0, len(result.Entries))
+
+		for _, query := range []string{"entity-type=unknown", "from=yesterday", "from=2024-02-01&to=2024-01-01"} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", "/http/v1/audit?"+query, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	db := inMemDb.New()
+	db.Genre = slices.DeleteFunc(db.Genre, func(item *model.Genre) bool {
+		return item.Id == genreId
+	})
+}
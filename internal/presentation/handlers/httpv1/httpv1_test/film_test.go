@@ -7,13 +7,16 @@ import (
 	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
 	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
 	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
 	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
 	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
 	"github.com/OddEer0/vk-filmoteka/internal/presentation/router"
 	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -46,7 +49,6 @@ func TestFilmHttpV1Test(t *testing.T) {
 		requestBody, err := json.Marshal(appDto.CreateFilmUseCaseDto{
 			Name:        "Titanic",
 			ReleaseDate: time.Now().AddDate(-13, 0, 0),
-			Rate:        10,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -61,11 +63,12 @@ func TestFilmHttpV1Test(t *testing.T) {
 
 	t.Run("Should incorrect film", func(t *testing.T) {
 		handler := initFilmHandler()
+		invalidRate := float32(11)
 		rr := httptest.NewRecorder()
 		requestBody, err := json.Marshal(appDto.CreateFilmUseCaseDto{
 			Name:        "Titanic",
 			ReleaseDate: time.Now().AddDate(-13, 0, 0),
-			Rate:        11,
+			ManualRate:  &invalidRate,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -84,7 +87,6 @@ func TestFilmHttpV1Test(t *testing.T) {
 		requestBody, err := json.Marshal(appDto.CreateFilmUseCaseDto{
 			Name:        "Titanic",
 			ReleaseDate: time.Now().AddDate(-13, 0, 0),
-			Rate:        9,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -116,13 +118,12 @@ func TestFilmHttpV1Test(t *testing.T) {
 		assert.Equal(t, "Marvel", res2.Name)
 	})
 
-	t.Run("Should bad request film", func(t *testing.T) {
+	t.Run("Should bad request film update", func(t *testing.T) {
 		handler := initFilmHandler()
 		rr := httptest.NewRecorder()
 		requestBody, err := json.Marshal(appDto.CreateFilmUseCaseDto{
 			Name:        "Titanic",
 			ReleaseDate: time.Now().AddDate(-13, 0, 0),
-			Rate:        9,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -154,7 +155,6 @@ func TestFilmHttpV1Test(t *testing.T) {
 		requestBody, err := json.Marshal(appDto.CreateFilmUseCaseDto{
 			Name:        "Titanic",
 			ReleaseDate: time.Now().AddDate(-13, 0, 0),
-			Rate:        9,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -187,7 +187,6 @@ func TestFilmHttpV1Test(t *testing.T) {
 		requestBody, err := json.Marshal(appDto.CreateFilmUseCaseDto{
 			Name:        "Titanic",
 			ReleaseDate: time.Now().AddDate(-13, 0, 0),
-			Rate:        9,
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -239,11 +238,56 @@ func TestFilmHttpV1Test(t *testing.T) {
 			t.Fatal(err)
 		}
 		assert.Equal(t, 2, res2.PageCount)
+		assert.Equal(t, "", res2.Next)
+		assert.NotEqual(t, "", res2.Prev)
 
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film?connection=actor&cursor="+res2.Prev, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var prev appDto.FilmGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &prev); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, len(prev.Films))
+		assert.Equal(t, "", prev.Prev)
+
+		rr = httptest.NewRecorder()
+		from := strconv.Itoa(time.Now().AddDate(-14, 0, 0).Year())
+		req, _ = http.NewRequest("GET", "/http/v1/film?release-from="+from+"&rate-from=0&rate-to=10&order-field=release_date", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var res3 appDto.FilmGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &res3); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 4, len(res3.Films))
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film?release-to=1900-01-01&actor="+uuid.New().String(), nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var res4 appDto.FilmGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &res4); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 0, len(res4.Films))
 	})
 
-	t.Run("Should bad request film", func(t *testing.T) {
+	t.Run("Should bad request film query", func(t *testing.T) {
 		handler := initFilmHandler()
+		tampered := []string{
+			domainQuery.Cursor{SortField: "rate", OrderBy: domainQuery.Asc, Value: "abc", Id: uuid.New().String()}.Encode(),
+			domainQuery.Cursor{SortField: "release_date", OrderBy: domainQuery.Asc, Value: "7.5", Id: uuid.New().String()}.Encode(),
+			domainQuery.Cursor{SortField: "name", OrderBy: domainQuery.Asc, Value: "Titanic", Id: "1"}.Encode(),
+		}
+		for _, params := range []string{"page-count=0", "cursor=incorrect", "cursor=" + tampered[0], "cursor=" + tampered[1], "cursor=" + tampered[2], "release-from=2000&release-to=1990", "release-from=20-01", "rate-from=11", "rate-from=8&rate-to=7", "actor=incorrect", "actor-match=some"} {
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/http/v1/film?"+params, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, params)
+		}
+
 		rr := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/http/v1/film?page=2&page-count=2&connection=incorrect&order-by=desc&order-field=rate", nil)
 		handler.ServeHTTP(rr, req)
@@ -283,9 +327,9 @@ func TestFilmHttpV1Test(t *testing.T) {
 		}
 		assert.Equal(t, 1, len(res.Films))
 		assert.Equal(t, 1, res.PageCount)
-		for _, a := range res.Films {
-			t.Log(a.Film.Name)
-		}
+		assert.Equal(t, 1, res.Total)
+		assert.NotNil(t, res.Films[0].Search)
+		assert.True(t, res.Films[0].Search.TitleMatch)
 	})
 
 	t.Run("Should search film bad request", func(t *testing.T) {
@@ -299,5 +343,10 @@ func TestFilmHttpV1Test(t *testing.T) {
 		req, _ = http.NewRequest("GET", "/http/v1/film/search?search=Me", nil)
 		handler.ServeHTTP(rr, req)
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film/search?search=Marvel&page=0", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 }
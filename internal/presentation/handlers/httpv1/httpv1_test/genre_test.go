@@ -0,0 +1,133 @@
+package httpv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/dto"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/router"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func initGenreHandler() http.HandlerFunc {
+	errHandlerToDefaulHandler := func(next appErrors.AppHandlerFunc) http.HandlerFunc {
+		return func(res http.ResponseWriter, req *http.Request) {
+			err := next(res, req)
+			if err != nil {
+				var appErr *appErrors.AppError
+				if errors.As(err, &appErr) {
+					body := appErrors.ResponseError{
+						Code:    appErr.Code,
+						Message: appErr.Message,
+					}
+					httpUtils.SendJson(res, appErr.Code, body)
+				}
+			}
+		}
+	}
+	appHandler := httpv1.NewAppHandlerMock()
+	return http.HandlerFunc(errHandlerToDefaulHandler(router.HttpV1Router(appHandler)))
+}
+
+func TestGenreHttpV1Test(t *testing.T) {
+	config.MustLoad()
+	var genre model.Genre
+	t.Run("Should create genre", func(t *testing.T) {
+		handler := initGenreHandler()
+		rr := httptest.NewRecorder()
+		requestBody, _ := json.Marshal(appDto.CreateGenreUseCaseDto{Name: "Thriller"})
+		req, _ := http.NewRequest("POST", "/http/v1/genre", bytes.NewBuffer(requestBody))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		err := json.Unmarshal(rr.Body.Bytes(), &genre)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v1/genre", bytes.NewBuffer(requestBody))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("Should get all genres", func(t *testing.T) {
+		handler := initGenreHandler()
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v1/genre", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var res []model.Genre
+		_ = json.Unmarshal(rr.Body.Bytes(), &res)
+		assert.Equal(t, 1, len(res))
+	})
+
+	t.Run("Should filter films by genre", func(t *testing.T) {
+		handler := initGenreHandler()
+		rr := httptest.NewRecorder()
+		requestBody, _ := json.Marshal(appDto.CreateFilmUseCaseDto{
+			Name:        "Se7en",
+			ReleaseDate: time.Now().AddDate(-28, 0, 0),
+		})
+		req, _ := http.NewRequest("POST", "/http/v1/film", bytes.NewBuffer(requestBody))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var film model.Film
+		_ = json.Unmarshal(rr.Body.Bytes(), &film)
+
+		rr = httptest.NewRecorder()
+		requestBody, _ = json.Marshal(dto.FilmsToGenreDto{GenreId: genre.Id, FilmIds: []string{film.Id}})
+		req, _ = http.NewRequest("POST", "/http/v1/genre/add-film", bytes.NewBuffer(requestBody))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film?genre="+genre.Id+"&genre-match=all&connection=genre&connection=actor", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var res appDto.FilmGetByQueryResult
+		_ = json.Unmarshal(rr.Body.Bytes(), &res)
+		assert.Equal(t, 1, len(res.Films))
+		assert.Equal(t, "Se7en", res.Films[0].Film.Name)
+		assert.Equal(t, 1, len(res.Films[0].Genres))
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film?genre=incorrect", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film?genre="+genre.Id+"&genre-match=some", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Should update and delete genre", func(t *testing.T) {
+		handler := initGenreHandler()
+		rr := httptest.NewRecorder()
+		genre.Name = "Detective"
+		requestBody, _ := json.Marshal(genre)
+		req, _ := http.NewRequest("PUT", "/http/v1/genre", bytes.NewBuffer(requestBody))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v1/genre?id="+genre.Id, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v1/genre?id="+genre.Id, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,170 @@
+package httpv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/router"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func initRatingHandler() http.HandlerFunc {
+	errHandlerToDefaulHandler := func(next appErrors.AppHandlerFunc) http.HandlerFunc {
+		return func(res http.ResponseWriter, req *http.Request) {
+			err := next(res, req)
+			if err != nil {
+				var appErr *appErrors.AppError
+				if errors.As(err, &appErr) {
+					body := appErrors.ResponseError{
+						Code:    appErr.Code,
+						Message: appErr.Message,
+					}
+					httpUtils.SendJson(res, appErr.Code, body)
+				}
+			}
+		}
+	}
+	appHandler := httpv1.NewAppHandlerMock()
+	return http.HandlerFunc(errHandlerToDefaulHandler(router.HttpV1RouterRating(appHandler)))
+}
+
+func TestRatingHttpV1Test(t *testing.T) {
+	cfg := config.MustLoad()
+	db := inMemDb.New()
+	filmId := uuid.New().String()
+	db.Film = append(db.Film, &model.Film{Id: filmId, Name: "Rated film", ReleaseDate: time.Now()})
+	handler := initRatingHandler()
+
+	t.Run("Should unauthorized without user", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		requestBody, _ := json.Marshal(appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 8})
+		req, _ := http.NewRequest("POST", "/http/v1/rating", bytes.NewBuffer(requestBody))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	cfg.Env = "dev"
+	authHandler := initAppHandler()
+	rrAuth := httptest.NewRecorder()
+	requestBody, _ := json.Marshal(map[string]string{
+		"name":     "Admin",
+		"password": "Adminadmin41",
+	})
+	req, _ := http.NewRequest("POST", "/http/v1/auth/login", bytes.NewBuffer(requestBody))
+	authHandler.ServeHTTP(rrAuth, req)
+	assert.Equal(t, http.StatusOK, rrAuth.Code)
+
+	t.Run("Should rate, change and withdraw", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		requestBody, _ := json.Marshal(appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 8})
+		req, _ := http.NewRequest("POST", "/http/v1/rating", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var result appDto.RatingUseCaseResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, float32(8), result.Rate)
+		assert.Equal(t, 1, result.VoteCount)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v1/rating", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		rr = httptest.NewRecorder()
+		requestBody, _ = json.Marshal(appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 3})
+		req, _ = http.NewRequest("PUT", "/http/v1/rating", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/rating?filmId="+filmId, nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var rating model.Rating
+		if err := json.Unmarshal(rr.Body.Bytes(), &rating); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, rating.Score)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v1/rating?filmId="+filmId, nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, float32(0), result.Rate)
+		assert.Equal(t, 0, result.VoteCount)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v1/rating?filmId="+filmId, nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Should keep user rates after patch without manual rate", func(t *testing.T) {
+		filmHandler := initFilmHandler()
+		patch := func(body string) model.Film {
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", "/http/v1/film?id="+filmId, bytes.NewBufferString(body))
+			setToken(rrAuth, req)
+			filmHandler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+			var film model.Film
+			if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+				t.Fatal(err)
+			}
+			return film
+		}
+
+		film := patch(`{"description": "Only description"}`)
+		assert.Nil(t, film.ManualRate)
+
+		rr := httptest.NewRecorder()
+		requestBody, _ := json.Marshal(appDto.RateFilmUseCaseDto{FilmId: filmId, Score: 6})
+		req, _ := http.NewRequest("POST", "/http/v1/rating", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var result appDto.RatingUseCaseResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, float32(6), result.Rate)
+
+		film = patch(`{"manualRate": 9}`)
+		assert.Equal(t, float32(9), film.Rate)
+		film = patch(`{"name": "Rated film"}`)
+		assert.Equal(t, float32(9), *film.ManualRate)
+		film = patch(`{"manualRate": null}`)
+		assert.Nil(t, film.ManualRate)
+		assert.Equal(t, float32(6), film.Rate)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v1/rating?filmId="+filmId, nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+	cfg.Env = "test"
+}
@@ -0,0 +1,123 @@
+package httpv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReviewHttpV1Test(t *testing.T) {
+	cfg := config.MustLoad()
+	db := inMemDb.New()
+	filmId := uuid.New().String()
+	db.Film = append(db.Film, &model.Film{Id: filmId, Name: "Reviewed film", ReleaseDate: time.Now()})
+	handler := initGenreHandler()
+
+	t.Run("Should not found reviews of unknown film", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v1/film/"+uuid.New().String()+"/reviews", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film/"+filmId+"/reviews?page=0", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	cfg.Env = "dev"
+	authHandler := initAppHandler()
+	rrAuth := httptest.NewRecorder()
+	requestBody, _ := json.Marshal(map[string]string{
+		"name":     "Admin",
+		"password": "Adminadmin41",
+	})
+	req, _ := http.NewRequest("POST", "/http/v1/auth/login", bytes.NewBuffer(requestBody))
+	authHandler.ServeHTTP(rrAuth, req)
+	assert.Equal(t, http.StatusOK, rrAuth.Code)
+
+	t.Run("Should write, moderate and list review", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		requestBody, _ := json.Marshal(appDto.CreateReviewUseCaseDto{FilmId: filmId, Title: "Masterpiece", Body: "Must see"})
+		req, _ := http.NewRequest("POST", "/http/v1/review", bytes.NewBuffer(requestBody))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v1/review", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var review aggregate.ReviewAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &review); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, constants.ReviewPending, review.Review.Status)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/review/queue", nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var queue appDto.ReviewGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &queue); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(queue.Reviews))
+
+		rr = httptest.NewRecorder()
+		requestBody, _ = json.Marshal(appDto.ModerateReviewUseCaseDto{Id: review.Review.Id, Action: "approve"})
+		req, _ = http.NewRequest("POST", "/http/v1/review/moderate", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/film/"+filmId+"/reviews?page=1&page-count=5", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var result appDto.ReviewGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(result.Reviews))
+		assert.Equal(t, "Masterpiece", result.Reviews[0].Review.Title)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v1/review?id="+review.Review.Id, nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Should reject malformed review ids", func(t *testing.T) {
+		for _, item := range []struct {
+			method string
+			url    string
+			body   string
+			code   int
+		}{
+			{"DELETE", "/http/v1/review?id=incorrect", "", http.StatusNotFound},
+			{"GET", "/http/v1/review/queue?film=incorrect", "", http.StatusBadRequest},
+			{"POST", "/http/v1/review/moderate", `{"id": "incorrect", "action": "approve"}`, http.StatusBadRequest},
+		} {
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest(item.method, item.url, bytes.NewBufferString(item.body))
+			setToken(rrAuth, req)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, item.code, rr.Code, item.url)
+		}
+	})
+	cfg.Env = "test"
+}
@@ -0,0 +1,63 @@
+package httpv1_test
+
+import (
+	"encoding/json"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSuggestHttpV1Test(t *testing.T) {
+	config.MustLoad()
+	db := inMemDb.New()
+	db.Film = append(db.Film,
+		&model.Film{Id: uuid.New().String(), Name: "Интерстеллар", ReleaseDate: time.Now(), VoteCount: 10},
+		&model.Film{Id: uuid.New().String(), Name: "Интерстеллар 2", ReleaseDate: time.Now()},
+	)
+	db.Actor = append(db.Actor, &model.Actor{Id: uuid.New().String(), Name: "Интерстеллар Актер", Birthday: time.Now()})
+	handler := initGenreHandler()
+
+	t.Run("Should suggest films and actors", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v1/suggest?q="+url.QueryEscape("интерстелар"), nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var res appDto.SuggestUseCaseResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, len(res.Suggestions))
+		assert.Equal(t, "Интерстеллар", res.Suggestions[0].Name)
+		assert.Equal(t, constants.SuggestFilm, res.Suggestions[0].Type)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/suggest?limit=1&q="+url.QueryEscape("интерстелар"), nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(res.Suggestions))
+	})
+
+	t.Run("Should suggest bad request", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v1/suggest?q=a", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/suggest?q=abc&limit=50", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
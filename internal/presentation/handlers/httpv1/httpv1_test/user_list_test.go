@@ -0,0 +1,111 @@
+package httpv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUserListHttpV1Test(t *testing.T) {
+	cfg := config.MustLoad()
+	db := inMemDb.New()
+	filmId := uuid.New().String()
+	db.Film = append(db.Film, &model.Film{Id: filmId, Name: "Listed film", ReleaseDate: time.Now()})
+	handler := initGenreHandler()
+
+	t.Run("Should unauthorized without user", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v1/list", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	cfg.Env = "dev"
+	authHandler := initAppHandler()
+	rrAuth := httptest.NewRecorder()
+	requestBody, _ := json.Marshal(map[string]string{
+		"name":     "Admin",
+		"password": "Adminadmin41",
+	})
+	req, _ := http.NewRequest("POST", "/http/v1/auth/login", bytes.NewBuffer(requestBody))
+	authHandler.ServeHTTP(rrAuth, req)
+	assert.Equal(t, http.StatusOK, rrAuth.Code)
+
+	t.Run("Should fill favorites and share list", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v1/list", nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var lists []*model.UserList
+		if err := json.Unmarshal(rr.Body.Bytes(), &lists); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, len(lists))
+
+		rr = httptest.NewRecorder()
+		requestBody, _ := json.Marshal(appDto.UserListFilmUseCaseDto{ListId: lists[2].Id, FilmId: filmId})
+		req, _ = http.NewRequest("POST", "/http/v1/list/add-film", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		requestBody, _ = json.Marshal(appDto.UpdateUserListUseCaseDto{Id: lists[2].Id, Name: "Лучшее", Shared: true})
+		req, _ = http.NewRequest("PUT", "/http/v1/list", bytes.NewBuffer(requestBody))
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var shared model.UserList
+		if err := json.Unmarshal(rr.Body.Bytes(), &shared); err != nil {
+			t.Fatal(err)
+		}
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/list/shared/"+*shared.ShareSlug, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var result appDto.UserListFilmsResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(result.Films))
+		assert.Equal(t, filmId, result.Films[0].Film.Id)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v1/list?id="+lists[2].Id, nil)
+		setToken(rrAuth, req)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("Should reject malformed list ids", func(t *testing.T) {
+		for _, item := range []struct {
+			method string
+			url    string
+			body   string
+			code   int
+		}{
+			{"DELETE", "/http/v1/list?id=incorrect", "", http.StatusNotFound},
+			{"GET", "/http/v1/list/films?id=incorrect", "", http.StatusNotFound},
+			{"POST", "/http/v1/list/add-film", `{"listId": "incorrect", "filmId": "incorrect"}`, http.StatusBadRequest},
+			{"PUT", "/http/v1/list", `{"id": "incorrect", "name": "Renamed"}`, http.StatusBadRequest},
+		} {
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest(item.method, item.url, bytes.NewBufferString(item.body))
+			setToken(rrAuth, req)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, item.code, rr.Code, item.url)
+		}
+	})
+	cfg.Env = "test"
+}
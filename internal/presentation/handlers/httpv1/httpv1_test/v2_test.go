@@ -0,0 +1,572 @@
+package httpv1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/aggregate"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+	inMemDb "github.com/OddEer0/vk-filmoteka/internal/infrastructure/storage/in_mem_db"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/dto"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/router"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHttpV2Router(t *testing.T) {
+	config.MustLoad()
+	db := inMemDb.New()
+	description, manualRate := "About space", float32(7)
+	filmId, actorId := uuid.New().String(), uuid.New().String()
+	db.Film = append(db.Film, &model.Film{Id: filmId, Name: "V2 film", Description: &description, ReleaseDate: time.Now().AddDate(-3, 0, 0), Rate: 7, ManualRate: &manualRate})
+	db.Actor = append(db.Actor, &model.Actor{Id: actorId, Name: "V2 actor", Gender: "male", Birthday: time.Now().AddDate(-30, 0, 0)})
+	db.ActorFilm = append(db.ActorFilm, &inMemDb.ActorFilm{ActorId: actorId, FilmId: filmId, Role: "actor"})
+	handler := router.NewAppRouter(slog.New(slog.NewTextHandler(io.Discard, nil)), httpv1.NewAppHandlerMock())
+
+	t.Run("Should get film and actor by id", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v2/films/"+filmId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var film aggregate.FilmAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "V2 film", film.Film.Name)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/actors/"+actorId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		for _, path := range []string{"/http/v2/films/" + uuid.New().String(), "/http/v2/films/incorrect", "/http/v2/actors/" + uuid.New().String()} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", path, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("Should get nested resources", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v2/films/"+filmId+"/actors", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var actors appDto.ActorGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &actors); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(actors.Actors))
+		assert.Equal(t, actorId, actors.Actors[0].Actor.Id)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/actors/"+actorId+"/films?page-count=5", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var films appDto.FilmGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &films); err != nil {
+			t.Fatal(err)
+		}
+		assert.NotEqual(t, 0, len(films.Films))
+		assert.Equal(t, filmId, films.Films[0].Film.Id)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/actors/"+uuid.New().String()+"/films", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/films/"+filmId+"/actors?page=0", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Should put and patch film", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/http/v2/films/"+filmId, bytes.NewBufferString(`{"name": "V2 film patched", "description": null}`))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var film model.Film
+		if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "V2 film patched", film.Name)
+		assert.Nil(t, film.Description)
+		assert.Equal(t, float32(7), film.Rate)
+
+		for _, patch := range []string{`{"name": ""}`, `{"name": null}`, `{"release": null}`} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("PATCH", "/http/v2/films/"+filmId, bytes.NewBufferString(patch))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		}
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("PATCH", "/http/v2/films/"+filmId, bytes.NewBufferString(`{"name": "Plain"}`))
+		req.Header.Set("Content-Type", "text/plain")
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("PATCH", "/http/v1/film?id="+filmId, bytes.NewBufferString(`{"description": "Back in space"}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "V2 film patched", film.Name)
+		assert.Equal(t, "Back in space", *film.Description)
+
+		rr = httptest.NewRecorder()
+		body, _ := json.Marshal(model.Film{Name: "V2 film", ReleaseDate: time.Now().AddDate(-3, 0, 0), Rate: 8})
+		req, _ = http.NewRequest("PUT", "/http/v2/films/"+filmId, bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("PUT", "/http/v2/films/"+uuid.New().String(), bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("PATCH", "/http/v2/actors/"+actorId, bytes.NewBufferString(`{"name": "V2 actor patched"}`))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var actor model.Actor
+		if err := json.Unmarshal(rr.Body.Bytes(), &actor); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "V2 actor patched", actor.Name)
+		assert.Equal(t, "male", actor.Gender)
+	})
+
+	t.Run("Should replace and remove cast", func(t *testing.T) {
+		secondActorId := uuid.New().String()
+		db.Actor = append(db.Actor, &model.Actor{Id: secondActorId, Name: "V2 second actor", Gender: "female", Birthday: time.Now().AddDate(-25, 0, 0)})
+
+		rr := httptest.NewRecorder()
+		body, _ := json.Marshal(dto.SetCastDto{
+			ActorIds: []string{secondActorId, secondActorId},
+			Credits:  []dto.CastCreditDto{{ActorId: actorId, Role: "director", BillingOrder: 2}},
+		})
+		req, _ := http.NewRequest("PUT", "/http/v2/films/"+filmId+"/actors", bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var film aggregate.FilmAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, len(film.Credits))
+		assert.Equal(t, secondActorId, film.Credits[0].ActorId)
+		assert.Equal(t, "director", film.Credits[1].Role)
+
+		rr = httptest.NewRecorder()
+		body, _ = json.Marshal(dto.SetCastDto{ActorIds: []string{uuid.New().String()}})
+		req, _ = http.NewRequest("PUT", "/http/v2/films/"+filmId+"/actors", bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/films/"+filmId+"/actors/"+secondActorId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		body, _ = json.Marshal(dto.SetActorFilmsDto{FilmIds: []string{filmId}})
+		req, _ = http.NewRequest("PUT", "/http/v2/actors/"+actorId+"/films", bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var actor aggregate.ActorAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &actor); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(actor.Credits))
+		assert.Equal(t, "actor", actor.Credits[0].Role)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/actors/"+actorId+"/films/"+uuid.New().String(), nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Should create film with cast", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		body, _ := json.Marshal(appDto.CreateFilmUseCaseDto{
+			Name: "V2 created", ReleaseDate: time.Now().AddDate(-1, 0, 0),
+			ActorIds: []string{actorId},
+			Actors:   []appDto.CreateActorUseCaseDto{{Name: "V2 inline actor", Gender: "female", Birthday: time.Now().AddDate(-22, 0, 0)}},
+		})
+		req, _ := http.NewRequest("POST", "/http/v2/films", bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var film aggregate.FilmAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, len(film.Actors))
+		assert.Equal(t, "V2 inline actor", film.Actors[1].Name)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/films/"+film.Film.Id, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Should find by external id", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		body, _ := json.Marshal(appDto.CreateFilmUseCaseDto{
+			Name: "V2 external", ReleaseDate: time.Now().AddDate(-2, 0, 0),
+			ExternalIds: map[string]string{"imdb": "tt0111161", "tmdb": "278"},
+			Actors: []appDto.CreateActorUseCaseDto{{
+				Name: "V2 external actor", Gender: "male", Birthday: time.Now().AddDate(-40, 0, 0),
+				ExternalIds: map[string]string{"imdb": "nm0000209"},
+			}},
+		})
+		req, _ := http.NewRequest("POST", "/http/v2/films", bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var created aggregate.FilmAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "278", created.ExternalIds["tmdb"])
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/external/imdb/tt0111161/film", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var film aggregate.FilmAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, created.Film.Id, film.Film.Id)
+		assert.Equal(t, map[string]string{"imdb": "tt0111161", "tmdb": "278"}, film.ExternalIds)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/external/imdb/nm0000209/actor", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "V2 external actor")
+
+		for path, code := range map[string]int{
+			"/http/v2/external/imdb/tt9999999/film":            http.StatusNotFound,
+			"/http/v2/external/netflix/1/film":                 http.StatusBadRequest,
+			"/http/v2/external/tmdb/278/actor":                 http.StatusNotFound,
+			"/http/v2/films/" + created.Film.Id + "/revisions": http.StatusOK,
+		} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", path, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, code, rr.Code, path)
+		}
+
+		for ids, code := range map[string]int{`{"imdb": "tt0111161"}`: http.StatusConflict, `{"imdb": "nm0000209"}`: http.StatusUnprocessableEntity} {
+			rr = httptest.NewRecorder()
+			body := `{"name": "V2 external copy", "release": "2001-02-03T00:00:00Z", "externalIds": ` + ids + `}`
+			req, _ = http.NewRequest("POST", "/http/v2/films", bytes.NewBufferString(body))
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, code, rr.Code, ids)
+		}
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/films/"+created.Film.Id, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/external/imdb/tt0111161/film", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = httptest.NewRecorder()
+		body = []byte(`{"name": "V2 external new", "release": "2001-02-03T00:00:00Z", "externalIds": {"imdb": "tt0111161"}}`)
+		req, _ = http.NewRequest("POST", "/http/v2/films", bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/external/imdb/tt0111161/film", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "V2 external new")
+	})
+
+	t.Run("Should reject stale version", func(t *testing.T) {
+		versionedId := uuid.New().String()
+		db.Film = append(db.Film, &model.Film{Id: versionedId, Name: "Versioned", ReleaseDate: time.Now().AddDate(-1, 0, 0), Version: 1})
+
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v2/films/"+versionedId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		etag := rr.Header().Get("ETag")
+		assert.True(t, strings.HasPrefix(etag, `"1-`))
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/films/"+versionedId, nil)
+		req.Header.Set("If-None-Match", etag)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Equal(t, 0, rr.Body.Len())
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("PATCH", "/http/v2/films/"+versionedId, bytes.NewBufferString(`{"name": "First admin"}`))
+		req.Header.Set("If-Match", etag)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		patchedEtag := rr.Header().Get("ETag")
+		assert.True(t, strings.HasPrefix(patchedEtag, `"2-`))
+
+		requestBody, _ := json.Marshal(model.Film{Name: "Second admin", ReleaseDate: time.Now().AddDate(-1, 0, 0)})
+		for _, method := range []string{"PUT", "PATCH"} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest(method, "/http/v2/films/"+versionedId, bytes.NewBuffer(requestBody))
+			req.Header.Set("If-Match", etag)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		}
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/films/"+versionedId, nil)
+		req.Header.Set("If-None-Match", etag)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var film aggregate.FilmAggregate
+		if err := json.Unmarshal(rr.Body.Bytes(), &film); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "First admin", film.Film.Name)
+		assert.Equal(t, 2, film.Film.Version)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/actors/"+actorId, nil)
+		handler.ServeHTTP(rr, req)
+		actorEtag := rr.Header().Get("ETag")
+
+		rr = httptest.NewRecorder()
+		body, _ := json.Marshal(dto.SetCastDto{ActorIds: []string{actorId}})
+		req, _ = http.NewRequest("PUT", "/http/v2/films/"+versionedId+"/actors", bytes.NewBuffer(body))
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		for _, item := range [][2]string{{"/http/v2/films/" + versionedId, patchedEtag}, {"/http/v2/actors/" + actorId, actorEtag}} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", item[0], nil)
+			req.Header.Set("If-None-Match", item[1])
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+		}
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/films/"+versionedId+"/actors/"+actorId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Should list revisions and revert", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v2/films/"+filmId+"/revisions", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var revisions appDto.RevisionGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &revisions); err != nil {
+			t.Fatal(err)
+		}
+		assert.NotEqual(t, 0, len(revisions.Revisions))
+		last := revisions.Revisions[0].Number
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", fmt.Sprintf("/http/v2/films/%s/revisions/%d/revert", filmId, last), nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.NotEqual(t, "", rr.Header().Get("ETag"))
+
+		for path, code := range map[string]int{
+			"/http/v2/films/" + filmId + "/revisions/diff":                 http.StatusBadRequest,
+			"/http/v2/films/" + filmId + "/revisions/diff?from=1&to=999":   http.StatusNotFound,
+			"/http/v2/films/" + filmId + "/revisions/abc":                  http.StatusNotFound,
+			"/http/v2/films/" + uuid.New().String() + "/revisions":         http.StatusNotFound,
+			fmt.Sprintf("/http/v2/films/%s/revisions/%d", filmId, last+1):  http.StatusOK,
+			fmt.Sprintf("/http/v2/films/%s/revisions/%d", filmId, last+10): http.StatusNotFound,
+		} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", path, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, code, rr.Code, path)
+		}
+	})
+
+	t.Run("Should dry run import", func(t *testing.T) {
+		body := "id,name,release\n" + filmId + ",,\n,V2 import,2001-02-03\n,,2001-02-03\n"
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/http/v2/import/films?dry-run=true", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv")
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var report appDto.ImportReport
+		if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, filmId, report.Rows[0].Id)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v2/import/films", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v2/import/genres", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "text/csv")
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Should stream export", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v2/export/films?format=csv", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), "films.csv")
+		assert.Contains(t, rr.Body.String(), "id,name,description,release,rate\n")
+		assert.Contains(t, rr.Body.String(), "\n"+filmId+",V2 film")
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/export/credits?actor="+actorId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var credits []map[string]interface{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &credits); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, len(credits))
+		assert.Equal(t, actorId, credits[0]["actor"])
+		assert.Equal(t, "V2 film", credits[0]["filmName"])
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/export/actors?format=ndjson&gender=female", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+		for _, line := range strings.Split(strings.TrimSpace(rr.Body.String()), "\n") {
+			assert.Contains(t, line, `"gender":"female"`)
+		}
+
+		for _, path := range []string{"/http/v2/export/actors?format=xml", "/http/v2/export/films?rate-from=abc"} {
+			rr = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", path, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, path)
+			assert.Empty(t, rr.Header().Get("Content-Disposition"), path)
+		}
+	})
+
+	t.Run("Should answer 405 with allowed methods", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/http/v2/films/"+filmId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Contains(t, rr.Header().Get("Allow"), "PATCH")
+		assert.Contains(t, rr.Header().Get("Allow"), "DELETE")
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/actors/"+actorId+"/films", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, "GET, HEAD, PUT", rr.Header().Get("Allow"))
+		var resErr appErrors.ResponseError
+		if err := json.Unmarshal(rr.Body.Bytes(), &resErr); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, appErrors.ResponseError{Code: http.StatusMethodNotAllowed, Message: appErrors.DefaultMethodNotAllowed}, resErr)
+	})
+
+	t.Run("Should answer json 404 for unknown route", func(t *testing.T) {
+		for _, path := range []string{"/http/v2/unknown", "/http/v2/films/by-external/imdb/tt0111161", "/http/v2/external/imdb/tt0111161/genre"} {
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", path, nil)
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusNotFound, rr.Code, path)
+			var resErr appErrors.ResponseError
+			if err := json.Unmarshal(rr.Body.Bytes(), &resErr); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, http.StatusNotFound, resErr.Code, path)
+		}
+	})
+
+	t.Run("Should delete by id and keep v1", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/http/v2/actors/"+actorId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/films/"+filmId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/films/"+filmId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v1/genre", nil)
+		handler.ServeHTTP(rr, req)
+		assert.NotEqual(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Should list, restore and purge trash", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/http/v2/trash/films", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var films appDto.FilmGetByQueryResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &films); err != nil {
+			t.Fatal(err)
+		}
+		assert.NotEqual(t, 0, len(films.Films))
+		assert.Equal(t, filmId, films.Films[0].Film.Id)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v2/trash/films/"+filmId+"/restore", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/http/v2/films/"+filmId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v2/trash/films/"+filmId+"/restore", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("DELETE", "/http/v2/trash/actors/"+actorId, nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/http/v2/trash/actors/"+actorId+"/restore", nil)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,91 @@
+package httpv1
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	importUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/import_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+)
+
+// maxImportBody самый большой файл импорта
+const maxImportBody = 32 << 20
+
+// importFormats формат файла импорта по Content-Type тела
+var importFormats = map[string]string{
+	"text/csv":             constants.ImportCsv,
+	"application/x-ndjson": constants.ImportNdjson,
+}
+
+type (
+	ImportHandler interface {
+		Import(res http.ResponseWriter, req *http.Request) error
+	}
+
+	importHandler struct {
+		importUseCase.ImportUseCase
+	}
+)
+
+func NewImportHandler(useCase importUseCase.ImportUseCase) ImportHandler {
+	return &importHandler{
+		ImportUseCase: useCase,
+	}
+}
+
+// @Summary Импорт каталога из файла [Админы]
+// @Description Доступно только админам. Тело - CSV с заголовком (text/csv) или NDJSON (application/x-ndjson).
+// @Description Колонки films: id, name, description, release, rate. actors: id, name, gender, birthday.
+// @Description credits: film, filmName, filmRelease, actor, actorName, actorBirthday, role, character, billingOrder.
+// @Description Запись ищется по id, иначе по названию или имени и дате. Пустые поля не меняют найденную запись.
+// @Description Ошибка строки не прерывает импорт, итог каждой строки в rows
+// @Tags import
+// @Accept plain
+// @Produce json
+// @Param kind path string true "что импортируется (films, actors, credits)"
+// @Param dry-run query bool false "только проверка, без сохранения"
+// @Param batch-size query int false "строк в одной транзакции, по умолчанию 100"
+// @Success 200 {object} appDto.ImportReport "Итог по строкам"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 415 {object} appErrors.ResponseError "Ошибка 415"
+// @Router /http/v2/import/{kind} [post]
+func (i *importHandler) Import(res http.ResponseWriter, req *http.Request) error {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	format, ok := importFormats[mediaType]
+	if !ok {
+		return appErrors.UnsupportedMediaType("", "expected content type: text/csv or application/x-ndjson")
+	}
+
+	data := appDto.ImportUseCaseDto{
+		Kind:   req.PathValue("kind"),
+		Format: format,
+		Data:   http.MaxBytesReader(res, req.Body, maxImportBody),
+	}
+	query := req.URL.Query()
+	if query.Has("dry-run") {
+		dryRun, err := strconv.ParseBool(query.Get("dry-run"))
+		if err != nil {
+			return appErrors.BadRequest("", "incorrect dry-run")
+		}
+		data.DryRun = dryRun
+	}
+	if query.Has("batch-size") {
+		batchSize, err := strconv.Atoi(query.Get("batch-size"))
+		if err != nil || batchSize <= 0 {
+			return appErrors.BadRequest("", "incorrect batch-size")
+		}
+		data.BatchSize = batchSize
+	}
+
+	result, err := i.ImportUseCase.Import(req.Context(), data)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
@@ -0,0 +1,202 @@
+package httpv1
+
+import (
+	"errors"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parsePage читает page и page-count, оставляя значения по умолчанию если параметров нет
+func parsePage(query url.Values, currentPage *int, pageCount *int) error {
+	var err error
+	if query.Has("page") {
+		*currentPage, err = strconv.Atoi(query.Get("page"))
+		if err != nil || *currentPage < 1 {
+			return appErrors.BadRequest("invalid page")
+		}
+	}
+	if query.Has("page-count") {
+		*pageCount, err = strconv.Atoi(query.Get("page-count"))
+		if err != nil || *pageCount < 1 {
+			return appErrors.BadRequest("invalid page count")
+		}
+	}
+	return nil
+}
+
+// parseIntRange читает необязательный диапазон неотрицательных чисел, from не может быть больше to
+func parseIntRange(query url.Values, fromKey string, toKey string, from **int, to **int) error {
+	for _, item := range []struct {
+		key    string
+		target **int
+	}{{fromKey, from}, {toKey, to}} {
+		if !query.Has(item.key) {
+			continue
+		}
+		value, err := strconv.Atoi(query.Get(item.key))
+		if err != nil || value < 0 {
+			return appErrors.BadRequest("invalid " + item.key)
+		}
+		*item.target = &value
+	}
+	if *from != nil && *to != nil && **from > **to {
+		return appErrors.BadRequest("invalid range " + fromKey + " - " + toKey)
+	}
+	return nil
+}
+
+// parseIds уникальные uuid из повторяющегося параметра, значения можно перечислять через запятую
+func parseIds(query url.Values, key string, target *[]string) error {
+	for _, value := range query[key] {
+		for _, id := range strings.Split(value, ",") {
+			if _, err := uuid.Parse(id); err != nil {
+				return appErrors.BadRequest("invalid " + key)
+			}
+			if !slices.Contains(*target, id) {
+				*target = append(*target, id)
+			}
+		}
+	}
+	return nil
+}
+
+func parseMatchMode(query url.Values, key string, target *domainQuery.MatchMode) error {
+	if !query.Has(key) {
+		return nil
+	}
+	switch domainQuery.MatchMode(query.Get(key)) {
+	case domainQuery.MatchAny:
+		*target = domainQuery.MatchAny
+	case domainQuery.MatchAll:
+		*target = domainQuery.MatchAll
+	default:
+		return appErrors.BadRequest("invalid " + key)
+	}
+	return nil
+}
+
+// parseDateRange принимает год (2000) либо дату (2000-12-31). Год в from означает 1 января, в to - 31 декабря
+func parseDateRange(query url.Values, fromKey string, toKey string, from **time.Time, to **time.Time) error {
+	parse := func(key string, yearEnd bool) (*time.Time, error) {
+		value := query.Get(key)
+		if year, err := strconv.Atoi(value); err == nil && year > 0 && year < 10000 {
+			date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+			if yearEnd {
+				date = time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+			}
+			return &date, nil
+		}
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, appErrors.BadRequest("invalid " + key)
+		}
+		return &date, nil
+	}
+
+	var err error
+	if query.Has(fromKey) {
+		if *from, err = parse(fromKey, false); err != nil {
+			return err
+		}
+	}
+	if query.Has(toKey) {
+		if *to, err = parse(toKey, true); err != nil {
+			return err
+		}
+	}
+	if *from != nil && *to != nil && (*from).After(**to) {
+		return appErrors.BadRequest("invalid range " + fromKey + " - " + toKey)
+	}
+	return nil
+}
+
+// parseTimeRange принимает момент в RFC3339 либо дату (2000-12-31). Дата в to означает конец дня
+func parseTimeRange(query url.Values, fromKey string, toKey string, from **time.Time, to **time.Time) error {
+	parse := func(key string, dayEnd bool) (*time.Time, error) {
+		value := query.Get(key)
+		if moment, err := time.Parse(time.RFC3339, value); err == nil {
+			return &moment, nil
+		}
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, appErrors.BadRequest("invalid " + key)
+		}
+		if dayEnd {
+			date = date.Add(24*time.Hour - time.Nanosecond)
+		}
+		return &date, nil
+	}
+
+	var err error
+	if query.Has(fromKey) {
+		if *from, err = parse(fromKey, false); err != nil {
+			return err
+		}
+	}
+	if query.Has(toKey) {
+		if *to, err = parse(toKey, true); err != nil {
+			return err
+		}
+	}
+	if *from != nil && *to != nil && (*from).After(**to) {
+		return appErrors.BadRequest("invalid range " + fromKey + " - " + toKey)
+	}
+	return nil
+}
+
+// parseRateRange диапазон рейтинга в пределах 0-10
+func parseRateRange(query url.Values, fromKey string, toKey string, from **float32, to **float32) error {
+	for _, item := range []struct {
+		key    string
+		target **float32
+	}{{fromKey, from}, {toKey, to}} {
+		if !query.Has(item.key) {
+			continue
+		}
+		value, err := strconv.ParseFloat(query.Get(item.key), 32)
+		if err != nil || value < 0 || value > 10 {
+			return appErrors.BadRequest("invalid " + item.key)
+		}
+		rate := float32(value)
+		*item.target = &rate
+	}
+	if *from != nil && *to != nil && **from > **to {
+		return appErrors.BadRequest("invalid range " + fromKey + " - " + toKey)
+	}
+	return nil
+}
+
+// parseCursor читает токен keyset пагинации, сортировка в нем должна быть одной из sortFields
+func parseCursor(query url.Values, sortFields []string, target **domainQuery.Cursor) error {
+	if !query.Has("cursor") {
+		return nil
+	}
+	cursor, err := domainQuery.DecodeCursor(query.Get("cursor"))
+	if err != nil || !slices.Contains(sortFields, cursor.SortField) || !cursor.Valid() {
+		return appErrors.BadRequest("invalid cursor")
+	}
+	*target = cursor
+	return nil
+}
+
+// pathId id ресурса из параметра name пути /http/v2, не uuid не может существовать поэтому это 404
+func pathId(req *http.Request, name string) (string, error) {
+	id := req.PathValue(name)
+	if _, err := uuid.Parse(id); err != nil {
+		return "", appErrors.NotFound("")
+	}
+	return id, nil
+}
+
+// isPreconditionFailed ответ use case на устаревшую версию записи
+func isPreconditionFailed(err error) bool {
+	var appErr *appErrors.AppError
+	return errors.As(err, &appErr) && appErr.Code == http.StatusPreconditionFailed
+}
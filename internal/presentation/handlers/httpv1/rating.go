@@ -0,0 +1,154 @@
+package httpv1
+
+import (
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	ratingUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/rating_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/middleware"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"net/http"
+)
+
+type (
+	RatingHandler interface {
+		Rate(res http.ResponseWriter, req *http.Request) error
+		Change(res http.ResponseWriter, req *http.Request) error
+		Withdraw(res http.ResponseWriter, req *http.Request) error
+		Get(res http.ResponseWriter, req *http.Request) error
+	}
+
+	ratingHandler struct {
+		ratingUseCase.RatingUseCase
+	}
+)
+
+func NewRatingHandler(useCase ratingUseCase.RatingUseCase) RatingHandler {
+	return &ratingHandler{
+		RatingUseCase: useCase,
+	}
+}
+
+// @Summary Оценка фильма [Пользователи]
+// @Description Доступно авторизованным пользователям, rate и voteCount фильма пересчитываются сразу
+// @Tags rating
+// @Accept json
+// @Produce json
+// @Param reg body appDto.RateFilmUseCaseDto true "id фильма и оценка от 1 до 10"
+// @Success 200 {object} appDto.RatingUseCaseResult "Оценка и новый рейтинг фильма"
+// @Failure 404 {object} appErrors.ResponseError "Фильм не найден"
+// @Failure 409 {object} appErrors.ResponseError "Фильм уже оценен"
+// @Router /http/v1/rating [post]
+func (r *ratingHandler) Rate(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.RateFilmUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	result, err := r.RatingUseCase.Rate(req.Context(), user.Id, body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Изменение оценки фильма [Пользователи]
+// @Description Доступно авторизованным пользователям, меняет ранее поставленную оценку
+// @Tags rating
+// @Accept json
+// @Produce json
+// @Param reg body appDto.RateFilmUseCaseDto true "id фильма и новая оценка от 1 до 10"
+// @Success 200 {object} appDto.RatingUseCaseResult "Оценка и новый рейтинг фильма"
+// @Failure 404 {object} appErrors.ResponseError "Оценка не найдена"
+// @Router /http/v1/rating [put]
+func (r *ratingHandler) Change(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.RateFilmUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	result, err := r.RatingUseCase.Change(req.Context(), user.Id, body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Отзыв оценки фильма [Пользователи]
+// @Description Доступно авторизованным пользователям, удаляет свою оценку
+// @Tags rating
+// @Accept json
+// @Produce json
+// @Param filmId query string true "id фильма"
+// @Success 200 {object} appDto.RatingUseCaseResult "Новый рейтинг фильма"
+// @Failure 404 {object} appErrors.ResponseError "Оценка не найдена"
+// @Router /http/v1/rating [delete]
+func (r *ratingHandler) Withdraw(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	filmId := req.URL.Query().Get("filmId")
+	if filmId == "" {
+		return appErrors.BadRequest("")
+	}
+
+	result, err := r.RatingUseCase.Withdraw(req.Context(), user.Id, filmId)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Своя оценка фильма [Пользователи]
+// @Tags rating
+// @Accept json
+// @Produce json
+// @Param filmId query string true "id фильма"
+// @Success 200 {object} model.Rating "Оценка пользователя"
+// @Failure 404 {object} appErrors.ResponseError "Оценка не найдена"
+// @Router /http/v1/rating [get]
+func (r *ratingHandler) Get(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	filmId := req.URL.Query().Get("filmId")
+	if filmId == "" {
+		return appErrors.BadRequest("")
+	}
+
+	rating, err := r.RatingUseCase.Get(req.Context(), user.Id, filmId)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, rating)
+	return nil
+}
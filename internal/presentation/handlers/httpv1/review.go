@@ -0,0 +1,237 @@
+package httpv1
+
+import (
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	reviewUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/review_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/middleware"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/google/uuid"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type (
+	ReviewHandler interface {
+		Create(res http.ResponseWriter, req *http.Request) error
+		Update(res http.ResponseWriter, req *http.Request) error
+		Delete(res http.ResponseWriter, req *http.Request) error
+		GetByFilm(res http.ResponseWriter, req *http.Request) error
+		GetQueue(res http.ResponseWriter, req *http.Request) error
+		Moderate(res http.ResponseWriter, req *http.Request) error
+	}
+
+	reviewHandler struct {
+		reviewUseCase.ReviewUseCase
+	}
+)
+
+func NewReviewHandler(useCase reviewUseCase.ReviewUseCase) ReviewHandler {
+	return &reviewHandler{
+		ReviewUseCase: useCase,
+	}
+}
+
+// @Summary Создание рецензии [Пользователи]
+// @Description Доступно авторизованным пользователям, рецензия попадает в очередь модерации. Одна рецензия на фильм от пользователя
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param reg body appDto.CreateReviewUseCaseDto true "Данные рецензии"
+// @Success 200 {object} aggregate.ReviewAggregate "Созданная рецензия"
+// @Failure 404 {object} appErrors.ResponseError "Фильм не найден"
+// @Failure 409 {object} appErrors.ResponseError "Рецензия уже написана"
+// @Router /http/v1/review [post]
+func (r *reviewHandler) Create(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.CreateReviewUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	review, err := r.ReviewUseCase.Create(req.Context(), user.Id, body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, review)
+	return nil
+}
+
+// @Summary Изменение своей рецензии [Пользователи]
+// @Description Доступно автору рецензии, после изменения рецензия снова проходит модерацию
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param reg body appDto.UpdateReviewUseCaseDto true "Новые данные рецензии"
+// @Success 200 {object} aggregate.ReviewAggregate "Измененная рецензия"
+// @Failure 403 {object} appErrors.ResponseError "Чужая рецензия"
+// @Failure 404 {object} appErrors.ResponseError "Рецензия не найдена"
+// @Router /http/v1/review [put]
+func (r *reviewHandler) Update(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.UpdateReviewUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	review, err := r.ReviewUseCase.Update(req.Context(), user.Id, body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, review)
+	return nil
+}
+
+// @Summary Удаление своей рецензии [Пользователи]
+// @Description Доступно автору рецензии, ничего ответом не возвращает
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id query string true "id рецензии"
+// @Failure 403 {object} appErrors.ResponseError "Чужая рецензия"
+// @Failure 404 {object} appErrors.ResponseError "Рецензия не найдена"
+// @Router /http/v1/review [delete]
+func (r *reviewHandler) Delete(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		return appErrors.BadRequest("")
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return appErrors.NotFound("")
+	}
+
+	return r.ReviewUseCase.Delete(req.Context(), user.Id, id)
+}
+
+// @Summary Рецензии фильма
+// @Description Только одобренные модератором рецензии, от новых к старым
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во рецензий на странице"
+// @Success 200 {object} appDto.ReviewGetByQueryResult "Рецензии"
+// @Failure 404 {object} appErrors.ResponseError "Фильм не найден"
+// @Router /http/v1/film/{id}/reviews [get]
+func (r *reviewHandler) GetByFilm(res http.ResponseWriter, req *http.Request) error {
+	path := strings.TrimPrefix(req.URL.Path, "/http/v1/film/")
+	filmId, ok := strings.CutSuffix(path, "/reviews")
+	if !ok || filmId == "" || strings.Contains(filmId, "/") {
+		return appErrors.NotFound("")
+	}
+
+	rQuery := domainQuery.NewReviewRepositoryQuery()
+	if err := parsePage(req.URL.Query(), &rQuery.CurrentPage, &rQuery.PageCount); err != nil {
+		return err
+	}
+	rQuery.FilmId = filmId
+
+	result, err := r.ReviewUseCase.GetByFilm(req.Context(), *rQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Очередь модерации рецензий [Админы]
+// @Description Доступно только админам, по умолчанию рецензии в статусе pending, от новых к старым
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param status query []string false "статусы (pending, approved, rejected, hidden)" collectionFormat(multi)
+// @Param film query string false "id фильма"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во рецензий на странице"
+// @Success 200 {object} appDto.ReviewGetByQueryResult "Рецензии"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v1/review/queue [get]
+func (r *reviewHandler) GetQueue(res http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	rQuery := domainQuery.NewReviewRepositoryQuery()
+	if err := parsePage(query, &rQuery.CurrentPage, &rQuery.PageCount); err != nil {
+		return err
+	}
+	for _, value := range query["status"] {
+		for _, status := range strings.Split(value, ",") {
+			if !slices.Contains(constants.ReviewStatuses, status) {
+				return appErrors.BadRequest("invalid status")
+			}
+			if !slices.Contains(rQuery.Statuses, status) {
+				rQuery.Statuses = append(rQuery.Statuses, status)
+			}
+		}
+	}
+	if query.Has("film") {
+		rQuery.FilmId = query.Get("film")
+		if _, err := uuid.Parse(rQuery.FilmId); err != nil {
+			return appErrors.BadRequest("invalid film")
+		}
+	}
+
+	result, err := r.ReviewUseCase.GetQueue(req.Context(), *rQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Модерация рецензии [Админы]
+// @Description Доступно только админам. approve - опубликовать, reject - отклонить, hide - скрыть опубликованную
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param reg body appDto.ModerateReviewUseCaseDto true "id рецензии, действие и причина"
+// @Success 200 {object} aggregate.ReviewAggregate "Рецензия с новым статусом"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Рецензия не найдена"
+// @Failure 422 {object} appErrors.ResponseError "Неизвестное действие"
+// @Router /http/v1/review/moderate [post]
+func (r *reviewHandler) Moderate(res http.ResponseWriter, req *http.Request) error {
+	var body appDto.ModerateReviewUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	review, err := r.ReviewUseCase.Moderate(req.Context(), body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, review)
+	return nil
+}
@@ -0,0 +1,244 @@
+package httpv1
+
+import (
+	"net/http"
+	"strconv"
+
+	revisionUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/revision_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+)
+
+type (
+	RevisionHandler interface {
+		GetFilmRevisions(res http.ResponseWriter, req *http.Request) error
+		GetFilmRevision(res http.ResponseWriter, req *http.Request) error
+		DiffFilmRevisions(res http.ResponseWriter, req *http.Request) error
+		RevertFilm(res http.ResponseWriter, req *http.Request) error
+		GetActorRevisions(res http.ResponseWriter, req *http.Request) error
+		GetActorRevision(res http.ResponseWriter, req *http.Request) error
+		DiffActorRevisions(res http.ResponseWriter, req *http.Request) error
+		RevertActor(res http.ResponseWriter, req *http.Request) error
+	}
+
+	revisionHandler struct {
+		revisionUseCase.RevisionUseCase
+	}
+)
+
+func NewRevisionHandler(useCase revisionUseCase.RevisionUseCase) RevisionHandler {
+	return &revisionHandler{
+		RevisionUseCase: useCase,
+	}
+}
+
+// @Summary Ревизии фильма [Админы]
+// @Description Доступно только админам, от новых к старым. snapshot - фильм с титрами после правки
+// @Tags revision
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во ревизий на странице"
+// @Success 200 {object} appDto.RevisionGetByQueryResult "Ревизии"
+// @Failure 404 {object} appErrors.ResponseError "Фильм не найден"
+// @Router /http/v2/films/{id}/revisions [get]
+func (r *revisionHandler) GetFilmRevisions(res http.ResponseWriter, req *http.Request) error {
+	return r.getRevisions(res, req, constants.AuditFilm)
+}
+
+// @Summary Ревизия фильма [Админы]
+// @Description Доступно только админам
+// @Tags revision
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param number path int true "номер ревизии"
+// @Success 200 {object} model.Revision "Ревизия"
+// @Failure 404 {object} appErrors.ResponseError "Ревизия не найдена"
+// @Router /http/v2/films/{id}/revisions/{number} [get]
+func (r *revisionHandler) GetFilmRevision(res http.ResponseWriter, req *http.Request) error {
+	return r.getRevision(res, req, constants.AuditFilm)
+}
+
+// @Summary Разница между ревизиями фильма [Админы]
+// @Description Доступно только админам, поля которые поменялись от ревизии from к ревизии to
+// @Tags revision
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param from query int true "номер ревизии"
+// @Param to query int true "номер ревизии"
+// @Success 200 {object} appDto.RevisionDiffResult "Изменившиеся поля"
+// @Failure 400 {object} appErrors.ResponseError "Нет номеров ревизий"
+// @Failure 404 {object} appErrors.ResponseError "Ревизия не найдена"
+// @Router /http/v2/films/{id}/revisions/diff [get]
+func (r *revisionHandler) DiffFilmRevisions(res http.ResponseWriter, req *http.Request) error {
+	return r.diff(res, req, constants.AuditFilm)
+}
+
+// @Summary Откат фильма к ревизии [Админы]
+// @Description Доступно только админам. Поля и титры возвращаются к ревизии, откат проходит валидацию и создает новую ревизию
+// @Tags revision
+// @Produce json
+// @Param id path string true "id фильма"
+// @Param number path int true "номер ревизии"
+// @Success 200 {object} aggregate.FilmAggregate "Фильм после отката"
+// @Failure 404 {object} appErrors.ResponseError "Фильм или ревизия не найдены"
+// @Failure 409 {object} appErrors.ResponseError "Актеры ревизии удалены"
+// @Failure 422 {object} appErrors.ResponseError "Ревизия не проходит валидацию"
+// @Router /http/v2/films/{id}/revisions/{number}/revert [post]
+func (r *revisionHandler) RevertFilm(res http.ResponseWriter, req *http.Request) error {
+	id, number, err := revisionPath(req)
+	if err != nil {
+		return err
+	}
+
+	film, err := r.RevisionUseCase.RevertFilm(req.Context(), id, number)
+	if err != nil {
+		return err
+	}
+
+	res.Header().Set("ETag", filmETag(&film.Film))
+	httpUtils.SendJson(res, http.StatusOK, film)
+	return nil
+}
+
+// @Summary Ревизии актера [Админы]
+// @Description Доступно только админам, от новых к старым. snapshot - актер с титрами после правки
+// @Tags revision
+// @Produce json
+// @Param id path string true "id актера"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во ревизий на странице"
+// @Success 200 {object} appDto.RevisionGetByQueryResult "Ревизии"
+// @Failure 404 {object} appErrors.ResponseError "Актер не найден"
+// @Router /http/v2/actors/{id}/revisions [get]
+func (r *revisionHandler) GetActorRevisions(res http.ResponseWriter, req *http.Request) error {
+	return r.getRevisions(res, req, constants.AuditActor)
+}
+
+// @Summary Ревизия актера [Админы]
+// @Description Доступно только админам
+// @Tags revision
+// @Produce json
+// @Param id path string true "id актера"
+// @Param number path int true "номер ревизии"
+// @Success 200 {object} model.Revision "Ревизия"
+// @Failure 404 {object} appErrors.ResponseError "Ревизия не найдена"
+// @Router /http/v2/actors/{id}/revisions/{number} [get]
+func (r *revisionHandler) GetActorRevision(res http.ResponseWriter, req *http.Request) error {
+	return r.getRevision(res, req, constants.AuditActor)
+}
+
+// @Summary Разница между ревизиями актера [Админы]
+// @Description Доступно только админам, поля которые поменялись от ревизии from к ревизии to
+// @Tags revision
+// @Produce json
+// @Param id path string true "id актера"
+// @Param from query int true "номер ревизии"
+// @Param to query int true "номер ревизии"
+// @Success 200 {object} appDto.RevisionDiffResult "Изменившиеся поля"
+// @Failure 400 {object} appErrors.ResponseError "Нет номеров ревизий"
+// @Failure 404 {object} appErrors.ResponseError "Ревизия не найдена"
+// @Router /http/v2/actors/{id}/revisions/diff [get]
+func (r *revisionHandler) DiffActorRevisions(res http.ResponseWriter, req *http.Request) error {
+	return r.diff(res, req, constants.AuditActor)
+}
+
+// @Summary Откат актера к ревизии [Админы]
+// @Description Доступно только админам. Поля и участия в фильмах возвращаются к ревизии, откат проходит валидацию и создает новую ревизию
+// @Tags revision
+// @Produce json
+// @Param id path string true "id актера"
+// @Param number path int true "номер ревизии"
+// @Success 200 {object} aggregate.ActorAggregate "Актер после отката"
+// @Failure 404 {object} appErrors.ResponseError "Актер или ревизия не найдены"
+// @Failure 409 {object} appErrors.ResponseError "Фильмы ревизии удалены"
+// @Failure 422 {object} appErrors.ResponseError "Ревизия не проходит валидацию"
+// @Router /http/v2/actors/{id}/revisions/{number}/revert [post]
+func (r *revisionHandler) RevertActor(res http.ResponseWriter, req *http.Request) error {
+	id, number, err := revisionPath(req)
+	if err != nil {
+		return err
+	}
+
+	actor, err := r.RevisionUseCase.RevertActor(req.Context(), id, number)
+	if err != nil {
+		return err
+	}
+
+	res.Header().Set("ETag", httpUtils.ETag(actor.Actor.Version))
+	httpUtils.SendJson(res, http.StatusOK, actor)
+	return nil
+}
+
+func (r *revisionHandler) getRevisions(res http.ResponseWriter, req *http.Request, entityType string) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+	pQuery := domainQuery.NewPageQuery()
+	if err := parsePage(req.URL.Query(), &pQuery.CurrentPage, &pQuery.PageCount); err != nil {
+		return err
+	}
+
+	result, err := r.RevisionUseCase.GetByEntity(req.Context(), entityType, id, *pQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+func (r *revisionHandler) getRevision(res http.ResponseWriter, req *http.Request, entityType string) error {
+	id, number, err := revisionPath(req)
+	if err != nil {
+		return err
+	}
+
+	revision, err := r.RevisionUseCase.GetByNumber(req.Context(), entityType, id, number)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, revision)
+	return nil
+}
+
+func (r *revisionHandler) diff(res http.ResponseWriter, req *http.Request, entityType string) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+	query := req.URL.Query()
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil || from < 1 {
+		return appErrors.BadRequest("invalid from")
+	}
+	to, err := strconv.Atoi(query.Get("to"))
+	if err != nil || to < 1 {
+		return appErrors.BadRequest("invalid to")
+	}
+
+	result, err := r.RevisionUseCase.Diff(req.Context(), entityType, id, from, to)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// revisionPath id сущности и номер ревизии из пути, невалидный номер не может существовать поэтому это 404
+func revisionPath(req *http.Request) (string, int, error) {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return "", 0, err
+	}
+	number, err := strconv.Atoi(req.PathValue("number"))
+	if err != nil || number < 1 {
+		return "", 0, appErrors.NotFound("")
+	}
+	return id, number, nil
+}
@@ -0,0 +1,63 @@
+package httpv1
+
+import (
+	suggestUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/suggest_usecase"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	SuggestHandler interface {
+		Suggest(res http.ResponseWriter, req *http.Request) error
+	}
+
+	suggestHandler struct {
+		suggestUseCase.SuggestUseCase
+	}
+)
+
+func NewSuggestHandler(useCase suggestUseCase.SuggestUseCase) SuggestHandler {
+	return &suggestHandler{
+		SuggestUseCase: useCase,
+	}
+}
+
+// @Summary Подсказки при вводе
+// @Description фильмы и актеры вперемешку, устойчиво к опечаткам. Сортировка по схожести и популярности
+// @Tags suggest
+// @Accept json
+// @Produce json
+// @Param q query string true "введенное значение, минимум 2 символа"
+// @Param limit query int false "кол-во подсказок, от 1 до 20 (по умолчанию 10)"
+// @Success 200 {object} appDto.SuggestUseCaseResult "подсказки"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v1/suggest [get]
+func (s *suggestHandler) Suggest(res http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	value := strings.TrimSpace(query.Get("q"))
+	if utf8.RuneCountInString(value) < 2 {
+		return appErrors.BadRequest("suggest value min 2 chars")
+	}
+
+	suggestQuery := domainQuery.NewSuggestQuery(value)
+	if query.Has("limit") {
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || limit < 1 || limit > 20 {
+			return appErrors.BadRequest("invalid limit")
+		}
+		suggestQuery.Limit = limit
+	}
+
+	result, err := s.SuggestUseCase.Suggest(req.Context(), *suggestQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
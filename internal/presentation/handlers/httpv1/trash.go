@@ -0,0 +1,137 @@
+package httpv1
+
+import (
+	trashUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/trash_usecase"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"net/http"
+)
+
+type (
+	TrashHandler interface {
+		GetFilms(res http.ResponseWriter, req *http.Request) error
+		GetActors(res http.ResponseWriter, req *http.Request) error
+		RestoreFilm(res http.ResponseWriter, req *http.Request) error
+		RestoreActor(res http.ResponseWriter, req *http.Request) error
+		PurgeFilm(res http.ResponseWriter, req *http.Request) error
+		PurgeActor(res http.ResponseWriter, req *http.Request) error
+	}
+
+	trashHandler struct {
+		trashUseCase.TrashUseCase
+	}
+)
+
+func NewTrashHandler(useCase trashUseCase.TrashUseCase) TrashHandler {
+	return &trashHandler{
+		TrashUseCase: useCase,
+	}
+}
+
+// @Summary Фильмы в корзине [Админы]
+// @Description Доступно только админам, сначала удаленные последними
+// @Tags trash
+// @Produce json
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во фильмов на странице"
+// @Success 200 {object} appDto.FilmGetByQueryResult "удаленные фильмы"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v2/trash/films [get]
+func (t *trashHandler) GetFilms(res http.ResponseWriter, req *http.Request) error {
+	pQuery := domainQuery.NewPageQuery()
+	if err := parsePage(req.URL.Query(), &pQuery.CurrentPage, &pQuery.PageCount); err != nil {
+		return err
+	}
+
+	result, err := t.TrashUseCase.GetFilms(req.Context(), *pQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Актеры в корзине [Админы]
+// @Description Доступно только админам, сначала удаленные последними
+// @Tags trash
+// @Produce json
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во актеров на странице"
+// @Success 200 {object} appDto.ActorGetByQueryResult "удаленные актеры"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Router /http/v2/trash/actors [get]
+func (t *trashHandler) GetActors(res http.ResponseWriter, req *http.Request) error {
+	pQuery := domainQuery.NewPageQuery()
+	if err := parsePage(req.URL.Query(), &pQuery.CurrentPage, &pQuery.PageCount); err != nil {
+		return err
+	}
+
+	result, err := t.TrashUseCase.GetActors(req.Context(), *pQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Восстановление фильма из корзины [Админы]
+// @Description Доступно только админам, фильм возвращается со всеми связями
+// @Tags trash
+// @Param id path string true "id фильма"
+// @Failure 404 {object} appErrors.ResponseError "Фильма нет в корзине"
+// @Router /http/v2/trash/films/{id}/restore [post]
+func (t *trashHandler) RestoreFilm(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return t.TrashUseCase.RestoreFilm(req.Context(), id)
+}
+
+// @Summary Восстановление актера из корзины [Админы]
+// @Description Доступно только админам, актер возвращается со всеми связями
+// @Tags trash
+// @Param id path string true "id актера"
+// @Failure 404 {object} appErrors.ResponseError "Актера нет в корзине"
+// @Router /http/v2/trash/actors/{id}/restore [post]
+func (t *trashHandler) RestoreActor(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return t.TrashUseCase.RestoreActor(req.Context(), id)
+}
+
+// @Summary Удаление фильма навсегда [Админы]
+// @Description Доступно только админам, удаляется только фильм из корзины вместе с оценками, рецензиями и связями
+// @Tags trash
+// @Param id path string true "id фильма"
+// @Failure 404 {object} appErrors.ResponseError "Фильма нет в корзине"
+// @Router /http/v2/trash/films/{id} [delete]
+func (t *trashHandler) PurgeFilm(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return t.TrashUseCase.PurgeFilm(req.Context(), id)
+}
+
+// @Summary Удаление актера навсегда [Админы]
+// @Description Доступно только админам, удаляется только актер из корзины вместе со связями
+// @Tags trash
+// @Param id path string true "id актера"
+// @Failure 404 {object} appErrors.ResponseError "Актера нет в корзине"
+// @Router /http/v2/trash/actors/{id} [delete]
+func (t *trashHandler) PurgeActor(res http.ResponseWriter, req *http.Request) error {
+	id, err := pathId(req, "id")
+	if err != nil {
+		return err
+	}
+
+	return t.TrashUseCase.PurgeActor(req.Context(), id)
+}
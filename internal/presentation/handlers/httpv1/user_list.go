@@ -0,0 +1,305 @@
+package httpv1
+
+import (
+	appDto "github.com/OddEer0/vk-filmoteka/internal/app/app_dto"
+	userListUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/user_list_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	domainQuery "github.com/OddEer0/vk-filmoteka/internal/domain/repository/domain_query"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/middleware"
+	httpUtils "github.com/OddEer0/vk-filmoteka/pkg/http_utils"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+type (
+	UserListHandler interface {
+		GetMy(res http.ResponseWriter, req *http.Request) error
+		Create(res http.ResponseWriter, req *http.Request) error
+		Update(res http.ResponseWriter, req *http.Request) error
+		Delete(res http.ResponseWriter, req *http.Request) error
+		GetFilms(res http.ResponseWriter, req *http.Request) error
+		AddFilm(res http.ResponseWriter, req *http.Request) error
+		RemoveFilm(res http.ResponseWriter, req *http.Request) error
+		MoveFilm(res http.ResponseWriter, req *http.Request) error
+		GetShared(res http.ResponseWriter, req *http.Request) error
+	}
+
+	userListHandler struct {
+		userListUseCase.UserListUseCase
+	}
+)
+
+func NewUserListHandler(useCase userListUseCase.UserListUseCase) UserListHandler {
+	return &userListHandler{
+		UserListUseCase: useCase,
+	}
+}
+
+// @Summary Свои списки фильмов [Пользователи]
+// @Description Хочу посмотреть, просмотрено, избранное и собственные списки. Системные списки создаются автоматически
+// @Tags list
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.UserList "Списки пользователя"
+// @Router /http/v1/list [get]
+func (u *userListHandler) GetMy(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	lists, err := u.UserListUseCase.GetByUser(req.Context(), user.Id)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, lists)
+	return nil
+}
+
+// @Summary Создание своего списка [Пользователи]
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param reg body appDto.CreateUserListUseCaseDto true "Название списка и открыт ли он по ссылке"
+// @Success 200 {object} model.UserList "Созданный список"
+// @Router /http/v1/list [post]
+func (u *userListHandler) Create(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.CreateUserListUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	list, err := u.UserListUseCase.Create(req.Context(), user.Id, body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, list)
+	return nil
+}
+
+// @Summary Изменение списка [Пользователи]
+// @Description Переименование и управление ссылкой: shared=true выдает shareSlug, false закрывает доступ
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param reg body appDto.UpdateUserListUseCaseDto true "Новые данные списка"
+// @Success 200 {object} model.UserList "Измененный список"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 403 {object} appErrors.ResponseError "Чужой список"
+// @Failure 404 {object} appErrors.ResponseError "Список не найден"
+// @Router /http/v1/list [put]
+func (u *userListHandler) Update(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.UpdateUserListUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	list, err := u.UserListUseCase.Update(req.Context(), user.Id, body)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, list)
+	return nil
+}
+
+// @Summary Удаление своего списка [Пользователи]
+// @Description Системные списки удалить нельзя, ничего ответом не возвращает
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param id query string true "id списка"
+// @Failure 403 {object} appErrors.ResponseError "Чужой список"
+// @Failure 404 {object} appErrors.ResponseError "Список не найден"
+// @Failure 422 {object} appErrors.ResponseError "Системный список"
+// @Router /http/v1/list [delete]
+func (u *userListHandler) Delete(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	id := req.URL.Query().Get("id")
+	if id == "" {
+		return appErrors.BadRequest("")
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return appErrors.NotFound("")
+	}
+
+	return u.UserListUseCase.Delete(req.Context(), user.Id, id)
+}
+
+// @Summary Фильмы своего списка [Пользователи]
+// @Description Фильмы в порядке, заданном пользователем
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param id query string true "id списка"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во фильмов на странице"
+// @Success 200 {object} appDto.UserListFilmsResult "Список и его фильмы"
+// @Failure 403 {object} appErrors.ResponseError "Чужой список"
+// @Failure 404 {object} appErrors.ResponseError "Список не найден"
+// @Router /http/v1/list/films [get]
+func (u *userListHandler) GetFilms(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	query := req.URL.Query()
+	id := query.Get("id")
+	if id == "" {
+		return appErrors.BadRequest("")
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return appErrors.NotFound("")
+	}
+	pQuery := domainQuery.NewPageQuery()
+	if err := parsePage(query, &pQuery.CurrentPage, &pQuery.PageCount); err != nil {
+		return err
+	}
+
+	result, err := u.UserListUseCase.GetFilms(req.Context(), user.Id, id, *pQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
+
+// @Summary Добавление фильма в список [Пользователи]
+// @Description Фильм добавляется в конец списка, повторное добавление ничего не меняет
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param reg body appDto.UserListFilmUseCaseDto true "id списка и фильма"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Список или фильм не найден"
+// @Router /http/v1/list/add-film [post]
+func (u *userListHandler) AddFilm(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.UserListFilmUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	return u.UserListUseCase.AddFilm(req.Context(), user.Id, body)
+}
+
+// @Summary Удаление фильма из списка [Пользователи]
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param reg body appDto.UserListFilmUseCaseDto true "id списка и фильма"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Фильма нет в списке"
+// @Router /http/v1/list/remove-film [post]
+func (u *userListHandler) RemoveFilm(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.UserListFilmUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	return u.UserListUseCase.RemoveFilm(req.Context(), user.Id, body)
+}
+
+// @Summary Перемещение фильма в списке [Пользователи]
+// @Description Ставит фильм на позицию position (с нуля), позиция за концом списка означает последнее место
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param reg body appDto.MoveUserListFilmUseCaseDto true "id списка, фильма и новая позиция"
+// @Failure 400 {object} appErrors.ResponseError "Ошибка 400"
+// @Failure 404 {object} appErrors.ResponseError "Фильма нет в списке"
+// @Router /http/v1/list/move-film [post]
+func (u *userListHandler) MoveFilm(res http.ResponseWriter, req *http.Request) error {
+	user, ok := middleware.UserFromContext(req.Context())
+	if !ok {
+		return appErrors.Unauthorized(constants.Unauthorized)
+	}
+
+	var body appDto.MoveUserListFilmUseCaseDto
+	err := httpUtils.BodyJson(req, &body)
+	if err != nil {
+		return appErrors.BadRequest("")
+	}
+	defer func() {
+		_ = req.Body.Close()
+	}()
+
+	return u.UserListUseCase.MoveFilm(req.Context(), user.Id, body)
+}
+
+// @Summary Открытый список по ссылке
+// @Description Доступно без авторизации, если владелец открыл список
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param slug path string true "shareSlug списка"
+// @Param page query string false "текущая страница"
+// @Param page-count query string false "кол-во фильмов на странице"
+// @Success 200 {object} appDto.UserListFilmsResult "Список и его фильмы"
+// @Failure 404 {object} appErrors.ResponseError "Список не найден или закрыт"
+// @Router /http/v1/list/shared/{slug} [get]
+func (u *userListHandler) GetShared(res http.ResponseWriter, req *http.Request) error {
+	slug := strings.TrimPrefix(req.URL.Path, "/http/v1/list/shared/")
+	if slug == "" || strings.Contains(slug, "/") {
+		return appErrors.NotFound("")
+	}
+
+	query := req.URL.Query()
+	pQuery := domainQuery.NewPageQuery()
+	if err := parsePage(query, &pQuery.CurrentPage, &pQuery.PageCount); err != nil {
+		return err
+	}
+
+	result, err := u.UserListUseCase.GetShared(req.Context(), slug, *pQuery)
+	if err != nil {
+		return err
+	}
+
+	httpUtils.SendJson(res, http.StatusOK, result)
+	return nil
+}
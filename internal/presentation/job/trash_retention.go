@@ -0,0 +1,36 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	trashUseCase "github.com/OddEer0/vk-filmoteka/internal/app/usecases/trash_usecase"
+	"github.com/OddEer0/vk-filmoteka/internal/infrastructure/config"
+)
+
+// TrashRetention сразу и затем раз в PurgeInterval удаляет навсегда то, что лежит в корзине дольше Retention.
+// Работает до отмены ctx, ошибка очистки только логируется и повторяется на следующем тике
+func TrashRetention(ctx context.Context, logger *slog.Logger, useCase trashUseCase.TrashUseCase, cfg config.Trash) {
+	if cfg.Retention <= 0 || cfg.PurgeInterval <= 0 {
+		logger.Info("trash retention disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		result, err := useCase.PurgeExpired(ctx, cfg.Retention)
+		if err != nil {
+			logger.Error("trash retention failed", slog.String("error", err.Error()))
+		} else if result.Films > 0 || result.Actors > 0 {
+			logger.Info("trash purged", slog.Int("films", result.Films), slog.Int("actors", result.Actors))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
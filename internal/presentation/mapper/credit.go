@@ -0,0 +1,61 @@
+package mapper
+
+import (
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	"github.com/OddEer0/vk-filmoteka/internal/domain/model"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/dto"
+)
+
+// AddFilmToActorDtoToCredits filmIds превращаются в роль actor с billing order по порядку
+func AddFilmToActorDtoToCredits(body dto.AddFilmToActorDto) []*model.Credit {
+	return actorCredits(body.ActorId, body.FilmIds, body.Credits)
+}
+
+func SetActorFilmsDtoToCredits(actorId string, body dto.SetActorFilmsDto) []*model.Credit {
+	return actorCredits(actorId, body.FilmIds, body.Credits)
+}
+
+// SetCastDtoToCredits actorIds превращаются в роль actor с billing order по порядку
+func SetCastDtoToCredits(filmId string, body dto.SetCastDto) []*model.Credit {
+	credits := make([]*model.Credit, 0, len(body.ActorIds)+len(body.Credits))
+	for i, actorId := range body.ActorIds {
+		credits = append(credits, &model.Credit{
+			ActorId:      actorId,
+			FilmId:       filmId,
+			Role:         constants.CreditActor,
+			BillingOrder: i,
+		})
+	}
+	for _, credit := range body.Credits {
+		credits = append(credits, &model.Credit{
+			ActorId:      credit.ActorId,
+			FilmId:       filmId,
+			Role:         credit.Role,
+			Character:    credit.Character,
+			BillingOrder: credit.BillingOrder,
+		})
+	}
+	return credits
+}
+
+func actorCredits(actorId string, filmIds []string, creditDtos []dto.CreditDto) []*model.Credit {
+	credits := make([]*model.Credit, 0, len(filmIds)+len(creditDtos))
+	for i, filmId := range filmIds {
+		credits = append(credits, &model.Credit{
+			ActorId:      actorId,
+			FilmId:       filmId,
+			Role:         constants.CreditActor,
+			BillingOrder: i,
+		})
+	}
+	for _, credit := range creditDtos {
+		credits = append(credits, &model.Credit{
+			ActorId:      actorId,
+			FilmId:       credit.FilmId,
+			Role:         credit.Role,
+			Character:    credit.Character,
+			BillingOrder: credit.BillingOrder,
+		})
+	}
+	return credits
+}
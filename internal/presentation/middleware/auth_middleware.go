@@ -10,6 +10,9 @@ import (
 	"slices"
 )
 
+// UserContextKey ключ, под которым AuthRoleMiddleware кладет *tokenService.JwtUserData в контекст
+const UserContextKey = tokenService.UserContextKey
+
 func AuthRoleMiddleware(roles ...string) func(appErrors.AppHandlerFunc) appErrors.AppHandlerFunc {
 	return func(next appErrors.AppHandlerFunc) appErrors.AppHandlerFunc {
 		return func(res http.ResponseWriter, req *http.Request) error {
@@ -33,11 +36,15 @@ func AuthRoleMiddleware(roles ...string) func(appErrors.AppHandlerFunc) appError
 					return appErrors.Unauthorized("")
 				}
 
-				ctx := context.WithValue(req.Context(), "user", &userData)
-				req = req.WithContext(ctx)
+				req = req.WithContext(tokenService.WithUser(req.Context(), &userData))
 			}
 
 			return next(res, req)
 		}
 	}
 }
+
+// UserFromContext данные пользователя, прошедшего AuthRoleMiddleware
+func UserFromContext(ctx context.Context) (*tokenService.JwtUserData, bool) {
+	return tokenService.UserFromContext(ctx)
+}
@@ -25,6 +25,18 @@ func HttpV1Router(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
 			return HttpV1RouterActor(appHandler)(res, req)
 		case strings.HasPrefix(path, "/film"):
 			return HttpV1RouterFilm(appHandler)(res, req)
+		case strings.HasPrefix(path, "/genre"):
+			return HttpV1RouterGenre(appHandler)(res, req)
+		case strings.HasPrefix(path, "/rating"):
+			return HttpV1RouterRating(appHandler)(res, req)
+		case strings.HasPrefix(path, "/review"):
+			return HttpV1RouterReview(appHandler)(res, req)
+		case strings.HasPrefix(path, "/list"):
+			return HttpV1RouterUserList(appHandler)(res, req)
+		case path == "/audit" && req.Method == http.MethodGet:
+			return middleware.AuthRoleMiddleware(constants.AdminRole)(appHandler.AuditHandler.GetByQuery)(res, req)
+		case path == "/suggest" && req.Method == http.MethodGet:
+			return appHandler.SuggestHandler.Suggest(res, req)
 		default:
 			http.NotFound(res, req)
 		}
@@ -65,6 +77,8 @@ func HttpV1RouterActor(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
 			return adminMiddleware(appHandler.ActorHandler.Create)(res, req)
 		case http.MethodPut == req.Method:
 			return adminMiddleware(appHandler.ActorHandler.Update)(res, req)
+		case http.MethodPatch == req.Method:
+			return adminMiddleware(appHandler.ActorHandler.Patch)(res, req)
 		case http.MethodDelete == req.Method:
 			return adminMiddleware(appHandler.ActorHandler.Delete)(res, req)
 		default:
@@ -82,12 +96,16 @@ func HttpV1RouterFilm(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
 		switch {
 		case path == "/search" && http.MethodGet == req.Method:
 			return appHandler.FilmHandler.SearchByNameAndActorName(res, req)
+		case strings.HasSuffix(path, "/reviews") && http.MethodGet == req.Method:
+			return appHandler.ReviewHandler.GetByFilm(res, req)
 		case http.MethodGet == req.Method:
 			return appHandler.FilmHandler.GetByQuery(res, req)
 		case http.MethodPost == req.Method:
 			return adminMiddleware(appHandler.FilmHandler.Create)(res, req)
 		case http.MethodPut == req.Method:
 			return adminMiddleware(appHandler.FilmHandler.Update)(res, req)
+		case http.MethodPatch == req.Method:
+			return adminMiddleware(appHandler.FilmHandler.Patch)(res, req)
 		case http.MethodDelete == req.Method:
 			return adminMiddleware(appHandler.FilmHandler.Delete)(res, req)
 		default:
@@ -96,3 +114,112 @@ func HttpV1RouterFilm(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
 		return nil
 	}
 }
+
+func HttpV1RouterGenre(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) error {
+		path := strings.TrimPrefix(req.URL.Path, HttpV1Prefix+"/genre")
+
+		adminMiddleware := middleware.AuthRoleMiddleware(constants.AdminRole)
+		switch {
+		case http.MethodPost == req.Method && path == "/add-film":
+			return adminMiddleware(appHandler.GenreHandler.AddFilm)(res, req)
+		case http.MethodPost == req.Method && path == "/remove-film":
+			return adminMiddleware(appHandler.GenreHandler.RemoveFilm)(res, req)
+		case http.MethodGet == req.Method:
+			return appHandler.GenreHandler.GetAll(res, req)
+		case http.MethodPost == req.Method:
+			return adminMiddleware(appHandler.GenreHandler.Create)(res, req)
+		case http.MethodPut == req.Method:
+			return adminMiddleware(appHandler.GenreHandler.Update)(res, req)
+		case http.MethodDelete == req.Method:
+			return adminMiddleware(appHandler.GenreHandler.Delete)(res, req)
+		default:
+			http.NotFound(res, req)
+		}
+		return nil
+	}
+}
+
+func HttpV1RouterRating(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) error {
+		path := strings.TrimPrefix(req.URL.Path, HttpV1Prefix+"/rating")
+		if path != "" {
+			http.NotFound(res, req)
+			return nil
+		}
+
+		userMiddleware := middleware.AuthRoleMiddleware(constants.UserRole, constants.AdminRole)
+		switch req.Method {
+		case http.MethodGet:
+			return userMiddleware(appHandler.RatingHandler.Get)(res, req)
+		case http.MethodPost:
+			return userMiddleware(appHandler.RatingHandler.Rate)(res, req)
+		case http.MethodPut:
+			return userMiddleware(appHandler.RatingHandler.Change)(res, req)
+		case http.MethodDelete:
+			return userMiddleware(appHandler.RatingHandler.Withdraw)(res, req)
+		default:
+			http.NotFound(res, req)
+		}
+		return nil
+	}
+}
+
+func HttpV1RouterReview(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) error {
+		path := strings.TrimPrefix(req.URL.Path, HttpV1Prefix+"/review")
+
+		adminMiddleware := middleware.AuthRoleMiddleware(constants.AdminRole)
+		userMiddleware := middleware.AuthRoleMiddleware(constants.UserRole, constants.AdminRole)
+		switch {
+		case http.MethodGet == req.Method && path == "/queue":
+			return adminMiddleware(appHandler.ReviewHandler.GetQueue)(res, req)
+		case http.MethodPost == req.Method && path == "/moderate":
+			return adminMiddleware(appHandler.ReviewHandler.Moderate)(res, req)
+		case path != "":
+			http.NotFound(res, req)
+		case http.MethodPost == req.Method:
+			return userMiddleware(appHandler.ReviewHandler.Create)(res, req)
+		case http.MethodPut == req.Method:
+			return userMiddleware(appHandler.ReviewHandler.Update)(res, req)
+		case http.MethodDelete == req.Method:
+			return userMiddleware(appHandler.ReviewHandler.Delete)(res, req)
+		default:
+			http.NotFound(res, req)
+		}
+		return nil
+	}
+}
+
+func HttpV1RouterUserList(appHandler *httpv1.AppHandler) appErrors.AppHandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) error {
+		path := strings.TrimPrefix(req.URL.Path, HttpV1Prefix+"/list")
+
+		userMiddleware := middleware.AuthRoleMiddleware(constants.UserRole, constants.AdminRole)
+		switch {
+		case http.MethodGet == req.Method && strings.HasPrefix(path, "/shared/"):
+			return appHandler.UserListHandler.GetShared(res, req)
+		case http.MethodGet == req.Method && path == "/films":
+			return userMiddleware(appHandler.UserListHandler.GetFilms)(res, req)
+		case http.MethodPost == req.Method && path == "/add-film":
+			return userMiddleware(appHandler.UserListHandler.AddFilm)(res, req)
+		case http.MethodPost == req.Method && path == "/remove-film":
+			return userMiddleware(appHandler.UserListHandler.RemoveFilm)(res, req)
+		case http.MethodPost == req.Method && path == "/move-film":
+			return userMiddleware(appHandler.UserListHandler.MoveFilm)(res, req)
+		case path != "":
+			http.NotFound(res, req)
+		case http.MethodGet == req.Method:
+			return userMiddleware(appHandler.UserListHandler.GetMy)(res, req)
+		case http.MethodPost == req.Method:
+			return userMiddleware(appHandler.UserListHandler.Create)(res, req)
+		case http.MethodPut == req.Method:
+			return userMiddleware(appHandler.UserListHandler.Update)(res, req)
+		case http.MethodDelete == req.Method:
+			return userMiddleware(appHandler.UserListHandler.Delete)(res, req)
+		default:
+			http.NotFound(res, req)
+		}
+		return nil
+	}
+}
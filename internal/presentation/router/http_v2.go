@@ -0,0 +1,98 @@
+package router
+
+import (
+	"github.com/OddEer0/vk-filmoteka/internal/common/constants"
+	appErrors "github.com/OddEer0/vk-filmoteka/internal/common/lib/app_errors"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/handlers/httpv1"
+	"github.com/OddEer0/vk-filmoteka/internal/presentation/middleware"
+	"net/http"
+)
+
+const (
+	HttpV2Prefix = "/http/v2"
+)
+
+// HttpV2Router ресурсы с id в пути на шаблонах ServeMux, 404 и 405 с заголовком Allow отдаются как ошибки API.
+// wrap превращает AppHandlerFunc в обычный обработчик с ответом ошибки
+func HttpV2Router(appHandler *httpv1.AppHandler, wrap func(appErrors.AppHandlerFunc) http.HandlerFunc) http.Handler {
+	mux := http.NewServeMux()
+	adminMiddleware := middleware.AuthRoleMiddleware(constants.AdminRole)
+
+	mux.Handle("POST /films", wrap(adminMiddleware(appHandler.FilmHandler.CreateWithCast)))
+	mux.Handle("GET /films/{id}", wrap(appHandler.FilmHandler.GetById))
+	mux.Handle("PUT /films/{id}", wrap(adminMiddleware(appHandler.FilmHandler.UpdateById)))
+	mux.Handle("PATCH /films/{id}", wrap(adminMiddleware(appHandler.FilmHandler.PatchById)))
+	mux.Handle("DELETE /films/{id}", wrap(adminMiddleware(appHandler.FilmHandler.DeleteById)))
+	mux.Handle("GET /films/{id}/actors", wrap(appHandler.ActorHandler.GetByFilm))
+	mux.Handle("PUT /films/{id}/actors", wrap(adminMiddleware(appHandler.FilmHandler.SetCast)))
+	mux.Handle("DELETE /films/{id}/actors/{actorId}", wrap(adminMiddleware(appHandler.FilmHandler.RemoveActor)))
+
+	mux.Handle("GET /actors/{id}", wrap(appHandler.ActorHandler.GetById))
+	mux.Handle("PUT /actors/{id}", wrap(adminMiddleware(appHandler.ActorHandler.UpdateById)))
+	mux.Handle("PATCH /actors/{id}", wrap(adminMiddleware(appHandler.ActorHandler.PatchById)))
+	mux.Handle("DELETE /actors/{id}", wrap(adminMiddleware(appHandler.ActorHandler.DeleteById)))
+	mux.Handle("GET /actors/{id}/films", wrap(appHandler.FilmHandler.GetByActor))
+	mux.Handle("PUT /actors/{id}/films", wrap(adminMiddleware(appHandler.ActorHandler.SetFilms)))
+	mux.Handle("DELETE /actors/{id}/films/{filmId}", wrap(adminMiddleware(appHandler.ActorHandler.RemoveFilm)))
+
+	mux.Handle("GET /films/{id}/revisions", wrap(adminMiddleware(appHandler.RevisionHandler.GetFilmRevisions)))
+	mux.Handle("GET /films/{id}/revisions/diff", wrap(adminMiddleware(appHandler.RevisionHandler.DiffFilmRevisions)))
+	mux.Handle("GET /films/{id}/revisions/{number}", wrap(adminMiddleware(appHandler.RevisionHandler.GetFilmRevision)))
+	mux.Handle("POST /films/{id}/revisions/{number}/revert", wrap(adminMiddleware(appHandler.RevisionHandler.RevertFilm)))
+	mux.Handle("GET /actors/{id}/revisions", wrap(adminMiddleware(appHandler.RevisionHandler.GetActorRevisions)))
+	mux.Handle("GET /actors/{id}/revisions/diff", wrap(adminMiddleware(appHandler.RevisionHandler.DiffActorRevisions)))
+	mux.Handle("GET /actors/{id}/revisions/{number}", wrap(adminMiddleware(appHandler.RevisionHandler.GetActorRevision)))
+	mux.Handle("POST /actors/{id}/revisions/{number}/revert", wrap(adminMiddleware(appHandler.RevisionHandler.RevertActor)))
+
+	mux.Handle("GET /trash/films", wrap(adminMiddleware(appHandler.TrashHandler.GetFilms)))
+	mux.Handle("POST /trash/films/{id}/restore", wrap(adminMiddleware(appHandler.TrashHandler.RestoreFilm)))
+	mux.Handle("DELETE /trash/films/{id}", wrap(adminMiddleware(appHandler.TrashHandler.PurgeFilm)))
+	mux.Handle("GET /trash/actors", wrap(adminMiddleware(appHandler.TrashHandler.GetActors)))
+	mux.Handle("POST /trash/actors/{id}/restore", wrap(adminMiddleware(appHandler.TrashHandler.RestoreActor)))
+	mux.Handle("DELETE /trash/actors/{id}", wrap(adminMiddleware(appHandler.TrashHandler.PurgeActor)))
+
+	mux.Handle("POST /import/{kind}", wrap(adminMiddleware(appHandler.ImportHandler.Import)))
+	mux.Handle("GET /export/films", wrap(adminMiddleware(appHandler.ExportHandler.Films)))
+	mux.Handle("GET /export/actors", wrap(adminMiddleware(appHandler.ExportHandler.Actors)))
+	mux.Handle("GET /export/credits", wrap(adminMiddleware(appHandler.ExportHandler.Credits)))
+
+	mux.Handle("GET /external/{source}/{externalId}/film", wrap(appHandler.FilmHandler.GetByExternalId))
+	mux.Handle("GET /external/{source}/{externalId}/actor", wrap(appHandler.ActorHandler.GetByExternalId))
+
+	// без подходящего шаблона ServeMux отвечает текстом, вместо него отдается ошибка API с тем же статусом и Allow
+	unmatched := wrap(func(res http.ResponseWriter, req *http.Request) error {
+		status := &muxStatus{header: http.Header{}}
+		mux.ServeHTTP(status, req)
+		if status.code == http.StatusMethodNotAllowed {
+			res.Header().Set("Allow", status.header.Get("Allow"))
+			return appErrors.MethodNotAllowed("")
+		}
+		return appErrors.NotFound("")
+	})
+
+	return http.StripPrefix(HttpV2Prefix, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if _, pattern := mux.Handler(req); pattern == "" {
+			unmatched(res, req)
+			return
+		}
+		mux.ServeHTTP(res, req)
+	}))
+}
+
+// muxStatus ответ ServeMux без подходящего шаблона, нужны только статус и заголовок Allow
+type muxStatus struct {
+	header http.Header
+	code   int
+}
+
+func (m *muxStatus) Header() http.Header {
+	return m.header
+}
+
+func (m *muxStatus) Write(body []byte) (int, error) {
+	return len(body), nil
+}
+
+func (m *muxStatus) WriteHeader(code int) {
+	m.code = code
+}
@@ -22,6 +22,7 @@ func NewAppRouter(log *slog.Logger, appHandler *httpv1.AppHandler) *http.ServeMu
 		}
 		return nil
 	}))
+	mux.Handle(HttpV2Prefix+"/", HttpV2Router(appHandler, middleware))
 
 	return mux
 }
@@ -0,0 +1,58 @@
+package httpUtils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const IfMatchError = "error If-Match header %v"
+
+// ETag сильный валидатор из версии записи. state - данные ответа, которые меняются без роста версии
+// (например оценка фильма): они добавляются хешем после версии и не участвуют в проверке If-Match
+func ETag(version int, state ...string) string {
+	if len(state) == 0 {
+		return `"` + strconv.Itoa(version) + `"`
+	}
+	hash := fnv.New32a()
+	for _, item := range state {
+		_, _ = hash.Write([]byte(item))
+		_, _ = hash.Write([]byte{0})
+	}
+	return fmt.Sprintf(`"%d-%08x"`, version, hash.Sum32())
+}
+
+// IfMatchVersion версия из If-Match. Без заголовка и для * возвращается 0, то есть обновление без проверки.
+// Ожидается один сильный ETag, слабый или список тегов ошибка - их нельзя проверить одной версией
+func IfMatchVersion(req *http.Request) (int, error) {
+	value := strings.TrimSpace(req.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, fmt.Errorf(IfMatchError, value)
+	}
+	tag, _, _ := strings.Cut(value[1:len(value)-1], "-")
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf(IfMatchError, value)
+	}
+	return version, nil
+}
+
+// NotModified совпадает ли etag с одним из тегов If-None-Match. Сравнение слабое, как требует RFC 9110
+func NotModified(req *http.Request, etag string) bool {
+	value := req.Header.Get("If-None-Match")
+	if value == "" {
+		return false
+	}
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -33,6 +33,20 @@ func BodyJson(req *http.Request, body interface{}) error {
 	return nil
 }
 
+// DecodeJson читает тело без валидации, когда проверку делает агрегат после слияния с текущими данными
+func DecodeJson(req *http.Request, body interface{}) error {
+	byteBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		return fmt.Errorf(ReadBodyError, err)
+	}
+
+	if err = json.Unmarshal(byteBody, body); err != nil {
+		return fmt.Errorf(UnmarshalError, err)
+	}
+
+	return nil
+}
+
 func SendJson(res http.ResponseWriter, statusCode int, data interface{}) {
 	res.WriteHeader(statusCode)
 	encoder := json.NewEncoder(res)
@@ -0,0 +1,79 @@
+package httpUtils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	MergePatchTargetError = "error merge patch target %v"
+)
+
+// IsMergePatch тело PATCH должно быть merge patch документом, application/json и пустой заголовок тоже принимаются
+func IsMergePatch(req *http.Request) bool {
+	contentType := req.Header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == MergePatchContentType || mediaType == "application/json")
+}
+
+// MergePatchJson накладывает тело запроса на target по RFC 7396: null удаляет поле, объекты сливаются рекурсивно,
+// остальные значения заменяются целиком. target пересобирается из результата, удаленные поля становятся нулевыми
+func MergePatchJson(req *http.Request, target interface{}) error {
+	byteBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		return fmt.Errorf(ReadBodyError, err)
+	}
+	var patch interface{}
+	if err = json.Unmarshal(byteBody, &patch); err != nil {
+		return fmt.Errorf(UnmarshalError, err)
+	}
+
+	byteTarget, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf(MergePatchTargetError, err)
+	}
+	var document interface{}
+	if err = json.Unmarshal(byteTarget, &document); err != nil {
+		return fmt.Errorf(MergePatchTargetError, err)
+	}
+
+	merged, err := json.Marshal(MergePatch(document, patch))
+	if err != nil {
+		return fmt.Errorf(MergePatchTargetError, err)
+	}
+	value := reflect.ValueOf(target).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	if err = json.Unmarshal(merged, target); err != nil {
+		return fmt.Errorf(UnmarshalError, err)
+	}
+
+	return nil
+}
+
+// MergePatch алгоритм MergePatch из RFC 7396 над значениями после json.Unmarshal в interface{}
+func MergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{}, len(patchObject))
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = MergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
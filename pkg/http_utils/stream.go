@@ -0,0 +1,196 @@
+package httpUtils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+const (
+	StreamJson   = "json"
+	StreamNdjson = "ndjson"
+	StreamCsv    = "csv"
+)
+
+// StreamContentTypes Content-Type ответа для каждого формата StreamWriter
+var StreamContentTypes = map[string]string{
+	StreamJson:   "application/json",
+	StreamNdjson: "application/x-ndjson",
+	StreamCsv:    "text/csv",
+}
+
+type (
+	// StreamWriter пишет записи по одной, не собирая документ в памяти, в отличие от SendJson.
+	// JSON - один массив объектов, NDJSON - объект на строку, CSV - заголовок из колонок и строка на запись
+	StreamWriter interface {
+		// Write значения записи в порядке колонок, nil указатель - null в JSON и пустая ячейка в CSV
+		Write(values ...interface{}) error
+		// Close дописывает окончание документа и отправляет остаток буфера
+		Close() error
+		// Started true, если в w уже ушли данные и ошибку отдельным ответом не отправить
+		Started() bool
+	}
+
+	streamWriter struct {
+		format  string
+		columns []string
+		out     *countingWriter
+		buf     *bufio.Writer
+		csv     *csv.Writer
+		count   int
+	}
+
+	countingWriter struct {
+		w io.Writer
+		n int64
+	}
+)
+
+func NewStreamWriter(w io.Writer, format string, columns []string) (StreamWriter, error) {
+	if _, ok := StreamContentTypes[format]; !ok {
+		return nil, fmt.Errorf("unknown stream format %s", format)
+	}
+	out := &countingWriter{w: w}
+	result := &streamWriter{format: format, columns: columns, out: out, buf: bufio.NewWriterSize(out, 32*1024)}
+	if format == StreamCsv {
+		result.csv = csv.NewWriter(result.buf)
+	}
+	return result, nil
+}
+
+// SetStreamHeaders заголовки ответа с выгрузкой, браузер сохраняет ее в файл filename
+func SetStreamHeaders(res http.ResponseWriter, format string, filename string) {
+	res.Header().Set("Content-Type", StreamContentTypes[format])
+	res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+}
+
+func (s *streamWriter) Write(values ...interface{}) error {
+	if len(values) != len(s.columns) {
+		return fmt.Errorf("expected %d values, got %d", len(s.columns), len(values))
+	}
+	if s.count == 0 {
+		if err := s.begin(); err != nil {
+			return err
+		}
+	}
+	s.count++
+
+	if s.format == StreamCsv {
+		record := make([]string, 0, len(values))
+		for _, value := range values {
+			record = append(record, csvValue(value))
+		}
+		return s.csv.Write(record)
+	}
+
+	if s.format == StreamJson && s.count > 1 {
+		if _, err := s.buf.WriteString(",\n"); err != nil {
+			return err
+		}
+	}
+	if err := s.writeObject(values); err != nil {
+		return err
+	}
+	if s.format == StreamNdjson {
+		return s.buf.WriteByte('\n')
+	}
+	return nil
+}
+
+func (s *streamWriter) Close() error {
+	if s.count == 0 {
+		if err := s.begin(); err != nil {
+			return err
+		}
+	}
+	switch s.format {
+	case StreamCsv:
+		s.csv.Flush()
+		if err := s.csv.Error(); err != nil {
+			return err
+		}
+	case StreamJson:
+		if _, err := s.buf.WriteString("\n]\n"); err != nil {
+			return err
+		}
+	}
+	if err := s.buf.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := s.out.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (s *streamWriter) Started() bool {
+	return s.out.n > 0
+}
+
+// begin начало документа: открывающая скобка массива или заголовок CSV
+func (s *streamWriter) begin() error {
+	switch s.format {
+	case StreamJson:
+		_, err := s.buf.WriteString("[\n")
+		return err
+	case StreamCsv:
+		return s.csv.Write(s.columns)
+	}
+	return nil
+}
+
+// writeObject объект с полями в порядке колонок
+func (s *streamWriter) writeObject(values []interface{}) error {
+	if err := s.buf.WriteByte('{'); err != nil {
+		return err
+	}
+	for i, value := range values {
+		if i > 0 {
+			if err := s.buf.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		key, err := json.Marshal(s.columns[i])
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(s.buf, "%s:%s", key, data); err != nil {
+			return err
+		}
+	}
+	return s.buf.WriteByte('}')
+}
+
+func csvValue(value interface{}) string {
+	reflected := reflect.ValueOf(value)
+	if !reflected.IsValid() || (reflected.Kind() == reflect.Pointer && reflected.IsNil()) {
+		return ""
+	}
+	if reflected.Kind() == reflect.Pointer {
+		reflected = reflected.Elem()
+	}
+	switch reflected.Kind() {
+	case reflect.String:
+		return reflected.String()
+	case reflect.Float32:
+		return strconv.FormatFloat(reflected.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(reflected.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(reflected.Interface())
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}